@@ -17,6 +17,8 @@ require (
 	golang.org/x/crypto v0.39.0
 )
 
+require github.com/wycliff-ochieng/common_packages v0.0.0-00010101000000-000000000000
+
 require (
 	github.com/cespare/xxhash/v2 v2.3.0 // indirect
 	github.com/davecgh/go-spew v1.1.1 // indirect
@@ -35,3 +37,5 @@ require (
 	google.golang.org/genproto/googleapis/api v0.0.0-20250707201910-8d1bb00bc6a7 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
+
+replace github.com/wycliff-ochieng/common_packages => ../common_packages
@@ -0,0 +1,7 @@
+-- +goose Up
+-- platform admins, granted by inserting into user_roles. user-service lets them suspend and
+-- reactivate users
+INSERT INTO roles (name) VALUES ('admin') ON CONFLICT (name) DO NOTHING;
+
+-- +goose Down
+DELETE FROM roles WHERE name = 'admin';
@@ -12,7 +12,7 @@ import (
 	internal "sports/authservice/internal/producer"
 	"sports/authservice/internal/service"
 
-	"github.com/google/uuid"
+	"github.com/wycliff-ochieng/common_packages/events"
 	//"github.com/google/uuid"
 )
 
@@ -45,13 +45,6 @@ type AuthenticationResponse struct {
 	RefreshToken string `json:"refreshToken"`
 }
 
-type UserCreatedEvent struct {
-	UserID    uuid.UUID `json:"userid"`
-	FirstName string    `json:"firstName"`
-	LastName  string    `json:"lastName"`
-	Email     string    `json:"email"`
-}
-
 func NewAuthHandler(l *log.Logger, as AuthService, p internal.KafkaProducer) *AuthHandler {
 	return &AuthHandler{
 		l:  l,
@@ -94,7 +87,7 @@ func (a *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 
 	//after successfull event creation ,create user created event
 
-	event := UserCreatedEvent{
+	event := events.UserCreated{
 		UserID:    user.UserID,
 		FirstName: user.FirstName,
 		LastName:  user.LastName,
@@ -2,16 +2,19 @@ package internal
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
 	"os"
 
 	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/wycliff-ochieng/common_packages/events"
 )
 
+// ProducerName is stamped on the envelope of every event this service publishes
+const ProducerName = "auth-service"
+
 type KafkaProducer interface {
-	PublishUserCreation(ctx context.Context, userData interface{}) error
+	PublishUserCreation(ctx context.Context, event events.Payload) error
 }
 
 type CreateUser struct {
@@ -42,11 +45,12 @@ func (c *CreateUser) DeliveryReportHandler() {
 	}
 }
 
-func (c *CreateUser) PublishUserCreation(ctx context.Context, userData interface{}) error {
+// PublishUserCreation validates the event and publishes it wrapped in the shared envelope
+func (c *CreateUser) PublishUserCreation(ctx context.Context, event events.Payload) error {
 
-	data, err := json.Marshal(userData)
+	data, err := events.Marshal(ProducerName, event)
 	if err != nil {
-		return fmt.Errorf("failed to marshal data: %s", err)
+		return fmt.Errorf("failed to marshal %s: %w", event.EventType(), err)
 	}
 
 	err = c.producer.Produce(&kafka.Message{
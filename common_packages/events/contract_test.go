@@ -0,0 +1,145 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// testdata/<type>.v<version>.json holds one message per event type exactly as producers send it.
+// Changing a payload struct without updating its fixture (and the version, when old consumers
+// cannot read the new shape) fails these tests
+func loadFixture(t *testing.T, eventType string) []byte {
+	t.Helper()
+	path := filepath.Join("testdata", fmt.Sprintf("%s.v%d.json", eventType, Version(eventType)))
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("missing contract fixture for %s: %v", eventType, err)
+	}
+	return data
+}
+
+func normalize(t *testing.T, data []byte) interface{} {
+	t.Helper()
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		t.Fatalf("fixture is not json: %v", err)
+	}
+	return v
+}
+
+func TestEveryEventMatchesItsContract(t *testing.T) {
+	for _, eventType := range Types() {
+		t.Run(eventType, func(t *testing.T) {
+			fixture := loadFixture(t, eventType)
+
+			env, payload, err := Unmarshal(fixture)
+			if err != nil {
+				t.Fatalf("consumer rejects the contract: %v", err)
+			}
+			if env.Type != eventType || payload.EventType() != eventType {
+				t.Fatalf("fixture type %s decoded as %s", env.Type, payload.EventType())
+			}
+
+			//the producer side must write exactly the fields of the fixture, no more, no less
+			produced, err := Marshal(env.Producer, payload)
+			if err != nil {
+				t.Fatalf("producer rejects the contract payload: %v", err)
+			}
+			var sent Envelope
+			if err := json.Unmarshal(produced, &sent); err != nil {
+				t.Fatal(err)
+			}
+			want := normalize(t, env.Payload)
+			got := normalize(t, sent.Payload)
+			if !reflect.DeepEqual(want, got) {
+				t.Errorf("payload drifted from the contract\nwant %s\ngot  %s", env.Payload, sent.Payload)
+			}
+
+			//the envelope fields are part of the contract too
+			var raw map[string]interface{}
+			if err := json.Unmarshal(produced, &raw); err != nil {
+				t.Fatal(err)
+			}
+			for _, key := range []string{"id", "type", "version", "occurredAt", "producer", "payload"} {
+				if _, ok := raw[key]; !ok {
+					t.Errorf("envelope is missing %q", key)
+				}
+			}
+			if len(raw) != 6 {
+				t.Errorf("envelope has unexpected fields: %v", raw)
+			}
+		})
+	}
+}
+
+func TestProduceValidatesPayload(t *testing.T) {
+	if _, err := Marshal("team-service", TeamRosterChanged{ChangeType: "MEMBER_VANISHED", TeamID: uuid.New(), UserID: uuid.New()}); !errors.Is(err, ErrInvalidPayload) {
+		t.Errorf("unknown change type: got %v, want ErrInvalidPayload", err)
+	}
+	if _, err := Marshal("user-service", UserProfileUpdated{}); !errors.Is(err, ErrInvalidPayload) {
+		t.Errorf("missing userId: got %v, want ErrInvalidPayload", err)
+	}
+}
+
+func TestConsumeRejectsBadMessages(t *testing.T) {
+	fixture := loadFixture(t, TypeUserDeleted)
+
+	edit := func(change func(m map[string]interface{})) []byte {
+		var m map[string]interface{}
+		if err := json.Unmarshal(fixture, &m); err != nil {
+			t.Fatal(err)
+		}
+		change(m)
+		out, _ := json.Marshal(m)
+		return out
+	}
+
+	cases := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{"bare uuid", []byte(`"` + uuid.New().String() + `"`), ErrInvalidEnvelope},
+		{"no id", edit(func(m map[string]interface{}) { delete(m, "id") }), ErrInvalidEnvelope},
+		{"no producer", edit(func(m map[string]interface{}) { delete(m, "producer") }), ErrInvalidEnvelope},
+		{"unknown type", edit(func(m map[string]interface{}) { m["type"] = "UserRenamed" }), ErrUnknownType},
+		{"newer version", edit(func(m map[string]interface{}) { m["version"] = Version(TypeUserDeleted) + 1 }), ErrUnsupportedVersion},
+		{"empty payload", edit(func(m map[string]interface{}) { m["payload"] = map[string]interface{}{} }), ErrInvalidPayload},
+		{"only unknown keys", edit(func(m map[string]interface{}) {
+			m["payload"] = map[string]interface{}{"deletedBy": uuid.New().String()}
+		}), ErrInvalidPayload},
+	}
+	for _, c := range cases {
+		if _, _, err := Unmarshal(c.data); !errors.Is(err, c.want) {
+			t.Errorf("%s: got %v, want %v", c.name, err, c.want)
+		}
+	}
+}
+
+func TestConsumeIgnoresUnknownFields(t *testing.T) {
+	//a producer that added an optional field must not break consumers built before it
+	var m map[string]interface{}
+	if err := json.Unmarshal(loadFixture(t, TypeTeamRosterChanged), &m); err != nil {
+		t.Fatal(err)
+	}
+	m["payload"].(map[string]interface{})["reason"] = "moved to the reserves"
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, payload, err := Unmarshal(data)
+	if err != nil {
+		t.Fatalf("unknown field rejected: %v", err)
+	}
+	if change := payload.(*TeamRosterChanged); change.ChangeType != RosterMemberRemoved {
+		t.Errorf("decoded change type %q", change.ChangeType)
+	}
+}
@@ -0,0 +1,172 @@
+// Package events holds the Kafka event contracts shared by the services. Every message is an
+// Envelope whose payload is one of the registered, versioned payload structs of this package
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidEnvelope    = errors.New("invalid event envelope")
+	ErrUnknownType        = errors.New("unknown event type")
+	ErrUnsupportedVersion = errors.New("unsupported event version")
+	ErrInvalidPayload     = errors.New("invalid event payload")
+)
+
+// Envelope wraps every event put on a topic
+type Envelope struct {
+	ID         uuid.UUID       `json:"id"`
+	Type       string          `json:"type"`
+	Version    int             `json:"version"`
+	OccurredAt time.Time       `json:"occurredAt"`
+	Producer   string          `json:"producer"`
+	Payload    json.RawMessage `json:"payload"`
+}
+
+// Payload is implemented by every event payload, Validate checks the fields consumers rely on
+type Payload interface {
+	EventType() string
+	Validate() error
+}
+
+// spec is the current version of an event type and a constructor for its payload
+type spec struct {
+	version int
+	new     func() Payload
+}
+
+// registry lists every event type, bump the version when a payload changes in a way old
+// consumers cannot read (a renamed or removed field, a new required field)
+var registry = map[string]spec{
+	TypeUserCreated:        {1, func() Payload { return &UserCreated{} }},
+	TypeUserProfileUpdated: {1, func() Payload { return &UserProfileUpdated{} }},
+	TypeUserDeleted:        {1, func() Payload { return &UserDeleted{} }},
+	TypeUserSuspended:      {1, func() Payload { return &UserSuspended{} }},
+	TypeUserReactivated:    {1, func() Payload { return &UserReactivated{} }},
+
+	TypeTeamUpdated:             {1, func() Payload { return &TeamUpdated{} }},
+	TypeTeamMemberJoined:        {1, func() Payload { return &TeamMemberJoined{} }},
+	TypeTeamRosterChanged:       {1, func() Payload { return &TeamRosterChanged{} }},
+	TypeTeamOrganizationChanged: {1, func() Payload { return &TeamOrganizationChanged{} }},
+	TypeTeamJoinRequested:       {1, func() Payload { return &TeamJoinRequested{} }},
+	TypeTeamJoinRequestDecided:  {1, func() Payload { return &TeamJoinRequestDecided{} }},
+	TypeTeamArchived:            {1, func() Payload { return &TeamArchived{} }},
+	TypeTeamRestored:            {1, func() Payload { return &TeamRestored{} }},
+	TypeTeamDeleted:             {1, func() Payload { return &TeamDeleted{} }},
+	TypeTeamCoachMissing:        {1, func() Payload { return &TeamCoachMissing{} }},
+	TypeTeamAnnouncementPosted:  {1, func() Payload { return &TeamAnnouncementPosted{} }},
+
+	TypeEventCreated:      {1, func() Payload { return &EventCreated{} }},
+	TypeAttendanceUpdated: {1, func() Payload { return &AttendanceUpdated{} }},
+	TypeEventCancelled:    {1, func() Payload { return &EventCancelled{} }},
+}
+
+// Types returns every registered event type
+func Types() []string {
+	types := make([]string, 0, len(registry))
+	for t := range registry {
+		types = append(types, t)
+	}
+	return types
+}
+
+// Version returns the current version of an event type, 0 when it is unknown
+func Version(eventType string) int {
+	return registry[eventType].version
+}
+
+// NewEnvelope validates p and wraps it for producer
+func NewEnvelope(producer string, p Payload) (*Envelope, error) {
+	s, ok := registry[p.EventType()]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownType, p.EventType())
+	}
+	if err := p.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalidPayload, p.EventType(), err)
+	}
+
+	data, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Envelope{
+		ID:         uuid.New(),
+		Type:       p.EventType(),
+		Version:    s.version,
+		OccurredAt: time.Now().UTC(),
+		Producer:   producer,
+		Payload:    data,
+	}, nil
+}
+
+// Marshal builds the message value for p
+func Marshal(producer string, p Payload) ([]byte, error) {
+	env, err := NewEnvelope(producer, p)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(env)
+}
+
+// Unmarshal decodes a message value into its envelope and typed payload, both validated.
+// Older versions of a type are accepted, newer ones fail with ErrUnsupportedVersion. Unknown
+// payload keys are ignored
+func Unmarshal(data []byte) (*Envelope, Payload, error) {
+	var env Envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidEnvelope, err)
+	}
+	if env.ID == uuid.Nil || env.Type == "" || env.Version < 1 || env.OccurredAt.IsZero() || env.Producer == "" || len(env.Payload) == 0 {
+		return nil, nil, fmt.Errorf("%w: missing id, type, version, occurredAt, producer or payload", ErrInvalidEnvelope)
+	}
+
+	s, ok := registry[env.Type]
+	if !ok {
+		return &env, nil, fmt.Errorf("%w: %s", ErrUnknownType, env.Type)
+	}
+	if env.Version > s.version {
+		return &env, nil, fmt.Errorf("%w: %s v%d, newest known is v%d", ErrUnsupportedVersion, env.Type, env.Version, s.version)
+	}
+
+	//keys the payload struct does not declare are ignored, so a producer can add an optional field
+	//before every consumer is redeployed
+	p := s.new()
+	if err := json.Unmarshal(env.Payload, p); err != nil {
+		return &env, nil, fmt.Errorf("%w: %s: %v", ErrInvalidPayload, env.Type, err)
+	}
+	if err := p.Validate(); err != nil {
+		return &env, nil, fmt.Errorf("%w: %s: %v", ErrInvalidPayload, env.Type, err)
+	}
+	return &env, p, nil
+}
+
+// field is a required payload field and whether it is set
+type field struct {
+	name string
+	set  bool
+}
+
+// required is a small helper for Validate, it names the first empty field
+func required(fields ...field) error {
+	for _, f := range fields {
+		if !f.set {
+			return fmt.Errorf("%s is required", f.name)
+		}
+	}
+	return nil
+}
+
+func oneOf(name string, value string, allowed ...string) error {
+	for _, a := range allowed {
+		if value == a {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s %q is not one of %v", name, value, allowed)
+}
@@ -0,0 +1,100 @@
+package events
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// scheduling events, produced by event-service onto event_events
+const (
+	TypeEventCreated      = "EventCreated"
+	TypeAttendanceUpdated = "AttendanceUpdated"
+	TypeEventCancelled    = "EventCancelled"
+)
+
+// RSVP statuses of an attendance row
+const (
+	RSVPPending  = "PENDING"
+	RSVPGoing    = "GOING"
+	RSVPNotGoing = "NOT_GOING"
+	RSVPMaybe    = "MAYBE"
+)
+
+// EventCreated is published once a team event and its attendance list are stored
+type EventCreated struct {
+	EventID   uuid.UUID `json:"eventId"`
+	TeamID    uuid.UUID `json:"teamId"`
+	Title     string    `json:"title"`
+	Kind      string    `json:"kind"` //GAME, TRAINING, MEETING...
+	Location  string    `json:"location"`
+	StartTime time.Time `json:"startTime"`
+	EndTime   time.Time `json:"endTime"`
+	CreatedBy uuid.UUID `json:"createdBy"`
+}
+
+func (EventCreated) EventType() string { return TypeEventCreated }
+
+func (e EventCreated) Validate() error {
+	return required(
+		field{"eventId", e.EventID != uuid.Nil},
+		field{"teamId", e.TeamID != uuid.Nil},
+		field{"title", e.Title != ""},
+		field{"startTime", !e.StartTime.IsZero()},
+	)
+}
+
+// AttendanceUpdated is published on every RSVP change. Override is set when someone other than the
+// member, a coach, answered for them. The counts are the event's totals after the change
+type AttendanceUpdated struct {
+	EventID        uuid.UUID `json:"eventId"`
+	TeamID         uuid.UUID `json:"teamId"`
+	UserID         uuid.UUID `json:"userId"`
+	Status         string    `json:"status"`
+	PreviousStatus string    `json:"previousStatus"`
+	Reason         string    `json:"reason,omitempty"`
+	UpdatedBy      uuid.UUID `json:"updatedBy"`
+	Override       bool      `json:"override"`
+	Going          int       `json:"going"`
+	NotGoing       int       `json:"notGoing"`
+	Maybe          int       `json:"maybe"`
+	Pending        int       `json:"pending"`
+}
+
+func (AttendanceUpdated) EventType() string { return TypeAttendanceUpdated }
+
+func (e AttendanceUpdated) Validate() error {
+	if err := required(
+		field{"eventId", e.EventID != uuid.Nil},
+		field{"teamId", e.TeamID != uuid.Nil},
+		field{"userId", e.UserID != uuid.Nil},
+		field{"updatedBy", e.UpdatedBy != uuid.Nil},
+	); err != nil {
+		return err
+	}
+	return oneOf("status", e.Status, RSVPGoing, RSVPNotGoing, RSVPMaybe, RSVPPending)
+}
+
+// EventCancelled is published when a team event, or one occurrence of a recurring one, is cancelled.
+// Attendees are the members on its attendance list who had not declined, so notifiers do not have
+// to call back for them
+type EventCancelled struct {
+	EventID     uuid.UUID   `json:"eventId"`
+	TeamID      uuid.UUID   `json:"teamId"`
+	Title       string      `json:"title"`
+	StartTime   time.Time   `json:"startTime"`
+	CancelledBy uuid.UUID   `json:"cancelledBy"`
+	Reason      string      `json:"reason,omitempty"`
+	Attendees   []uuid.UUID `json:"attendees"`
+}
+
+func (EventCancelled) EventType() string { return TypeEventCancelled }
+
+func (e EventCancelled) Validate() error {
+	return required(
+		field{"eventId", e.EventID != uuid.Nil},
+		field{"teamId", e.TeamID != uuid.Nil},
+		field{"startTime", !e.StartTime.IsZero()},
+		field{"cancelledBy", e.CancelledBy != uuid.Nil},
+	)
+}
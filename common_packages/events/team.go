@@ -0,0 +1,253 @@
+package events
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// team events, produced by team-service onto team_events
+const (
+	TypeTeamUpdated             = "TeamUpdated"
+	TypeTeamMemberJoined        = "TeamMemberJoined"
+	TypeTeamRosterChanged       = "TeamRosterChanged"
+	TypeTeamOrganizationChanged = "TeamOrganizationChanged"
+	TypeTeamJoinRequested       = "TeamJoinRequested"
+	TypeTeamJoinRequestDecided  = "TeamJoinRequestDecided"
+	TypeTeamArchived            = "TeamArchived"
+	TypeTeamRestored            = "TeamRestored"
+	TypeTeamDeleted             = "TeamDeleted"
+	TypeTeamCoachMissing        = "TeamCoachMissing"
+	TypeTeamAnnouncementPosted  = "TeamAnnouncementPosted"
+)
+
+// TeamRosterChanged change types
+const (
+	RosterMemberAdded   = "MEMBER_ADDED"
+	RosterRoleChanged   = "ROLE_CHANGED"
+	RosterMemberRemoved = "MEMBER_REMOVED"
+	RosterMemberLeft    = "MEMBER_LEFT"
+
+	//driven by user lifecycle events, ChangedBy is uuid.Nil
+	RosterAccountDeleted    = "ACCOUNT_DELETED"
+	RosterMemberSuspended   = "MEMBER_SUSPENDED"
+	RosterMemberReactivated = "MEMBER_REACTIVATED"
+	RosterCoachReassigned   = "COACH_REASSIGNED"
+)
+
+// TeamCoachMissing reasons
+const (
+	CoachMissingAccountDeleted = "ACCOUNT_DELETED"
+	CoachMissingSuspended      = "SUSPENDED"
+)
+
+// TeamUpdated carries the team details after an update
+type TeamUpdated struct {
+	TeamID      uuid.UUID `json:"teamId"`
+	Name        string    `json:"name"`
+	Sport       string    `json:"sport"`
+	Description string    `json:"description"`
+	UpdatedBy   uuid.UUID `json:"updatedBy"`
+}
+
+func (TeamUpdated) EventType() string { return TypeTeamUpdated }
+
+func (e TeamUpdated) Validate() error {
+	return required(
+		field{"teamId", e.TeamID != uuid.Nil},
+		field{"name", e.Name != ""},
+		field{"updatedBy", e.UpdatedBy != uuid.Nil},
+	)
+}
+
+// TeamMemberJoined is published when an invite or join code is redeemed
+type TeamMemberJoined struct {
+	TeamID   uuid.UUID `json:"teamId"`
+	UserID   uuid.UUID `json:"userId"`
+	Role     string    `json:"role"`
+	InviteID uuid.UUID `json:"inviteId"`
+	JoinedAt time.Time `json:"joinedAt"`
+}
+
+func (TeamMemberJoined) EventType() string { return TypeTeamMemberJoined }
+
+func (e TeamMemberJoined) Validate() error {
+	return required(
+		field{"teamId", e.TeamID != uuid.Nil},
+		field{"userId", e.UserID != uuid.Nil},
+		field{"role", e.Role != ""},
+	)
+}
+
+// TeamRosterChanged is published for every role change, removal and imported member,
+// Role is empty when the member is no longer on the team
+type TeamRosterChanged struct {
+	ChangeType   string    `json:"changeType"`
+	TeamID       uuid.UUID `json:"teamId"`
+	UserID       uuid.UUID `json:"userId"`
+	PreviousRole string    `json:"previousRole"`
+	Role         string    `json:"role"`
+	ChangedBy    uuid.UUID `json:"changedBy"`
+}
+
+func (TeamRosterChanged) EventType() string { return TypeTeamRosterChanged }
+
+func (e TeamRosterChanged) Validate() error {
+	if err := required(
+		field{"teamId", e.TeamID != uuid.Nil},
+		field{"userId", e.UserID != uuid.Nil},
+	); err != nil {
+		return err
+	}
+	return oneOf("changeType", e.ChangeType, RosterMemberAdded, RosterRoleChanged, RosterMemberRemoved, RosterMemberLeft,
+		RosterAccountDeleted, RosterMemberSuspended, RosterMemberReactivated, RosterCoachReassigned)
+}
+
+// TeamOrganizationChanged is published when a team moves between organizations
+type TeamOrganizationChanged struct {
+	TeamID                 uuid.UUID `json:"teamId"`
+	PreviousOrganizationID uuid.UUID `json:"previousOrganizationId"`
+	OrganizationID         uuid.UUID `json:"organizationId"`
+	ChangedBy              uuid.UUID `json:"changedBy"`
+}
+
+func (TeamOrganizationChanged) EventType() string { return TypeTeamOrganizationChanged }
+
+func (e TeamOrganizationChanged) Validate() error {
+	return required(
+		field{"teamId", e.TeamID != uuid.Nil},
+		field{"organizationId", e.OrganizationID != uuid.Nil},
+		field{"changedBy", e.ChangedBy != uuid.Nil},
+	)
+}
+
+// TeamJoinRequested lets notification consumers tell the coaches about a new request
+type TeamJoinRequested struct {
+	RequestID uuid.UUID   `json:"requestId"`
+	TeamID    uuid.UUID   `json:"teamId"`
+	UserID    uuid.UUID   `json:"userId"`
+	Message   string      `json:"message"`
+	Approvers []uuid.UUID `json:"approvers"`
+}
+
+func (TeamJoinRequested) EventType() string { return TypeTeamJoinRequested }
+
+func (e TeamJoinRequested) Validate() error {
+	return required(
+		field{"requestId", e.RequestID != uuid.Nil},
+		field{"teamId", e.TeamID != uuid.Nil},
+		field{"userId", e.UserID != uuid.Nil},
+	)
+}
+
+// TeamJoinRequestDecided is published when a coach approves or rejects a join request
+type TeamJoinRequestDecided struct {
+	RequestID uuid.UUID `json:"requestId"`
+	TeamID    uuid.UUID `json:"teamId"`
+	UserID    uuid.UUID `json:"userId"`
+	Status    string    `json:"status"`
+	DecidedBy uuid.UUID `json:"decidedBy"`
+}
+
+func (TeamJoinRequestDecided) EventType() string { return TypeTeamJoinRequestDecided }
+
+func (e TeamJoinRequestDecided) Validate() error {
+	if err := required(
+		field{"requestId", e.RequestID != uuid.Nil},
+		field{"teamId", e.TeamID != uuid.Nil},
+		field{"userId", e.UserID != uuid.Nil},
+	); err != nil {
+		return err
+	}
+	return oneOf("status", e.Status, "APPROVED", "REJECTED")
+}
+
+// TeamArchived is published when a team is archived or scheduled for deletion (Status
+// PENDING_DELETION with DeleteAfter set), consumers should stop scheduling for the team
+type TeamArchived struct {
+	TeamID         uuid.UUID   `json:"teamId"`
+	OrganizationID uuid.UUID   `json:"organizationId"`
+	Status         string      `json:"status"`
+	DeleteAfter    *time.Time  `json:"deleteAfter,omitempty"`
+	ArchivedBy     uuid.UUID   `json:"archivedBy"`
+	MemberIDs      []uuid.UUID `json:"memberIds"`
+}
+
+func (TeamArchived) EventType() string { return TypeTeamArchived }
+
+func (e TeamArchived) Validate() error {
+	if err := required(field{"teamId", e.TeamID != uuid.Nil}); err != nil {
+		return err
+	}
+	if e.Status == "PENDING_DELETION" && e.DeleteAfter == nil {
+		return required(field{"deleteAfter", false})
+	}
+	return oneOf("status", e.Status, "ARCHIVED", "PENDING_DELETION")
+}
+
+type TeamRestored struct {
+	TeamID     uuid.UUID `json:"teamId"`
+	RestoredBy uuid.UUID `json:"restoredBy"`
+}
+
+func (TeamRestored) EventType() string { return TypeTeamRestored }
+
+func (e TeamRestored) Validate() error {
+	return required(field{"teamId", e.TeamID != uuid.Nil})
+}
+
+// TeamDeleted is published once the team and its roster are gone for good
+type TeamDeleted struct {
+	TeamID         uuid.UUID   `json:"teamId"`
+	OrganizationID uuid.UUID   `json:"organizationId"`
+	MemberIDs      []uuid.UUID `json:"memberIds"`
+}
+
+func (TeamDeleted) EventType() string { return TypeTeamDeleted }
+
+func (e TeamDeleted) Validate() error {
+	return required(field{"teamId", e.TeamID != uuid.Nil})
+}
+
+// TeamCoachMissing is published when a deleted or suspended user leaves a team without an
+// active coach, org admins are expected to appoint one
+type TeamCoachMissing struct {
+	TeamID         uuid.UUID `json:"teamId"`
+	OrganizationID uuid.UUID `json:"organizationId"`
+	UserID         uuid.UUID `json:"userId"`
+	Reason         string    `json:"reason"`
+}
+
+func (TeamCoachMissing) EventType() string { return TypeTeamCoachMissing }
+
+func (e TeamCoachMissing) Validate() error {
+	if err := required(field{"teamId", e.TeamID != uuid.Nil}); err != nil {
+		return err
+	}
+	return oneOf("reason", e.Reason, CoachMissingAccountDeleted, CoachMissingSuspended)
+}
+
+// TeamAnnouncementPosted is published for every new announcement, RecipientIDs are the members at
+// the time of posting so a notification pipeline can fan it out without calling team-service
+type TeamAnnouncementPosted struct {
+	AnnouncementID uuid.UUID   `json:"announcementId"`
+	TeamID         uuid.UUID   `json:"teamId"`
+	Title          string      `json:"title"`
+	Body           string      `json:"body"`
+	Pinned         bool        `json:"pinned"`
+	RequiresAck    bool        `json:"requiresAck"`
+	ExpiresAt      *time.Time  `json:"expiresAt,omitempty"`
+	PostedBy       uuid.UUID   `json:"postedBy"`
+	RecipientIDs   []uuid.UUID `json:"recipientIds"`
+}
+
+func (TeamAnnouncementPosted) EventType() string { return TypeTeamAnnouncementPosted }
+
+func (e TeamAnnouncementPosted) Validate() error {
+	return required(
+		field{"announcementId", e.AnnouncementID != uuid.Nil},
+		field{"teamId", e.TeamID != uuid.Nil},
+		field{"title", e.Title != ""},
+		field{"postedBy", e.PostedBy != uuid.Nil},
+	)
+}
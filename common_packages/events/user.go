@@ -0,0 +1,74 @@
+package events
+
+import "github.com/google/uuid"
+
+// user events, produced by auth-service (UserCreated) and user-service (the rest)
+const (
+	TypeUserCreated        = "UserCreated"
+	TypeUserProfileUpdated = "UserProfileUpdated"
+	TypeUserDeleted        = "UserDeleted"
+	TypeUserSuspended      = "UserSuspended"
+	TypeUserReactivated    = "UserReactivated"
+)
+
+// UserCreated is published on registration, user-service creates the profile from it
+type UserCreated struct {
+	UserID    uuid.UUID `json:"userId"`
+	FirstName string    `json:"firstName"`
+	LastName  string    `json:"lastName"`
+	Email     string    `json:"email"`
+}
+
+func (UserCreated) EventType() string { return TypeUserCreated }
+
+func (e UserCreated) Validate() error {
+	return required(
+		field{"userId", e.UserID != uuid.Nil},
+		field{"email", e.Email != ""},
+	)
+}
+
+// UserProfileUpdated carries the profile as it is after the update
+type UserProfileUpdated struct {
+	UserID    uuid.UUID `json:"userId"`
+	FirstName string    `json:"firstName"`
+	LastName  string    `json:"lastName"`
+	Email     string    `json:"email"`
+}
+
+func (UserProfileUpdated) EventType() string { return TypeUserProfileUpdated }
+
+func (e UserProfileUpdated) Validate() error {
+	return required(field{"userId", e.UserID != uuid.Nil})
+}
+
+type UserDeleted struct {
+	UserID uuid.UUID `json:"userId"`
+}
+
+func (UserDeleted) EventType() string { return TypeUserDeleted }
+
+func (e UserDeleted) Validate() error {
+	return required(field{"userId", e.UserID != uuid.Nil})
+}
+
+type UserSuspended struct {
+	UserID uuid.UUID `json:"userId"`
+	Reason string    `json:"reason,omitempty"`
+}
+
+func (UserSuspended) EventType() string { return TypeUserSuspended }
+
+func (e UserSuspended) Validate() error {
+	return required(field{"userId", e.UserID != uuid.Nil})
+}
+
+type UserReactivated struct {
+	UserID uuid.UUID `json:"userId"`
+}
+
+func (UserReactivated) EventType() string { return TypeUserReactivated }
+
+func (e UserReactivated) Validate() error {
+	return required(field{"userId", e.UserID != uuid.Nil})
+}
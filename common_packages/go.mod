@@ -0,0 +1,17 @@
+module github.com/wycliff-ochieng/common_packages
+
+go 1.24.5
+
+require (
+	github.com/confluentinc/confluent-kafka-go/v2 v2.11.0
+	github.com/google/uuid v1.6.0
+	google.golang.org/grpc v1.75.1
+	google.golang.org/protobuf v1.36.9
+)
+
+require (
+	golang.org/x/net v0.41.0 // indirect
+	golang.org/x/sys v0.33.0 // indirect
+	golang.org/x/text v0.26.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20250707201910-8d1bb00bc6a7 // indirect
+)
@@ -0,0 +1,117 @@
+// Package kafkaconsumer holds the poll, retry and commit loop shared by the services' Kafka consumers.
+package kafkaconsumer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+)
+
+// a message is retried this many times before it is logged and skipped
+const maxAttempts = 5
+
+// how long applying one message may take
+const handleTimeout = 30 * time.Second
+
+// ErrInvalidEvent marks a message that can never be applied, Run skips it without retrying
+var ErrInvalidEvent = errors.New("invalid event")
+
+// New creates a consumer in groupID that starts from the earliest offset. Offsets are committed by
+// hand once a message is applied
+func New(bootstrapServers string, groupID string) (*kafka.Consumer, error) {
+	consumer, err := kafka.NewConsumer(&kafka.ConfigMap{
+		"bootstrap.servers":  bootstrapServers,
+		"group.id":           groupID,
+		"auto.offset.reset":  "earliest",
+		"enable.auto.commit": false,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("setting up event consumer: %w", err)
+	}
+	return consumer, nil
+}
+
+// Run polls topics and passes every message to handle until ctx is cancelled, then leaves the group
+// and closes the consumer. Offsets are committed after a message is applied, failed messages are
+// re-read with a growing backoff and skipped after maxAttempts. Errors wrapping ErrInvalidEvent are
+// skipped straight away
+func Run(ctx context.Context, l *log.Logger, consumer *kafka.Consumer, topics []string, handle func(ctx context.Context, value []byte) error) {
+
+	defer func() {
+		if err := consumer.Close(); err != nil {
+			l.Printf("error closing event consumer: %v", err)
+		}
+	}()
+
+	if err := consumer.SubscribeTopics(topics, nil); err != nil {
+		l.Printf("error subscribing to topics %v: %v", topics, err)
+		return
+	}
+
+	attempts := map[string]int{}
+
+	for {
+		select {
+		//stop polling on shutdown, uncommitted messages are redelivered to the next consumer
+		case <-ctx.Done():
+			l.Println("event consumer shutting down")
+			return
+
+		default:
+			ev := consumer.Poll(100)
+			if ev == nil {
+				continue
+			}
+
+			switch e := ev.(type) {
+			case *kafka.Message:
+				tp := e.TopicPartition
+				key := fmt.Sprintf("%s/%d/%d", *tp.Topic, tp.Partition, tp.Offset)
+
+				opCtx, cancel := context.WithTimeout(ctx, handleTimeout)
+				err := handle(opCtx, e.Value)
+				cancel()
+
+				if err != nil && !errors.Is(err, ErrInvalidEvent) {
+					attempts[key]++
+					if attempts[key] < maxAttempts && ctx.Err() == nil {
+						l.Printf("applying event %s failed (attempt %d): %v", key, attempts[key], err)
+						retry(ctx, l, consumer, tp, attempts[key])
+						continue
+					}
+					l.Printf("giving up on event %s after %d attempts: %v", key, attempts[key], err)
+				} else if err != nil {
+					l.Printf("skipping event %s: %v", key, err)
+				}
+				delete(attempts, key)
+
+				if _, err := consumer.CommitMessage(e); err != nil {
+					l.Printf("error committing event %s: %v", key, err)
+				}
+
+			case *kafka.Error:
+				l.Printf("Kafka Error: %v(code:%d)", e, e.Code())
+				if e.IsFatal() {
+					return
+				}
+			}
+		}
+	}
+}
+
+// retry rewinds the partition to the failed message and waits before the next poll
+func retry(ctx context.Context, l *log.Logger, consumer *kafka.Consumer, tp kafka.TopicPartition, attempt int) {
+	if err := consumer.Seek(tp, 0); err != nil {
+		l.Printf("error rewinding %s [%d] to %v: %v", *tp.Topic, tp.Partition, tp.Offset, err)
+	}
+
+	backoff := time.Duration(attempt) * time.Second
+	select {
+	case <-ctx.Done():
+	case <-time.After(backoff):
+	}
+}
@@ -1,19 +1,26 @@
 package api
 
 import (
+	"context"
 	"log"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
+	"time"
 
 	corshandlers "github.com/gorilla/handlers"
 	"github.com/gorilla/mux"
+	"github.com/wycliff-ochieng/common_packages/event_grpc/event_proto"
+	"github.com/wycliff-ochieng/common_packages/team_grpc/team_proto"
+	rpc "github.com/wycliff-ochieng/grpc"
 	"github.com/wycliff-ochieng/internal/config"
+	"github.com/wycliff-ochieng/internal/consumer"
 	"github.com/wycliff-ochieng/internal/database"
 	"github.com/wycliff-ochieng/internal/handlers"
+	internal "github.com/wycliff-ochieng/internal/producer"
 	"github.com/wycliff-ochieng/internal/service"
 	auth "github.com/wycliff-ochieng/sports-common-package/middleware"
-	"github.com/wycliff-ochieng/sports-common-package/team_grpc/team_proto"
 	"github.com/wycliff-ochieng/sports-common-package/user_grpc/user_proto"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
@@ -53,9 +60,9 @@ func (s *APIServer) Run() {
 
 	//userServiceAddress := "localhost:50051"
 
-	teamServiceAddress := os.Getenv("USER_SERVICE_GRPC_ADDR")
+	teamServiceAddress := os.Getenv("TEAM_SERVICE_GRPC_ADDR")
 	if teamServiceAddress == "" {
-		teamServiceAddress = "team-service:50051"
+		teamServiceAddress = "team-service:50052"
 	}
 
 	userServiceAddress := os.Getenv("USER_SERVICE_GRPC_ADDR")
@@ -81,26 +88,97 @@ func (s *APIServer) Run() {
 	//user Client
 	userClient := user_proto.NewUserServiceRPCClient(userConn)
 
-	es := service.NewEventService(db, teamClient, userClient, logger)
+	p, err := internal.InitKafkaProducer(s.cfg.KafkaBroker)
+	if err != nil {
+		log.Fatalf("error setting up kafka producer: %v", err)
+	}
+	defer p.Close()
+
+	prod := internal.NewUpdateEvent(p, s.cfg.EventsTopic)
+
+	es := service.NewEventService(db, teamClient, userClient, logger, time.Duration(s.cfg.SeriesHorizonDays)*24*time.Hour, prod)
+
+	//occurrences of recurring events are stored ahead up to the horizon
+	go es.RunSeriesMaterializer(context.Background(), time.Hour)
+
+	//archived and deleted teams lose their future events
+	ec, err := consumer.NewEventConsumer(l, es, s.cfg.KafkaBroker, s.cfg.TeamEventsGroupID)
+	if err != nil {
+		log.Fatalf("error setting up event consumer: %v", err)
+	}
+	go ec.StartEventConsumer(context.Background(), []string{s.cfg.TeamEventsTopic})
 
 	eh := handlers.NewEventHandler(l, es)
 
+	//other services read player stats over gRPC
+	lis, err := net.Listen("tcp", ":"+s.cfg.GRPCPort)
+	if err != nil {
+		log.Fatalf("ERROR spinning up network listener due to: %v", err)
+	}
+
+	grpcServ := grpc.NewServer()
+	event_proto.RegisterEventRPCServer(grpcServ, rpc.NewServer(es, l))
+
+	go func() {
+		l.Printf("gRPC server listening on port: %v", s.cfg.GRPCPort)
+		if err := grpcServ.Serve(lis); err != nil {
+			log.Fatalf("Some error spinning up RPC server: %v", err)
+		}
+	}()
+
 	router := mux.NewRouter()
 
 	authMiddleware := auth.AuthMiddleware(s.cfg.JWTSecret, logger)
 
 	createEvent := router.Methods("POST").Subrouter()
 	createEvent.HandleFunc("/api/events/new", eh.CreateEvent)
+	createEvent.HandleFunc("/api/events/{event_id}/cancel", eh.CancelEvent)
+	createEvent.HandleFunc("/api/events/feed-token", eh.CreateFeedToken)
+	createEvent.HandleFunc("/api/events/{event_id}/check-in-code", eh.IssueCheckInCode)
+	createEvent.HandleFunc("/api/events/{event_id}/check-in", eh.SelfCheckIn)
+	createEvent.HandleFunc("/api/venues", eh.CreateVenue)
+	createEvent.HandleFunc("/api/venues/{venue_id}/resources", eh.AddVenueResource)
+	createEvent.HandleFunc("/api/venues/{venue_id}/admins", eh.AddVenueAdmin)
+	createEvent.HandleFunc("/api/venues/{venue_id}/blockouts", eh.BlockOutVenue)
 	createEvent.Use(authMiddleware)
 
 	getEvents := router.Methods("GET").Subrouter()
+	getEvents.HandleFunc("/api/events", eh.ListEvents)
 	getEvents.HandleFunc("/api/events/get/{event_id}", eh.GetEventDet)
+	getEvents.HandleFunc("/api/events/team/{team_id}/attendance", eh.AttendanceStats)
+	getEvents.HandleFunc("/api/events/team/{team_id}/attendance.csv", eh.AttendanceStatsCSV)
+	getEvents.HandleFunc("/api/events/team/{team_id}/record", eh.SeasonRecord)
+	getEvents.HandleFunc("/api/events/team/{team_id}/stat-definitions", eh.StatDefinitions)
+	getEvents.HandleFunc("/api/events/team/{team_id}/stats", eh.SeasonStats)
+	getEvents.HandleFunc("/api/events/team/{team_id}/stats/leaders", eh.StatLeaders)
+	getEvents.HandleFunc("/api/events/{event_id}/stats", eh.GameStats)
+	getEvents.HandleFunc("/api/venues", eh.ListVenues)
+	getEvents.HandleFunc("/api/venues/{venue_id}", eh.GetVenue)
+	getEvents.HandleFunc("/api/venues/{venue_id}/calendar", eh.VenueCalendar)
 	getEvents.Use(authMiddleware)
 
 	updateEvents := router.Methods("PUT").Subrouter()
-	updateEvents.HandleFunc("api/event/{event_id}", eh.UpdateEventDetails)
+	updateEvents.HandleFunc("/api/events/{event_id}", eh.UpdateEventDetails)
+	updateEvents.HandleFunc("/api/events/{event_id}/rsvp", eh.RSVP)
+	updateEvents.HandleFunc("/api/events/{event_id}/check-ins", eh.RecordCheckIns)
+	updateEvents.HandleFunc("/api/events/{event_id}/result", eh.RecordResult)
+	updateEvents.HandleFunc("/api/events/{event_id}/stats", eh.RecordGameStats)
+	updateEvents.HandleFunc("/api/events/team/{team_id}/stat-definitions/{stat_key}", eh.SetStatDefinition)
+	updateEvents.HandleFunc("/api/venues/{venue_id}", eh.UpdateVenue)
 	updateEvents.Use(authMiddleware)
 
+	deleteEvents := router.Methods("DELETE").Subrouter()
+	deleteEvents.HandleFunc("/api/events/feed-token", eh.RevokeFeedToken)
+	deleteEvents.HandleFunc("/api/events/{event_id}", eh.CancelEvent)
+	deleteEvents.HandleFunc("/api/venues/{venue_id}/blockouts/{blockout_id}", eh.DeleteBlockOut)
+	deleteEvents.HandleFunc("/api/events/team/{team_id}/stat-definitions/{stat_key}", eh.DeleteStatDefinition)
+	deleteEvents.Use(authMiddleware)
+
+	//calendar apps cannot send a bearer token, feeds authenticate with the feed token in the URL
+	calendarFeeds := router.Methods("GET").Subrouter()
+	calendarFeeds.HandleFunc("/api/events/team/{team_id}.ics", eh.TeamFeed)
+	calendarFeeds.HandleFunc("/api/events/me.ics", eh.PersonalFeed)
+
 	origins := s.cfg.CORSAllowedOrigins
 
 	allowedMethods := corshandlers.AllowedMethods([]string{"GET", "POST", "PUT", "DELETE", "OPTIONS"})
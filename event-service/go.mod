@@ -3,6 +3,7 @@ module github.com/wycliff-ochieng
 go 1.24.5
 
 require (
+	github.com/confluentinc/confluent-kafka-go/v2 v2.11.1
 	github.com/google/uuid v1.6.0
 	github.com/gorilla/mux v1.8.1
 	github.com/joho/godotenv v1.5.1
@@ -13,6 +14,8 @@ require (
 	google.golang.org/grpc v1.75.1
 )
 
+require github.com/wycliff-ochieng/common_packages v0.0.0-00010101000000-000000000000
+
 require (
 	github.com/felixge/httpsnoop v1.0.3 // indirect
 	github.com/golang-jwt/jwt/v5 v5.3.0 // indirect
@@ -27,3 +30,5 @@ require (
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20250707201910-8d1bb00bc6a7 // indirect
 	google.golang.org/protobuf v1.36.9 // indirect
 )
+
+replace github.com/wycliff-ochieng/common_packages => ../common_packages
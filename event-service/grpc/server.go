@@ -0,0 +1,141 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/wycliff-ochieng/common_packages/event_grpc/event_proto"
+	"github.com/wycliff-ochieng/internal/models"
+	"github.com/wycliff-ochieng/internal/service"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type Server struct {
+	event_proto.UnimplementedEventRPCServer
+	Service *service.EventService
+	Logger  *log.Logger
+}
+
+func NewServer(service *service.EventService, l *log.Logger) *Server {
+	return &Server{
+		Service: service,
+		Logger:  l,
+	}
+}
+
+// parseSeason reads an optional season id, empty means the team's current season
+func parseSeason(id string) (uuid.UUID, error) {
+	if id == "" {
+		return uuid.Nil, nil
+	}
+	return uuid.Parse(id)
+}
+
+func nullID(id uuid.NullUUID) string {
+	if !id.Valid {
+		return ""
+	}
+	return id.UUID.String()
+}
+
+func toProtoDefinitions(defs []models.StatDefinition) []*event_proto.StatDefinition {
+	res := make([]*event_proto.StatDefinition, 0, len(defs))
+	for _, d := range defs {
+		res = append(res, &event_proto.StatDefinition{Key: d.Key, Name: d.Name})
+	}
+	return res
+}
+
+// GetSeasonStats answers each player's totals and per-game averages over a season, the current one
+// when SeasonId is empty, limited to UserId when given
+func (s *Server) GetSeasonStats(ctx context.Context, req *event_proto.GetSeasonStatsRequest) (*event_proto.GetSeasonStatsResponse, error) {
+
+	teamID, err := uuid.Parse(req.TeamId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid team id: %v", err)
+	}
+	seasonID, err := parseSeason(req.SeasonId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid season id: %v", err)
+	}
+
+	userIDs := make([]uuid.UUID, 0, len(req.UserId))
+	for _, id := range req.UserId {
+		userID, err := uuid.Parse(id)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid user id: %v", err)
+		}
+		userIDs = append(userIDs, userID)
+	}
+
+	stats, err := s.Service.SeasonStats(ctx, teamID, seasonID, userIDs)
+	if err != nil {
+		s.Logger.Printf("season stats lookup failed: %v", err)
+		return nil, status.Error(codes.Internal, "season stats lookup failed")
+	}
+
+	res := &event_proto.GetSeasonStatsResponse{
+		TeamId:      teamID.String(),
+		SeasonId:    nullID(stats.SeasonID),
+		SeasonName:  stats.SeasonName,
+		Definitions: toProtoDefinitions(stats.Definitions),
+	}
+	for _, p := range stats.Players {
+		player := &event_proto.PlayerSeasonStats{
+			UserId:      p.UserID.String(),
+			GamesPlayed: int32(p.GamesPlayed),
+			Totals:      make(map[string]int64, len(p.Totals)),
+			Averages:    p.Averages,
+		}
+		for key, total := range p.Totals {
+			player.Totals[key] = int64(total)
+		}
+		res.Players = append(res.Players, player)
+	}
+	return res, nil
+}
+
+// GetStatLeaders ranks the players of a season by their total of a stat, or its per-game average
+func (s *Server) GetStatLeaders(ctx context.Context, req *event_proto.GetStatLeadersRequest) (*event_proto.GetStatLeadersResponse, error) {
+
+	teamID, err := uuid.Parse(req.TeamId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid team id: %v", err)
+	}
+	seasonID, err := parseSeason(req.SeasonId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid season id: %v", err)
+	}
+	if req.Stat == "" {
+		return nil, status.Error(codes.InvalidArgument, "stat is required")
+	}
+
+	board, err := s.Service.StatLeaders(ctx, teamID, seasonID, req.Stat, req.PerGame, int(req.Limit))
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidEvent) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		s.Logger.Printf("stat leaders lookup failed: %v", err)
+		return nil, status.Error(codes.Internal, "stat leaders lookup failed")
+	}
+
+	res := &event_proto.GetStatLeadersResponse{
+		TeamId:     teamID.String(),
+		SeasonId:   nullID(board.SeasonID),
+		SeasonName: board.SeasonName,
+		Stat:       board.Stat,
+		PerGame:    board.PerGame,
+	}
+	for _, l := range board.Leaders {
+		res.Leaders = append(res.Leaders, &event_proto.StatLeader{
+			Rank:        int32(l.Rank),
+			UserId:      l.UserID.String(),
+			GamesPlayed: int32(l.GamesPlayed),
+			Value:       l.Value,
+		})
+	}
+	return res, nil
+}
@@ -22,6 +22,19 @@ type Config struct {
 	RefreshSecret      string
 	RefreshExpiry      string
 	CORSAllowedOrigins []string
+
+	// days of occurrences of recurring events kept materialized ahead
+	SeriesHorizonDays int
+
+	// port the gRPC server listens on
+	GRPCPort string
+
+	KafkaBroker string
+	// topic RSVP changes are published to
+	EventsTopic string
+	// team lifecycle events cancel the events of archived and deleted teams
+	TeamEventsTopic   string
+	TeamEventsGroupID string
 }
 
 func Load() (*Config, error) {
@@ -40,6 +53,12 @@ func Load() (*Config, error) {
 	config.JWTSecret = getEnv("JWT_SECRET", "mydogsnameisrufus")
 	config.RefreshSecret = getEnv("REFRESH_SECRET", "myotherdogiscalledseedolf")
 	config.CORSAllowedOrigins = getEnvAsSlice("CORS_ALLOWED_ORIGINS", []string{"http://localhost:5173"}, ",")
+	config.SeriesHorizonDays = getEnvAsInt("EVENT_SERIES_HORIZON_DAYS", 90)
+	config.GRPCPort = getEnv("PORT_GRPC", "50054")
+	config.KafkaBroker = getEnv("KAFKA_BROKER", "localhost:9092")
+	config.EventsTopic = getEnv("EVENT_EVENTS_TOPIC", "event_events")
+	config.TeamEventsTopic = getEnv("TEAM_EVENTS_TOPIC", "team_events")
+	config.TeamEventsGroupID = getEnv("TEAM_EVENTS_GROUP_ID", "event-service")
 
 	return config, nil
 }
@@ -0,0 +1,79 @@
+package consumer
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/google/uuid"
+	"github.com/wycliff-ochieng/common_packages/events"
+	"github.com/wycliff-ochieng/common_packages/kafkaconsumer"
+)
+
+// cancellation reasons sent to attendees
+const (
+	reasonTeamArchived = "The team was archived"
+	reasonTeamDeleted  = "The team was deleted"
+)
+
+var ErrInvalidEvent = kafkaconsumer.ErrInvalidEvent
+
+// EventHandler applies team lifecycle and roster events to the team's events, implemented by
+// service.EventService. Applying the same event twice changes nothing
+type EventHandler interface {
+	CancelTeamEvents(ctx context.Context, teamID uuid.UUID, cancelledBy uuid.UUID, reason string) error
+	RemoveTeamAttendee(ctx context.Context, teamID uuid.UUID, userID uuid.UUID) error
+}
+
+type EventConsumer struct {
+	l        *log.Logger
+	h        EventHandler
+	consumer *kafka.Consumer
+}
+
+func NewEventConsumer(l *log.Logger, h EventHandler, bootstrapServers string, groupID string) (*EventConsumer, error) {
+
+	consumer, err := kafkaconsumer.New(bootstrapServers, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EventConsumer{
+		l:        l,
+		h:        h,
+		consumer: consumer,
+	}, nil
+}
+
+// handle validates and applies one message, errors wrapping ErrInvalidEvent never succeed on retry
+func (c *EventConsumer) handle(ctx context.Context, value []byte) error {
+	_, payload, err := events.Unmarshal(value)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidEvent, err)
+	}
+
+	switch e := payload.(type) {
+	case *events.TeamArchived:
+		//sent on archive and again when the deletion is scheduled, the second finds nothing to cancel
+		return c.h.CancelTeamEvents(ctx, e.TeamID, e.ArchivedBy, reasonTeamArchived)
+	case *events.TeamDeleted:
+		//members were told when the deletion was scheduled, whatever is left goes quietly
+		return c.h.CancelTeamEvents(ctx, e.TeamID, uuid.Nil, reasonTeamDeleted)
+	case *events.TeamRosterChanged:
+		switch e.ChangeType {
+		case events.RosterMemberRemoved, events.RosterMemberLeft, events.RosterAccountDeleted:
+			return c.h.RemoveTeamAttendee(ctx, e.TeamID, e.UserID)
+		}
+		//role changes, suspensions and new members leave the attendance as it is
+		return nil
+	default:
+		//the rest of the team events are not ours to apply
+		return nil
+	}
+}
+
+// StartEventConsumer applies the messages of topics until ctx is cancelled, see kafkaconsumer.Run
+func (c *EventConsumer) StartEventConsumer(ctx context.Context, topics []string) {
+	kafkaconsumer.Run(ctx, c.l, c.consumer, topics, c.handle)
+}
@@ -0,0 +1,118 @@
+package consumer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/wycliff-ochieng/common_packages/events"
+)
+
+type cancelCall struct {
+	teamID      uuid.UUID
+	cancelledBy uuid.UUID
+	reason      string
+}
+
+type removeCall struct {
+	teamID uuid.UUID
+	userID uuid.UUID
+}
+
+type recordingHandler struct {
+	calls    []cancelCall
+	removals []removeCall
+}
+
+func (h *recordingHandler) CancelTeamEvents(ctx context.Context, teamID uuid.UUID, cancelledBy uuid.UUID, reason string) error {
+	h.calls = append(h.calls, cancelCall{teamID, cancelledBy, reason})
+	return nil
+}
+
+func (h *recordingHandler) RemoveTeamAttendee(ctx context.Context, teamID uuid.UUID, userID uuid.UUID) error {
+	h.removals = append(h.removals, removeCall{teamID, userID})
+	return nil
+}
+
+func message(t *testing.T, p events.Payload) []byte {
+	t.Helper()
+	data, err := events.Marshal("team-service", p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestHandleCancelsEventsOfArchivedAndDeletedTeams(t *testing.T) {
+	h := &recordingHandler{}
+	c := &EventConsumer{l: log.New(io.Discard, "", 0), h: h}
+	teamID, coachID := uuid.New(), uuid.New()
+
+	msgs := [][]byte{
+		message(t, &events.TeamArchived{TeamID: teamID, Status: "ARCHIVED", ArchivedBy: coachID}),
+		message(t, &events.TeamRestored{TeamID: teamID, RestoredBy: coachID}),
+		message(t, &events.TeamDeleted{TeamID: teamID}),
+	}
+	for _, m := range msgs {
+		if err := c.handle(context.Background(), m); err != nil {
+			t.Fatalf("handle: %v", err)
+		}
+	}
+
+	want := []cancelCall{
+		{teamID, coachID, reasonTeamArchived},
+		{teamID, uuid.Nil, reasonTeamDeleted},
+	}
+	if len(h.calls) != len(want) {
+		t.Fatalf("calls = %v, want %v", h.calls, want)
+	}
+	for i := range want {
+		if h.calls[i] != want[i] {
+			t.Errorf("call %d = %v, want %v", i, h.calls[i], want[i])
+		}
+	}
+}
+
+func TestHandleRemovesFormerMembersFromUpcomingEvents(t *testing.T) {
+	h := &recordingHandler{}
+	c := &EventConsumer{l: log.New(io.Discard, "", 0), h: h}
+	teamID, coachID := uuid.New(), uuid.New()
+	removed, left, deleted, suspended := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+
+	msgs := [][]byte{
+		message(t, &events.TeamRosterChanged{ChangeType: events.RosterMemberRemoved, TeamID: teamID, UserID: removed, PreviousRole: "player", ChangedBy: coachID}),
+		message(t, &events.TeamRosterChanged{ChangeType: events.RosterMemberLeft, TeamID: teamID, UserID: left, PreviousRole: "player", ChangedBy: left}),
+		message(t, &events.TeamRosterChanged{ChangeType: events.RosterAccountDeleted, TeamID: teamID, UserID: deleted, PreviousRole: "player"}),
+		message(t, &events.TeamRosterChanged{ChangeType: events.RosterMemberSuspended, TeamID: teamID, UserID: suspended, PreviousRole: "player", Role: "player"}),
+		message(t, &events.TeamRosterChanged{ChangeType: events.RosterRoleChanged, TeamID: teamID, UserID: removed, PreviousRole: "player", Role: "manager", ChangedBy: coachID}),
+	}
+	for _, m := range msgs {
+		if err := c.handle(context.Background(), m); err != nil {
+			t.Fatalf("handle: %v", err)
+		}
+	}
+
+	want := []removeCall{{teamID, removed}, {teamID, left}, {teamID, deleted}}
+	if len(h.removals) != len(want) {
+		t.Fatalf("removals = %v, want %v", h.removals, want)
+	}
+	for i := range want {
+		if h.removals[i] != want[i] {
+			t.Errorf("removal %d = %v, want %v", i, h.removals[i], want[i])
+		}
+	}
+	if len(h.calls) != 0 {
+		t.Errorf("cancelled events on roster changes: %v", h.calls)
+	}
+}
+
+func TestHandleRejectsInvalidEvents(t *testing.T) {
+	c := &EventConsumer{l: log.New(io.Discard, "", 0), h: &recordingHandler{}}
+
+	if err := c.handle(context.Background(), []byte(`{"teamId":"`+uuid.New().String()+`"}`)); !errors.Is(err, ErrInvalidEvent) {
+		t.Errorf("handle = %v, want ErrInvalidEvent", err)
+	}
+}
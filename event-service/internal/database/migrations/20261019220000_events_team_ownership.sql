@@ -0,0 +1,51 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE events
+    ADD COLUMN team_id UUID,
+    ADD COLUMN created_by UUID,
+    ADD COLUMN notes TEXT,
+    ADD COLUMN created_at TIMESTAMP DEFAULT NOW(),
+    ADD COLUMN updated_at TIMESTAMP DEFAULT NOW(),
+    ALTER COLUMN event_title TYPE VARCHAR(200),
+    ALTER COLUMN location TYPE VARCHAR(255);
+
+-- the team of an existing event is only known through its attendance rows, events that never got
+-- one keep a NULL team and are not listed for any team
+UPDATE events e SET team_id = a.team_id
+FROM (SELECT DISTINCT ON (event_id) event_id, team_id FROM attendance ORDER BY event_id, updated_at DESC) a
+WHERE a.event_id = e.event_id AND e.team_id IS NULL;
+
+CREATE INDEX idx_events_team_start ON events(team_id, start_time);
+
+-- event_id alone as the key allowed a single attendee per event
+ALTER TABLE attendance DROP CONSTRAINT attendance_pkey;
+ALTER TABLE attendance ALTER COLUMN event_id DROP DEFAULT;
+ALTER TABLE attendance ADD CONSTRAINT attendance_pkey PRIMARY KEY (event_id, user_id);
+
+-- NOT VALID keeps the attendance rows left behind by events that were never stored, new rows
+-- are checked
+ALTER TABLE attendance ADD CONSTRAINT attendance_event_fk
+    FOREIGN KEY (event_id) REFERENCES events(event_id) ON DELETE CASCADE NOT VALID;
+
+CREATE INDEX idx_attendance_user ON attendance(user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_attendance_user;
+ALTER TABLE attendance DROP CONSTRAINT IF EXISTS attendance_event_fk;
+ALTER TABLE attendance DROP CONSTRAINT IF EXISTS attendance_pkey;
+-- the old key allows one attendee per event, only the most recently updated row of each event is
+-- kept, the others are lost
+DELETE FROM attendance WHERE (event_id, user_id) NOT IN (
+    SELECT DISTINCT ON (event_id) event_id, user_id FROM attendance ORDER BY event_id, updated_at DESC NULLS LAST, user_id
+);
+ALTER TABLE attendance ADD CONSTRAINT attendance_pkey PRIMARY KEY (event_id);
+DROP INDEX IF EXISTS idx_events_team_start;
+ALTER TABLE events
+    DROP COLUMN IF EXISTS updated_at,
+    DROP COLUMN IF EXISTS created_at,
+    DROP COLUMN IF EXISTS notes,
+    DROP COLUMN IF EXISTS created_by,
+    DROP COLUMN IF EXISTS team_id;
+-- +goose StatementEnd
@@ -0,0 +1,44 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE event_series (
+    series_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    team_id UUID NOT NULL,
+    created_by UUID,
+    event_title VARCHAR(200),
+    event_type VARCHAR(20),
+    location VARCHAR(255),
+    notes TEXT,
+    rrule TEXT NOT NULL,
+    time_zone VARCHAR(64) NOT NULL,
+    -- wall clock start of the first occurrence in time_zone
+    dtstart TIMESTAMP NOT NULL,
+    duration_minutes INT NOT NULL CHECK (duration_minutes > 0),
+    -- occurrences exist as events rows up to here (UTC)
+    materialized_until TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_event_series_team ON event_series(team_id);
+CREATE INDEX idx_event_series_materialized ON event_series(materialized_until);
+
+ALTER TABLE events
+    ADD COLUMN series_id UUID REFERENCES event_series(series_id) ON DELETE CASCADE,
+    ADD COLUMN occurrence_date DATE,
+    ADD COLUMN is_exception BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'SCHEDULED',
+    ADD CONSTRAINT events_status_check CHECK (status IN ('SCHEDULED', 'CANCELLED')),
+    ADD CONSTRAINT events_series_occurrence_unique UNIQUE (series_id, occurrence_date);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE events
+    DROP CONSTRAINT IF EXISTS events_series_occurrence_unique,
+    DROP CONSTRAINT IF EXISTS events_status_check,
+    DROP COLUMN IF EXISTS status,
+    DROP COLUMN IF EXISTS is_exception,
+    DROP COLUMN IF EXISTS occurrence_date,
+    DROP COLUMN IF EXISTS series_id;
+DROP TABLE IF EXISTS event_series;
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+-- bumped on every change so calendar apps replace their copy of the event
+ALTER TABLE events ADD COLUMN sequence INT NOT NULL DEFAULT 0;
+
+-- only the SHA-256 of a feed token is stored, the token itself is shown to the user once
+CREATE TABLE calendar_feed_tokens (
+    token_hash VARCHAR(64) PRIMARY KEY,
+    user_id UUID NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    last_used_at TIMESTAMP,
+    revoked_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_calendar_feed_tokens_active ON calendar_feed_tokens(user_id) WHERE revoked_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS calendar_feed_tokens;
+ALTER TABLE events DROP COLUMN IF EXISTS sequence;
+-- +goose StatementEnd
@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+-- members can answer until then, NULL means until the event starts
+ALTER TABLE events ADD COLUMN rsvp_deadline TIMESTAMP;
+
+ALTER TABLE attendance
+    ADD COLUMN reason TEXT,
+    ADD COLUMN responded_at TIMESTAMP,
+    ADD COLUMN updated_by UUID,
+    -- a coach answered for the member, the member can no longer change it
+    ADD COLUMN overridden BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE attendance SET event_status = 'PENDING' WHERE event_status IS NULL;
+ALTER TABLE attendance
+    ALTER COLUMN event_status SET DEFAULT 'PENDING',
+    ALTER COLUMN event_status SET NOT NULL,
+    ADD CONSTRAINT attendance_status_check CHECK (event_status IN ('PENDING', 'GOING', 'NOT_GOING', 'MAYBE')) NOT VALID;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE attendance
+    DROP CONSTRAINT IF EXISTS attendance_status_check,
+    ALTER COLUMN event_status DROP NOT NULL,
+    ALTER COLUMN event_status DROP DEFAULT,
+    DROP COLUMN IF EXISTS overridden,
+    DROP COLUMN IF EXISTS updated_by,
+    DROP COLUMN IF EXISTS responded_at,
+    DROP COLUMN IF EXISTS reason;
+ALTER TABLE events DROP COLUMN IF EXISTS rsvp_deadline;
+-- +goose StatementEnd
@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+-- short code players type or scan during the check-in window, NULL until a coach issues one
+ALTER TABLE events ADD COLUMN check_in_code VARCHAR(12);
+
+-- who actually turned up, kept apart from the RSVP in attendance
+CREATE TABLE IF NOT EXISTS check_ins (
+    event_id UUID NOT NULL REFERENCES events(event_id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    team_id UUID NOT NULL,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('PRESENT', 'LATE', 'ABSENT', 'EXCUSED')),
+    method VARCHAR(10) NOT NULL CHECK (method IN ('COACH', 'SELF')),
+    note TEXT,
+    recorded_by UUID NOT NULL,
+    recorded_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (event_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_check_ins_team_user ON check_ins(team_id, user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS check_ins;
+ALTER TABLE events DROP COLUMN IF EXISTS check_in_code;
+-- +goose StatementEnd
@@ -0,0 +1,79 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS venues (
+    venue_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(150) NOT NULL,
+    address TEXT NOT NULL DEFAULT '',
+    latitude DOUBLE PRECISION CHECK (latitude BETWEEN -90 AND 90),
+    longitude DOUBLE PRECISION CHECK (longitude BETWEEN -180 AND 180),
+    capacity INT CHECK (capacity >= 0),
+    -- opening hours are read in this zone
+    time_zone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    created_by UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- courts, pitches and other parts of a venue that are booked on their own
+CREATE TABLE IF NOT EXISTS venue_resources (
+    resource_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    venue_id UUID NOT NULL REFERENCES venues(venue_id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    surface VARCHAR(50) NOT NULL DEFAULT '',
+    capacity INT CHECK (capacity >= 0),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (venue_id, name)
+);
+
+-- a venue without rows is always open, a weekday can have several ranges
+CREATE TABLE IF NOT EXISTS venue_hours (
+    venue_id UUID NOT NULL REFERENCES venues(venue_id) ON DELETE CASCADE,
+    weekday SMALLINT NOT NULL CHECK (weekday BETWEEN 0 AND 6), -- 0 is Sunday
+    opens_at TIME NOT NULL,
+    closes_at TIME NOT NULL CHECK (closes_at > opens_at),
+    PRIMARY KEY (venue_id, weekday, opens_at)
+);
+
+CREATE TABLE IF NOT EXISTS venue_admins (
+    venue_id UUID NOT NULL REFERENCES venues(venue_id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (venue_id, user_id)
+);
+
+-- resource_id NULL blocks the whole venue
+CREATE TABLE IF NOT EXISTS venue_blockouts (
+    blockout_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    venue_id UUID NOT NULL REFERENCES venues(venue_id) ON DELETE CASCADE,
+    resource_id UUID REFERENCES venue_resources(resource_id) ON DELETE CASCADE,
+    start_time TIMESTAMP NOT NULL,
+    end_time TIMESTAMP NOT NULL CHECK (end_time > start_time),
+    reason TEXT NOT NULL DEFAULT '',
+    created_by UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_venue_blockouts_venue_start ON venue_blockouts(venue_id, start_time);
+
+ALTER TABLE events
+    ADD COLUMN venue_id UUID REFERENCES venues(venue_id) ON DELETE SET NULL,
+    ADD COLUMN resource_id UUID REFERENCES venue_resources(resource_id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_events_venue_start ON events(venue_id, start_time);
+
+ALTER TABLE event_series
+    ADD COLUMN venue_id UUID REFERENCES venues(venue_id) ON DELETE SET NULL,
+    ADD COLUMN resource_id UUID REFERENCES venue_resources(resource_id) ON DELETE SET NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE event_series DROP COLUMN IF EXISTS resource_id, DROP COLUMN IF EXISTS venue_id;
+DROP INDEX IF EXISTS idx_events_venue_start;
+ALTER TABLE events DROP COLUMN IF EXISTS resource_id, DROP COLUMN IF EXISTS venue_id;
+DROP TABLE IF EXISTS venue_blockouts;
+DROP TABLE IF EXISTS venue_admins;
+DROP TABLE IF EXISTS venue_hours;
+DROP TABLE IF EXISTS venue_resources;
+DROP TABLE IF EXISTS venues;
+-- +goose StatementEnd
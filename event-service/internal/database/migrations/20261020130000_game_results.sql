@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+-- the outcome of a game event, from the point of view of the team that owns the event
+CREATE TABLE IF NOT EXISTS game_results (
+    event_id UUID PRIMARY KEY REFERENCES events(event_id) ON DELETE CASCADE,
+    team_id UUID NOT NULL,
+    -- the team-service season the game was played in, NULL outside any season
+    season_id UUID,
+    season_name VARCHAR(100) NOT NULL DEFAULT '',
+    -- set when the opponent is a team in the system, opponent_name is always filled
+    opponent_team_id UUID,
+    opponent_name VARCHAR(150) NOT NULL,
+    home_away VARCHAR(10) NOT NULL CHECK (home_away IN ('HOME', 'AWAY', 'NEUTRAL')),
+    score_for INT NOT NULL CHECK (score_for >= 0),
+    score_against INT NOT NULL CHECK (score_against >= 0),
+    -- [{"Period":"1H","For":1,"Against":0}, ...]
+    periods JSONB NOT NULL DEFAULT '[]'::jsonb,
+    result CHAR(1) NOT NULL CHECK (result IN ('W', 'D', 'L')),
+    -- the shootout period that broke a tie, empty otherwise
+    decided_by VARCHAR(10) NOT NULL DEFAULT '',
+    recorded_by UUID NOT NULL,
+    recorded_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_game_results_team_season ON game_results(team_id, season_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS game_results;
+-- +goose StatementEnd
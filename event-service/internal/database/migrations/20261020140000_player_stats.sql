@@ -0,0 +1,90 @@
+-- +goose Up
+-- +goose StatementBegin
+-- what is tracked per player and game. Rows without a team are the defaults of a sport, a team's
+-- own rows add stats or replace a default with the same key
+CREATE TABLE IF NOT EXISTS stat_definitions (
+    definition_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    sport VARCHAR(50) NOT NULL DEFAULT '',
+    team_id UUID,
+    stat_key VARCHAR(30) NOT NULL,
+    name VARCHAR(60) NOT NULL,
+    min_value INT NOT NULL DEFAULT 0,
+    max_value INT,
+    sort_order INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK (max_value IS NULL OR max_value >= min_value),
+    CHECK ((team_id IS NULL) <> (sport = ''))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_stat_definitions_sport_key ON stat_definitions(sport, stat_key) WHERE team_id IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_stat_definitions_team_key ON stat_definitions(team_id, stat_key) WHERE team_id IS NOT NULL;
+
+INSERT INTO stat_definitions(sport, stat_key, name, max_value, sort_order) VALUES
+    ('soccer', 'goals', 'Goals', NULL, 1),
+    ('soccer', 'assists', 'Assists', NULL, 2),
+    ('soccer', 'minutes', 'Minutes', 130, 3),
+    ('soccer', 'shots', 'Shots', NULL, 4),
+    ('soccer', 'saves', 'Saves', NULL, 5),
+    ('soccer', 'yellow_cards', 'Yellow cards', 2, 6),
+    ('soccer', 'red_cards', 'Red cards', 1, 7),
+    ('football', 'goals', 'Goals', NULL, 1),
+    ('football', 'assists', 'Assists', NULL, 2),
+    ('football', 'minutes', 'Minutes', 130, 3),
+    ('football', 'shots', 'Shots', NULL, 4),
+    ('football', 'saves', 'Saves', NULL, 5),
+    ('football', 'yellow_cards', 'Yellow cards', 2, 6),
+    ('football', 'red_cards', 'Red cards', 1, 7),
+    ('basketball', 'points', 'Points', NULL, 1),
+    ('basketball', 'rebounds', 'Rebounds', NULL, 2),
+    ('basketball', 'assists', 'Assists', NULL, 3),
+    ('basketball', 'steals', 'Steals', NULL, 4),
+    ('basketball', 'blocks', 'Blocks', NULL, 5),
+    ('basketball', 'turnovers', 'Turnovers', NULL, 6),
+    ('basketball', 'fouls', 'Fouls', 6, 7),
+    ('basketball', 'minutes', 'Minutes', 70, 8),
+    ('volleyball', 'kills', 'Kills', NULL, 1),
+    ('volleyball', 'aces', 'Aces', NULL, 2),
+    ('volleyball', 'blocks', 'Blocks', NULL, 3),
+    ('volleyball', 'digs', 'Digs', NULL, 4),
+    ('volleyball', 'assists', 'Assists', NULL, 5),
+    ('rugby', 'tries', 'Tries', NULL, 1),
+    ('rugby', 'conversions', 'Conversions', NULL, 2),
+    ('rugby', 'penalty_goals', 'Penalty goals', NULL, 3),
+    ('rugby', 'tackles', 'Tackles', NULL, 4),
+    ('rugby', 'minutes', 'Minutes', 100, 5),
+    ('rugby', 'yellow_cards', 'Yellow cards', 2, 6),
+    ('rugby', 'red_cards', 'Red cards', 1, 7),
+    ('netball', 'goals', 'Goals', NULL, 1),
+    ('netball', 'attempts', 'Attempts', NULL, 2),
+    ('netball', 'intercepts', 'Intercepts', NULL, 3),
+    ('netball', 'rebounds', 'Rebounds', NULL, 4),
+    ('hockey', 'goals', 'Goals', NULL, 1),
+    ('hockey', 'assists', 'Assists', NULL, 2),
+    ('hockey', 'green_cards', 'Green cards', NULL, 3),
+    ('hockey', 'yellow_cards', 'Yellow cards', NULL, 4),
+    ('hockey', 'red_cards', 'Red cards', 1, 5)
+ON CONFLICT DO NOTHING;
+
+-- one row per player, game and stat
+CREATE TABLE IF NOT EXISTS player_game_stats (
+    event_id UUID NOT NULL REFERENCES events(event_id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    team_id UUID NOT NULL,
+    -- the team-service season of the game, NULL outside any season
+    season_id UUID,
+    season_name VARCHAR(100) NOT NULL DEFAULT '',
+    stat_key VARCHAR(30) NOT NULL,
+    value INT NOT NULL,
+    recorded_by UUID NOT NULL,
+    recorded_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (event_id, user_id, stat_key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_player_game_stats_team_season ON player_game_stats(team_id, season_id, stat_key);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS player_game_stats;
+DROP TABLE IF EXISTS stat_definitions;
+-- +goose StatementEnd
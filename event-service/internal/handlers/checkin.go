@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/wycliff-ochieng/internal/models"
+	auth "github.com/wycliff-ochieng/sports-common-package/middleware"
+)
+
+const dateLayout = "2006-01-02"
+
+// POST :: /api/events/{event_id}/check-in-code -> a new self check-in code for the coach to show
+func (eh *EventHandler) IssueCheckInCode(w http.ResponseWriter, r *http.Request) {
+	eh.logger.Println("issuing check-in code")
+
+	ctx := r.Context()
+
+	eventID, err := uuid.Parse(mux.Vars(r)["event_id"])
+	if err != nil {
+		http.Error(w, "invalid event id", http.StatusBadRequest)
+		return
+	}
+
+	reqUserID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	code, err := eh.es.IssueCheckInCode(ctx, reqUserID, eventID)
+	if err != nil {
+		eh.logger.Printf("issue check-in code failed due to: %v", err)
+		http.Error(w, err.Error(), serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(code)
+}
+
+// POST :: /api/events/{event_id}/check-in?code= -> the caller checks in, the code can also be sent in the body
+func (eh *EventHandler) SelfCheckIn(w http.ResponseWriter, r *http.Request) {
+	eh.logger.Println("self check-in")
+
+	ctx := r.Context()
+
+	eventID, err := uuid.Parse(mux.Vars(r)["event_id"])
+	if err != nil {
+		http.Error(w, "invalid event id", http.StatusBadRequest)
+		return
+	}
+
+	reqUserID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	//scanning the QR code opens the URL with the code in it, typing it sends a body
+	code := r.URL.Query().Get("code")
+	if code == "" {
+		var req models.SelfCheckInReq
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+		code = req.Code
+	}
+
+	checkIn, err := eh.es.SelfCheckIn(ctx, reqUserID, eventID, code)
+	if err != nil {
+		eh.logger.Printf("self check-in failed due to: %v", err)
+		http.Error(w, err.Error(), serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(checkIn)
+}
+
+// PUT :: /api/events/{event_id}/check-ins -> a coach records who was present, late, absent or excused
+func (eh *EventHandler) RecordCheckIns(w http.ResponseWriter, r *http.Request) {
+	eh.logger.Println("recording check-ins")
+
+	ctx := r.Context()
+
+	eventID, err := uuid.Parse(mux.Vars(r)["event_id"])
+	if err != nil {
+		http.Error(w, "invalid event id", http.StatusBadRequest)
+		return
+	}
+
+	reqUserID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.RecordCheckInsReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	checkIns, err := eh.es.RecordCheckIns(ctx, reqUserID, eventID, req)
+	if err != nil {
+		eh.logger.Printf("record check-ins failed due to: %v", err)
+		http.Error(w, err.Error(), serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(checkIns)
+}
+
+// GET :: /api/events/team/{team_id}/attendance?from=&to= -> per player attendance rates
+func (eh *EventHandler) AttendanceStats(w http.ResponseWriter, r *http.Request) {
+	report, ok := eh.attendanceReport(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(report)
+}
+
+// GET :: /api/events/team/{team_id}/attendance.csv?from=&to= -> the same report as a spreadsheet
+func (eh *EventHandler) AttendanceStatsCSV(w http.ResponseWriter, r *http.Request) {
+	report, ok := eh.attendanceReport(w, r)
+	if !ok {
+		return
+	}
+
+	//To is exclusive, name the file after the last day it covers
+	filename := fmt.Sprintf("attendance-%s-%s.csv", report.From.Format(dateLayout), report.To.Add(-time.Second).Format(dateLayout))
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	w.WriteHeader(http.StatusOK)
+
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"user_id", "first_name", "last_name", "events", "present", "late", "absent", "excused", "unrecorded", "attendance_rate"})
+	for _, p := range report.Players {
+		cw.Write([]string{
+			p.UserID.String(),
+			p.FirstName,
+			p.LastName,
+			strconv.Itoa(p.Events),
+			strconv.Itoa(p.Present),
+			strconv.Itoa(p.Late),
+			strconv.Itoa(p.Absent),
+			strconv.Itoa(p.Excused),
+			strconv.Itoa(p.Unrecorded),
+			strconv.FormatFloat(p.Rate, 'f', 2, 64),
+		})
+	}
+	cw.Flush()
+}
+
+// attendanceReport parses the team and range shared by the JSON and CSV reports, writing the error
+// response itself when it fails
+func (eh *EventHandler) attendanceReport(w http.ResponseWriter, r *http.Request) (*models.AttendanceReport, bool) {
+	eh.logger.Println("building attendance report")
+
+	ctx := r.Context()
+
+	teamID, err := uuid.Parse(mux.Vars(r)["team_id"])
+	if err != nil {
+		http.Error(w, "invalid team id", http.StatusBadRequest)
+		return nil, false
+	}
+
+	reqUserID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return nil, false
+	}
+
+	from, err := parseDateParam(r.URL.Query().Get("from"), false, time.UTC)
+	if err != nil {
+		http.Error(w, "invalid from, use YYYY-MM-DD or RFC 3339", http.StatusBadRequest)
+		return nil, false
+	}
+	to, err := parseDateParam(r.URL.Query().Get("to"), true, time.UTC)
+	if err != nil {
+		http.Error(w, "invalid to, use YYYY-MM-DD or RFC 3339", http.StatusBadRequest)
+		return nil, false
+	}
+
+	report, err := eh.es.AttendanceStats(ctx, reqUserID, teamID, from, to)
+	if err != nil {
+		eh.logger.Printf("attendance report failed due to: %v", err)
+		http.Error(w, err.Error(), serviceErrorStatus(err))
+		return nil, false
+	}
+	return report, true
+}
+
+// parseDateParam reads a date in loc or an RFC 3339 time, a bare end date includes the whole day.
+// Empty gives the zero time so the service picks the default
+func parseDateParam(value string, end bool, loc *time.Location) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.ParseInLocation(dateLayout, value, loc); err == nil {
+		if end {
+			t = t.AddDate(0, 0, 1)
+		}
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
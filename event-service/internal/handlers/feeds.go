@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/wycliff-ochieng/internal/ics"
+	auth "github.com/wycliff-ochieng/sports-common-package/middleware"
+)
+
+// POST :: /api/events/feed-token -> issues a calendar feed token, revoking the previous one
+func (eh *EventHandler) CreateFeedToken(w http.ResponseWriter, r *http.Request) {
+	eh.logger.Println("issuing calendar feed token")
+
+	ctx := r.Context()
+
+	userID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	token, err := eh.es.CreateFeedToken(ctx, userID)
+	if err != nil {
+		eh.logger.Printf("create feed token failed due to: %v", err)
+		http.Error(w, "failed to create feed token", serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(&token)
+}
+
+// DELETE :: /api/events/feed-token -> subscribed calendars stop updating
+func (eh *EventHandler) RevokeFeedToken(w http.ResponseWriter, r *http.Request) {
+	eh.logger.Println("revoking calendar feed token")
+
+	ctx := r.Context()
+
+	userID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	if err := eh.es.RevokeFeedToken(ctx, userID); err != nil {
+		eh.logger.Printf("revoke feed token failed due to: %v", err)
+		http.Error(w, "failed to revoke feed token", serviceErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GET :: /api/events/team/{team_id}.ics?token= -> the team's calendar, authenticated by the feed token
+func (eh *EventHandler) TeamFeed(w http.ResponseWriter, r *http.Request) {
+	eh.logger.Println("serving team calendar feed")
+
+	teamID, err := uuid.Parse(mux.Vars(r)["team_id"])
+	if err != nil {
+		http.Error(w, "invalid team id", http.StatusBadRequest)
+		return
+	}
+
+	cal, err := eh.es.TeamFeed(r.Context(), r.URL.Query().Get("token"), teamID)
+	if err != nil {
+		eh.logger.Printf("team feed failed due to: %v", err)
+		http.Error(w, "failed to build calendar feed", serviceErrorStatus(err))
+		return
+	}
+
+	writeCalendar(w, cal, "team.ics")
+}
+
+// GET :: /api/events/me.ics?token= -> events of all the token holder's teams
+func (eh *EventHandler) PersonalFeed(w http.ResponseWriter, r *http.Request) {
+	eh.logger.Println("serving personal calendar feed")
+
+	cal, err := eh.es.PersonalFeed(r.Context(), r.URL.Query().Get("token"))
+	if err != nil {
+		eh.logger.Printf("personal feed failed due to: %v", err)
+		http.Error(w, "failed to build calendar feed", serviceErrorStatus(err))
+		return
+	}
+
+	writeCalendar(w, cal, "events.ics")
+}
+
+func writeCalendar(w http.ResponseWriter, cal *ics.Calendar, filename string) {
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="`+filename+`"`)
+	//the token is in the URL, keep shared caches out of it
+	w.Header().Set("Cache-Control", "private, max-age=300")
+	w.WriteHeader(http.StatusOK)
+	ics.Write(w, cal)
+}
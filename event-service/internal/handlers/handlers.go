@@ -2,12 +2,18 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
 	"log"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"github.com/wycliff-ochieng/internal/models"
+	"github.com/wycliff-ochieng/internal/recurrence"
 	"github.com/wycliff-ochieng/internal/service"
 	auth "github.com/wycliff-ochieng/sports-common-package/middleware"
 )
@@ -56,10 +62,24 @@ func (eh *EventHandler) CreateEvent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	event, err := eh.es.CreateTeamEvent(ctx, reqUserID, createReq.EventID, createReq.Name, createReq.TeamID, createReq.EventType, createReq.Location, createReq.StartTime, createReq.EndTime)
+	if createReq.RRule != "" {
+		series, err := eh.es.CreateRecurringEvent(ctx, reqUserID, createReq)
+		if err != nil {
+			log.Printf("error : due to : %s", err)
+			writeServiceError(w, err)
+			return
+		}
+
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(&series)
+		return
+	}
+
+	event, err := eh.es.CreateTeamEvent(ctx, reqUserID, createReq)
 	if err != nil {
 		log.Printf("error : due to : %s", err)
-		http.Error(w, "Issue with event creation in event service layer/db ops", http.StatusFailedDependency)
+		writeServiceError(w, err)
 		return
 	}
 
@@ -105,46 +125,239 @@ func (eh *EventHandler) GetEventDet(w http.ResponseWriter, r *http.Request) {
 
 }
 
+// PUT :: /api/events/{event_id}?scope=this|following|all -> scope only matters for recurring events
 func (eh *EventHandler) UpdateEventDetails(w http.ResponseWriter, r *http.Request) {
 	eh.logger.Println("updating team event Details")
 
 	ctx := r.Context()
 
-	vars := mux.Vars(r)
+	eventID, err := uuid.Parse(mux.Vars(r)["event_id"])
+	if err != nil {
+		http.Error(w, "invalid event id", http.StatusBadRequest)
+		return
+	}
 
-	eventIDStr := vars["eventID"]
-	teamIDStr := vars["userID"]
-	eventID, err := uuid.Parse(eventIDStr)
+	reqUserID, err := auth.GetUserUUIDFromContext(ctx)
 	if err != nil {
-		log.Fatalf("error changing to uuid")
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	var update models.UpdateEventReq
+
+	err = json.NewDecoder(r.Body).Decode(&update)
+	if err != nil {
+		http.Error(w, "issue decoding request data", http.StatusBadRequest)
+		return
+	}
+
+	var updated interface{}
+
+	switch scope := r.URL.Query().Get("scope"); scope {
+	case "", service.ScopeThis:
+		updated, err = eh.es.UpdateEventDetails(ctx, reqUserID, eventID, update)
+	default:
+		updated, err = eh.es.UpdateEventSeries(ctx, reqUserID, eventID, scope, update)
+	}
+	if err != nil {
+		eh.logger.Printf("update event failed due to: %v", err)
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(updated)
+}
+
+// POST :: /api/events/{event_id}/cancel -> cancels the event, or this occurrence of a recurring one
+// DELETE :: /api/events/{event_id} -> the same, events are never removed so attendance and calendar feeds keep them
+func (eh *EventHandler) CancelEvent(w http.ResponseWriter, r *http.Request) {
+	eh.logger.Println("cancelling team event")
+
+	ctx := r.Context()
+
+	eventID, err := uuid.Parse(mux.Vars(r)["event_id"])
+	if err != nil {
+		http.Error(w, "invalid event id", http.StatusBadRequest)
+		return
 	}
 
 	reqUserID, err := auth.GetUserUUIDFromContext(ctx)
 	if err != nil {
-		http.Error(w, "error fetching userID from context", http.StatusInternalServerError)
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	//the reason is optional and so is the body
+	var cancelReq models.CancelEventReq
+	if err := json.NewDecoder(r.Body).Decode(&cancelReq); err != nil && err != io.EOF {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
 		return
 	}
 
-	teamID, err := uuid.Parse(teamIDStr)
+	event, err := eh.es.CancelOccurrence(ctx, reqUserID, eventID, cancelReq.Reason)
 	if err != nil {
-		log.Fatalf("issue changing user ID to uuid")
+		eh.logger.Printf("cancel event failed due to: %v", err)
+		http.Error(w, "failed to cancel event", serviceErrorStatus(err))
+		return
 	}
 
-	var update models.UpdateEventReq
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(&event)
+}
 
-	err = json.NewDecoder(r.Body).Decode(&update)
+// PUT :: /api/events/{event_id}/rsvp -> going, not going or maybe; coaches can answer for a player with user_id
+func (eh *EventHandler) RSVP(w http.ResponseWriter, r *http.Request) {
+	eh.logger.Println("responding to team event")
+
+	ctx := r.Context()
+
+	eventID, err := uuid.Parse(mux.Vars(r)["event_id"])
+	if err != nil {
+		http.Error(w, "invalid event id", http.StatusBadRequest)
+		return
+	}
+
+	reqUserID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.RSVPReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	res, err := eh.es.RespondToEvent(ctx, reqUserID, eventID, req)
+	if err != nil {
+		eh.logger.Printf("rsvp failed due to: %v", err)
+		http.Error(w, err.Error(), serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(res)
+}
+
+// writeServiceError answers with the conflict report when scheduling failed on conflicts, and
+// with the error text otherwise
+func writeServiceError(w http.ResponseWriter, err error) {
+	var conflict *service.ConflictError
+	if !errors.As(err, &conflict) {
+		http.Error(w, err.Error(), serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusConflict)
+	json.NewEncoder(w).Encode(struct {
+		Error     string
+		Conflicts *models.ConflictReport
+	}{err.Error(), conflict.Report})
+}
+
+// serviceErrorStatus maps service errors to HTTP status codes
+func serviceErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrInvalidFeedToken):
+		return http.StatusUnauthorized
+	case errors.Is(err, service.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, service.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, service.ErrInvalidEvent), errors.Is(err, service.ErrInvalidRange), errors.Is(err, service.ErrInvalidCursor), errors.Is(err, service.ErrNotRecurring), errors.Is(err, recurrence.ErrInvalidRule):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// GET :: /api/events?from=&to=&team_id=&type=&view=&tz=&include_cancelled=&limit=&cursor=
+// events of all the caller's teams, type can be repeated or comma separated, e.g. type=game,practice
+func (eh *EventHandler) ListEvents(w http.ResponseWriter, r *http.Request) {
+	eh.logger.Println("listing team events")
+
+	ctx := r.Context()
+
+	reqUserID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	query := r.URL.Query()
+
+	minLimit := 1
+	maxLimit := 200
+
+	params := models.ListEventsParams{
+		View:     strings.ToLower(query.Get("view")),
+		Location: time.UTC,
+		Cursor:   query.Get("cursor"),
+	}
+
+	if raw := query.Get("tz"); raw != "" {
+		params.Location, err = time.LoadLocation(raw)
+		if err != nil {
+			http.Error(w, "invalid tz, use an IANA time zone such as Africa/Nairobi", http.StatusBadRequest)
+			return
+		}
+	}
+	if raw := query.Get("team_id"); raw != "" {
+		params.TeamID, err = uuid.Parse(raw)
+		if err != nil {
+			http.Error(w, "invalid team id", http.StatusBadRequest)
+			return
+		}
+	}
+	if raw := query.Get("limit"); raw != "" {
+		params.Limit, err = strconv.Atoi(raw)
+		if err != nil || params.Limit < minLimit || params.Limit > maxLimit {
+			http.Error(w, "limit must be between 1 and 200", http.StatusBadRequest)
+			return
+		}
+	}
+	if raw := query.Get("include_cancelled"); raw != "" {
+		params.IncludeCancelled, err = strconv.ParseBool(raw)
+		if err != nil {
+			http.Error(w, "include_cancelled must be true or false", http.StatusBadRequest)
+			return
+		}
+	}
+	for _, raw := range query["type"] {
+		for _, t := range strings.Split(raw, ",") {
+			if t = strings.ToLower(strings.TrimSpace(t)); t != "" {
+				params.Types = append(params.Types, t)
+			}
+		}
+	}
+
+	params.From, err = parseDateParam(query.Get("from"), false, params.Location)
+	if err != nil {
+		http.Error(w, "invalid from, use YYYY-MM-DD or RFC 3339", http.StatusBadRequest)
+		return
+	}
+	params.To, err = parseDateParam(query.Get("to"), true, params.Location)
 	if err != nil {
-		http.Error(w, "issue decoding request data", http.StatusInternalServerError)
+		http.Error(w, "invalid to, use YYYY-MM-DD or RFC 3339", http.StatusBadRequest)
 		return
 	}
 
-	updateEvent, err := eh.es.UpdateEventDetails(ctx, reqUserID, teamID, eventID, update)
+	calendar, err := eh.es.ListEvents(ctx, reqUserID, params)
 	if err != nil {
-		http.Error(w, "Error updating events from service layer", http.StatusExpectationFailed)
+		eh.logger.Printf("list events failed due to: %v", err)
+		http.Error(w, err.Error(), serviceErrorStatus(err))
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(&updateEvent)
+	json.NewEncoder(w).Encode(calendar)
 }
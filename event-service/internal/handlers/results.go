@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/wycliff-ochieng/internal/models"
+	auth "github.com/wycliff-ochieng/sports-common-package/middleware"
+)
+
+// PUT :: /api/events/{event_id}/result -> a coach records or corrects the result of a game
+func (eh *EventHandler) RecordResult(w http.ResponseWriter, r *http.Request) {
+	eh.logger.Println("recording game result")
+
+	ctx := r.Context()
+
+	eventID, err := uuid.Parse(mux.Vars(r)["event_id"])
+	if err != nil {
+		http.Error(w, "invalid event id", http.StatusBadRequest)
+		return
+	}
+
+	reqUserID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.GameResultReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := eh.es.RecordResult(ctx, reqUserID, eventID, req)
+	if err != nil {
+		eh.logger.Printf("record result failed due to: %v", err)
+		http.Error(w, err.Error(), serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(result)
+}
+
+// GET :: /api/events/team/{team_id}/record?season_id= -> W/D/L and points of a season, the current one by default
+func (eh *EventHandler) SeasonRecord(w http.ResponseWriter, r *http.Request) {
+	eh.logger.Println("building season record")
+
+	ctx := r.Context()
+
+	teamID, err := uuid.Parse(mux.Vars(r)["team_id"])
+	if err != nil {
+		http.Error(w, "invalid team id", http.StatusBadRequest)
+		return
+	}
+
+	reqUserID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	var seasonID uuid.UUID
+	if v := r.URL.Query().Get("season_id"); v != "" {
+		if seasonID, err = uuid.Parse(v); err != nil {
+			http.Error(w, "invalid season id", http.StatusBadRequest)
+			return
+		}
+	}
+
+	record, err := eh.es.GetSeasonRecord(ctx, reqUserID, teamID, seasonID)
+	if err != nil {
+		eh.logger.Printf("season record failed due to: %v", err)
+		http.Error(w, err.Error(), serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(record)
+}
@@ -0,0 +1,269 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/wycliff-ochieng/internal/models"
+	auth "github.com/wycliff-ochieng/sports-common-package/middleware"
+)
+
+// GET :: /api/events/team/{team_id}/stat-definitions -> the stats the team tracks per player and game
+func (eh *EventHandler) StatDefinitions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	teamID, err := uuid.Parse(mux.Vars(r)["team_id"])
+	if err != nil {
+		http.Error(w, "invalid team id", http.StatusBadRequest)
+		return
+	}
+
+	reqUserID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	defs, err := eh.es.GetStatDefinitions(ctx, reqUserID, teamID)
+	if err != nil {
+		eh.logger.Printf("stat definitions failed due to: %v", err)
+		http.Error(w, err.Error(), serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(defs)
+}
+
+// PUT :: /api/events/team/{team_id}/stat-definitions/{stat_key} -> a coach adds a stat or replaces a sport default
+func (eh *EventHandler) SetStatDefinition(w http.ResponseWriter, r *http.Request) {
+	eh.logger.Println("setting stat definition")
+
+	ctx := r.Context()
+
+	vars := mux.Vars(r)
+	teamID, err := uuid.Parse(vars["team_id"])
+	if err != nil {
+		http.Error(w, "invalid team id", http.StatusBadRequest)
+		return
+	}
+
+	reqUserID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.StatDefinitionReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	defs, err := eh.es.SetStatDefinition(ctx, reqUserID, teamID, vars["stat_key"], req)
+	if err != nil {
+		eh.logger.Printf("set stat definition failed due to: %v", err)
+		http.Error(w, err.Error(), serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(defs)
+}
+
+// DELETE :: /api/events/team/{team_id}/stat-definitions/{stat_key} -> removes one of the team's own stats
+func (eh *EventHandler) DeleteStatDefinition(w http.ResponseWriter, r *http.Request) {
+	eh.logger.Println("deleting stat definition")
+
+	ctx := r.Context()
+
+	vars := mux.Vars(r)
+	teamID, err := uuid.Parse(vars["team_id"])
+	if err != nil {
+		http.Error(w, "invalid team id", http.StatusBadRequest)
+		return
+	}
+
+	reqUserID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	if err := eh.es.DeleteStatDefinition(ctx, reqUserID, teamID, vars["stat_key"]); err != nil {
+		eh.logger.Printf("delete stat definition failed due to: %v", err)
+		http.Error(w, err.Error(), serviceErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// PUT :: /api/events/{event_id}/stats -> a coach records the stats of players on the roster for a game
+func (eh *EventHandler) RecordGameStats(w http.ResponseWriter, r *http.Request) {
+	eh.logger.Println("recording game stats")
+
+	ctx := r.Context()
+
+	eventID, err := uuid.Parse(mux.Vars(r)["event_id"])
+	if err != nil {
+		http.Error(w, "invalid event id", http.StatusBadRequest)
+		return
+	}
+
+	reqUserID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.RecordGameStatsReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	stats, err := eh.es.RecordGameStats(ctx, reqUserID, eventID, req)
+	if err != nil {
+		eh.logger.Printf("record game stats failed due to: %v", err)
+		http.Error(w, err.Error(), serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(stats)
+}
+
+// GET :: /api/events/{event_id}/stats -> the stats recorded for each player in a game
+func (eh *EventHandler) GameStats(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	eventID, err := uuid.Parse(mux.Vars(r)["event_id"])
+	if err != nil {
+		http.Error(w, "invalid event id", http.StatusBadRequest)
+		return
+	}
+
+	reqUserID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	stats, err := eh.es.GetGameStats(ctx, reqUserID, eventID)
+	if err != nil {
+		eh.logger.Printf("game stats failed due to: %v", err)
+		http.Error(w, err.Error(), serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(stats)
+}
+
+// GET :: /api/events/team/{team_id}/stats?season_id= -> totals and per-game averages of each player, the current season by default
+func (eh *EventHandler) SeasonStats(w http.ResponseWriter, r *http.Request) {
+	eh.logger.Println("building season stats")
+
+	ctx := r.Context()
+
+	teamID, err := uuid.Parse(mux.Vars(r)["team_id"])
+	if err != nil {
+		http.Error(w, "invalid team id", http.StatusBadRequest)
+		return
+	}
+
+	reqUserID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	var seasonID uuid.UUID
+	if v := r.URL.Query().Get("season_id"); v != "" {
+		if seasonID, err = uuid.Parse(v); err != nil {
+			http.Error(w, "invalid season id", http.StatusBadRequest)
+			return
+		}
+	}
+
+	stats, err := eh.es.GetSeasonStats(ctx, reqUserID, teamID, seasonID)
+	if err != nil {
+		eh.logger.Printf("season stats failed due to: %v", err)
+		http.Error(w, err.Error(), serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(stats)
+}
+
+// GET :: /api/events/team/{team_id}/stats/leaders?stat=&season_id=&per_game=&limit= -> players ranked by a stat
+func (eh *EventHandler) StatLeaders(w http.ResponseWriter, r *http.Request) {
+	eh.logger.Println("building stat leaderboard")
+
+	ctx := r.Context()
+
+	teamID, err := uuid.Parse(mux.Vars(r)["team_id"])
+	if err != nil {
+		http.Error(w, "invalid team id", http.StatusBadRequest)
+		return
+	}
+
+	reqUserID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	query := r.URL.Query()
+
+	stat := query.Get("stat")
+	if stat == "" {
+		http.Error(w, "stat is required", http.StatusBadRequest)
+		return
+	}
+
+	var seasonID uuid.UUID
+	if v := query.Get("season_id"); v != "" {
+		if seasonID, err = uuid.Parse(v); err != nil {
+			http.Error(w, "invalid season id", http.StatusBadRequest)
+			return
+		}
+	}
+
+	var perGame bool
+	if v := query.Get("per_game"); v != "" {
+		if perGame, err = strconv.ParseBool(v); err != nil {
+			http.Error(w, "invalid per_game, use true or false", http.StatusBadRequest)
+			return
+		}
+	}
+
+	var limit int
+	if v := query.Get("limit"); v != "" {
+		if limit, err = strconv.Atoi(v); err != nil || limit < 1 {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+	}
+
+	board, err := eh.es.GetStatLeaders(ctx, reqUserID, teamID, seasonID, stat, perGame, limit)
+	if err != nil {
+		eh.logger.Printf("stat leaders failed due to: %v", err)
+		http.Error(w, err.Error(), serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(board)
+}
@@ -0,0 +1,293 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/wycliff-ochieng/internal/models"
+	auth "github.com/wycliff-ochieng/sports-common-package/middleware"
+)
+
+// POST :: /api/venues -> a new venue with its courts and opening hours, the caller becomes its admin
+func (eh *EventHandler) CreateVenue(w http.ResponseWriter, r *http.Request) {
+	eh.logger.Println("creating venue")
+
+	ctx := r.Context()
+
+	reqUserID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.VenueReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	venue, err := eh.es.CreateVenue(ctx, reqUserID, req)
+	if err != nil {
+		eh.logger.Printf("create venue failed due to: %v", err)
+		http.Error(w, err.Error(), serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(venue)
+}
+
+// GET :: /api/venues?search= -> venues matching the name or address
+func (eh *EventHandler) ListVenues(w http.ResponseWriter, r *http.Request) {
+	eh.logger.Println("listing venues")
+
+	venues, err := eh.es.ListVenues(r.Context(), r.URL.Query().Get("search"))
+	if err != nil {
+		eh.logger.Printf("list venues failed due to: %v", err)
+		http.Error(w, err.Error(), serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(venues)
+}
+
+// GET :: /api/venues/{venue_id} -> the venue with its resources and opening hours
+func (eh *EventHandler) GetVenue(w http.ResponseWriter, r *http.Request) {
+	venueID, err := uuid.Parse(mux.Vars(r)["venue_id"])
+	if err != nil {
+		http.Error(w, "invalid venue id", http.StatusBadRequest)
+		return
+	}
+
+	venue, err := eh.es.GetVenue(r.Context(), venueID)
+	if err != nil {
+		eh.logger.Printf("get venue failed due to: %v", err)
+		http.Error(w, err.Error(), serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(venue)
+}
+
+// PUT :: /api/venues/{venue_id} -> venue admins replace its details and opening hours
+func (eh *EventHandler) UpdateVenue(w http.ResponseWriter, r *http.Request) {
+	eh.logger.Println("updating venue")
+
+	ctx := r.Context()
+
+	venueID, err := uuid.Parse(mux.Vars(r)["venue_id"])
+	if err != nil {
+		http.Error(w, "invalid venue id", http.StatusBadRequest)
+		return
+	}
+
+	reqUserID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.VenueReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	venue, err := eh.es.UpdateVenue(ctx, reqUserID, venueID, req)
+	if err != nil {
+		eh.logger.Printf("update venue failed due to: %v", err)
+		http.Error(w, err.Error(), serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(venue)
+}
+
+// POST :: /api/venues/{venue_id}/resources -> venue admins add a court or pitch
+func (eh *EventHandler) AddVenueResource(w http.ResponseWriter, r *http.Request) {
+	eh.logger.Println("adding venue resource")
+
+	ctx := r.Context()
+
+	venueID, err := uuid.Parse(mux.Vars(r)["venue_id"])
+	if err != nil {
+		http.Error(w, "invalid venue id", http.StatusBadRequest)
+		return
+	}
+
+	reqUserID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.VenueResourceReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	resource, err := eh.es.AddVenueResource(ctx, reqUserID, venueID, req)
+	if err != nil {
+		eh.logger.Printf("add venue resource failed due to: %v", err)
+		http.Error(w, err.Error(), serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(resource)
+}
+
+// POST :: /api/venues/{venue_id}/admins -> venue admins let another user manage the venue
+func (eh *EventHandler) AddVenueAdmin(w http.ResponseWriter, r *http.Request) {
+	eh.logger.Println("adding venue admin")
+
+	ctx := r.Context()
+
+	venueID, err := uuid.Parse(mux.Vars(r)["venue_id"])
+	if err != nil {
+		http.Error(w, "invalid venue id", http.StatusBadRequest)
+		return
+	}
+
+	reqUserID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.VenueAdminReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := eh.es.AddVenueAdmin(ctx, reqUserID, venueID, req.UserID); err != nil {
+		eh.logger.Printf("add venue admin failed due to: %v", err)
+		http.Error(w, err.Error(), serviceErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// POST :: /api/venues/{venue_id}/blockouts -> venue admins block out time, the events already booked are returned
+func (eh *EventHandler) BlockOutVenue(w http.ResponseWriter, r *http.Request) {
+	eh.logger.Println("blocking out venue time")
+
+	ctx := r.Context()
+
+	venueID, err := uuid.Parse(mux.Vars(r)["venue_id"])
+	if err != nil {
+		http.Error(w, "invalid venue id", http.StatusBadRequest)
+		return
+	}
+
+	reqUserID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.BlockOutReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	res, err := eh.es.BlockOutVenue(ctx, reqUserID, venueID, req)
+	if err != nil {
+		eh.logger.Printf("block out venue failed due to: %v", err)
+		http.Error(w, err.Error(), serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(res)
+}
+
+// DELETE :: /api/venues/{venue_id}/blockouts/{blockout_id}
+func (eh *EventHandler) DeleteBlockOut(w http.ResponseWriter, r *http.Request) {
+	eh.logger.Println("removing venue block-out")
+
+	ctx := r.Context()
+
+	vars := mux.Vars(r)
+	venueID, err := uuid.Parse(vars["venue_id"])
+	if err != nil {
+		http.Error(w, "invalid venue id", http.StatusBadRequest)
+		return
+	}
+	blockOutID, err := uuid.Parse(vars["blockout_id"])
+	if err != nil {
+		http.Error(w, "invalid block-out id", http.StatusBadRequest)
+		return
+	}
+
+	reqUserID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	if err := eh.es.DeleteBlockOut(ctx, reqUserID, venueID, blockOutID); err != nil {
+		eh.logger.Printf("delete block-out failed due to: %v", err)
+		http.Error(w, err.Error(), serviceErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GET :: /api/venues/{venue_id}/calendar?from=&to= -> events and block-outs at the venue, a week by default
+func (eh *EventHandler) VenueCalendar(w http.ResponseWriter, r *http.Request) {
+	eh.logger.Println("building venue calendar")
+
+	ctx := r.Context()
+
+	venueID, err := uuid.Parse(mux.Vars(r)["venue_id"])
+	if err != nil {
+		http.Error(w, "invalid venue id", http.StatusBadRequest)
+		return
+	}
+
+	reqUserID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	from, err := parseDateParam(r.URL.Query().Get("from"), false, time.UTC)
+	if err != nil {
+		http.Error(w, "invalid from, use YYYY-MM-DD or RFC 3339", http.StatusBadRequest)
+		return
+	}
+	to, err := parseDateParam(r.URL.Query().Get("to"), true, time.UTC)
+	if err != nil {
+		http.Error(w, "invalid to, use YYYY-MM-DD or RFC 3339", http.StatusBadRequest)
+		return
+	}
+
+	calendar, err := eh.es.GetVenueCalendar(ctx, reqUserID, venueID, from, to)
+	if err != nil {
+		eh.logger.Printf("venue calendar failed due to: %v", err)
+		http.Error(w, err.Error(), serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(calendar)
+}
@@ -0,0 +1,141 @@
+// Package ics writes iCalendar (RFC 5545) feeds that calendar apps subscribe to.
+package ics
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	prodID      = "-//Sports Platform//Event Service//EN"
+	utcLayout   = "20060102T150405Z"
+	maxLineSize = 75
+)
+
+// Event statuses understood by calendar apps
+const (
+	StatusConfirmed = "CONFIRMED"
+	StatusCancelled = "CANCELLED"
+)
+
+// Event is one VEVENT. UID must never change for the same event and Sequence must grow with every
+// change, otherwise subscribers keep the old copy
+type Event struct {
+	UID          string
+	Sequence     int
+	Status       string
+	Summary      string
+	Description  string
+	Location     string
+	Categories   string
+	Start        time.Time
+	End          time.Time
+	Created      time.Time
+	LastModified time.Time
+}
+
+type Calendar struct {
+	Name string
+	// how often subscribers should refetch, zero leaves it to the app
+	RefreshInterval time.Duration
+	Events          []Event
+}
+
+// Write encodes cal, with CRLF line endings and long lines folded
+func Write(w io.Writer, cal *Calendar) error {
+	bw := bufio.NewWriter(w)
+	line := func(name string, value string) {
+		writeFolded(bw, name+":"+value)
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", prodID)
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	if cal.Name != "" {
+		line("X-WR-CALNAME", escape(cal.Name))
+	}
+	if cal.RefreshInterval > 0 {
+		minutes := strconv.Itoa(int(cal.RefreshInterval.Minutes()))
+		line("REFRESH-INTERVAL;VALUE=DURATION", "PT"+minutes+"M")
+		line("X-PUBLISHED-TTL", "PT"+minutes+"M")
+	}
+
+	now := time.Now()
+	for _, e := range cal.Events {
+		line("BEGIN", "VEVENT")
+		line("UID", escape(e.UID))
+		stamp := e.LastModified
+		if stamp.IsZero() {
+			stamp = now
+		}
+		line("DTSTAMP", utc(stamp))
+		line("DTSTART", utc(e.Start))
+		if !e.End.IsZero() {
+			line("DTEND", utc(e.End))
+		}
+		line("SEQUENCE", strconv.Itoa(e.Sequence))
+		status := e.Status
+		if status == "" {
+			status = StatusConfirmed
+		}
+		line("STATUS", status)
+		line("SUMMARY", escape(e.Summary))
+		if e.Description != "" {
+			line("DESCRIPTION", escape(e.Description))
+		}
+		if e.Location != "" {
+			line("LOCATION", escape(e.Location))
+		}
+		if e.Categories != "" {
+			line("CATEGORIES", escape(e.Categories))
+		}
+		if !e.Created.IsZero() {
+			line("CREATED", utc(e.Created))
+		}
+		if !e.LastModified.IsZero() {
+			line("LAST-MODIFIED", utc(e.LastModified))
+		}
+		line("END", "VEVENT")
+	}
+
+	line("END", "VCALENDAR")
+	return bw.Flush()
+}
+
+func utc(t time.Time) string {
+	return t.UTC().Format(utcLayout)
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+
+// escape makes a TEXT value safe to put on a content line
+func escape(s string) string {
+	return escaper.Replace(s)
+}
+
+// writeFolded writes a content line, folding it every 75 octets without splitting a UTF-8 sequence
+func writeFolded(w *bufio.Writer, s string) {
+	limit := maxLineSize
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(s[cut]) {
+			cut--
+		}
+		w.WriteString(s[:cut])
+		w.WriteString("\r\n ")
+		s = s[cut:]
+		//the leading space of a continuation line counts towards its length
+		limit = maxLineSize - 1
+	}
+	w.WriteString(s)
+	w.WriteString("\r\n")
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
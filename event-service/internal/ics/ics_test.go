@@ -0,0 +1,61 @@
+package ics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestWriteEscapesAndFolds(t *testing.T) {
+	start := time.Date(2025, 1, 14, 18, 0, 0, 0, time.FixedZone("EAT", 3*60*60))
+	cal := &Calendar{
+		Name: "Champions United",
+		Events: []Event{{
+			UID:         "550e8400-e29b-41d4-a716-446655440000@events",
+			Sequence:    2,
+			Status:      StatusCancelled,
+			Summary:     "Practice; bring boots, water",
+			Description: strings.Repeat("Ünïcödé ", 20) + "\nsecond line",
+			Location:    "Central Sports Complex, Pitch 2",
+			Start:       start,
+			End:         start.Add(90 * time.Minute),
+		}},
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, cal); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"DTSTART:20250114T150000Z\r\n",
+		"DTEND:20250114T163000Z\r\n",
+		"SEQUENCE:2\r\n",
+		"STATUS:CANCELLED\r\n",
+		`SUMMARY:Practice\; bring boots\, water` + "\r\n",
+		`LOCATION:Central Sports Complex\, Pitch 2` + "\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("feed is missing %q", want)
+		}
+	}
+
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("line longer than 75 octets: %q", line)
+		}
+		if !utf8.ValidString(line) {
+			t.Errorf("fold split a character: %q", line)
+		}
+	}
+
+	unfolded := strings.ReplaceAll(out, "\r\n ", "")
+	if !strings.Contains(unfolded, `second line`) || !strings.Contains(unfolded, `Ünïcödé \nsecond`) {
+		t.Errorf("description did not survive folding: %q", unfolded)
+	}
+}
@@ -6,6 +6,26 @@ import (
 	"github.com/google/uuid"
 )
 
+// event statuses, a cancelled occurrence stays listed so calendars can drop it
+const (
+	StatusScheduled = "SCHEDULED"
+	StatusCancelled = "CANCELLED"
+)
+
+// check-in statuses, what actually happened as opposed to the RSVP
+const (
+	CheckInPresent = "PRESENT"
+	CheckInLate    = "LATE"
+	CheckInAbsent  = "ABSENT"
+	CheckInExcused = "EXCUSED"
+)
+
+// how a check-in was recorded, a coach's record replaces a self check-in but not the other way round
+const (
+	CheckInByCoach = "COACH"
+	CheckInBySelf  = "SELF"
+)
+
 type Event struct {
 	ID        uuid.UUID
 	TeamID    uuid.UUID
@@ -16,6 +36,100 @@ type Event struct {
 	Location  string
 	Notes     string
 	TeamName  string
+	CreatedBy uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Status    string
+	//set on occurrences of a recurring event, OccurrenceDate is the local date the rule produced
+	SeriesID       uuid.NullUUID
+	OccurrenceDate string
+	IsException    bool
+	//grows with every change, calendar feeds send it as SEQUENCE
+	Sequence int
+	//nil means members can answer until the event starts
+	RSVPDeadline *time.Time
+	//set when the event is booked at a venue, ResourceID when it is on one of its courts
+	VenueID    uuid.NullUUID
+	ResourceID uuid.NullUUID
+}
+
+// EventSeries is a recurring event, its occurrences are stored as events up to MaterializedUntil
+type EventSeries struct {
+	SeriesID          uuid.UUID
+	TeamID            uuid.UUID
+	CreatedBy         uuid.UUID
+	Title             string
+	EventType         string
+	Location          string
+	Notes             string
+	RRule             string
+	TimeZone          string
+	StartTime         time.Time
+	DurationMinutes   int
+	MaterializedUntil time.Time
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+	VenueID           uuid.NullUUID
+	ResourceID        uuid.NullUUID
+	Occurrences       []Event
+}
+
+// calendar views of GET /api/events
+const (
+	ViewAgenda = "agenda"
+	ViewWeek   = "week"
+	ViewMonth  = "month"
+)
+
+// ListEventsParams filters GET /api/events, a nil TeamID lists every team of the caller and an
+// empty Types every type
+type ListEventsParams struct {
+	From             time.Time
+	To               time.Time
+	TeamID           uuid.UUID
+	Types            []string
+	View             string
+	Location         *time.Location
+	IncludeCancelled bool
+	Limit            int
+	Cursor           string
+}
+
+// CalendarEvent is an event in a listing, MyRSVP is the caller's answer and empty when they are
+// not on its attendance list
+type CalendarEvent struct {
+	Event
+	MyRSVP string
+}
+
+// CalendarDay holds the events starting on one local date
+type CalendarDay struct {
+	Date   string
+	Events []CalendarEvent
+}
+
+// EventCalendar is the agenda as a flat page of Events, or a week or month as Days with every
+// date of the range, empty ones included
+type EventCalendar struct {
+	View       string
+	TimeZone   string
+	From       time.Time
+	To         time.Time
+	Events     []CalendarEvent
+	Days       []CalendarDay
+	NextCursor string
+}
+
+type CancelEventReq struct {
+	Reason string
+}
+
+// FeedToken authenticates calendar subscriptions, Token is only returned when it is issued
+type FeedToken struct {
+	Token        string
+	PersonalFeed string
+	TeamFeed     string
+	CreatedAt    time.Time
 }
 
 type Attendance struct {
@@ -24,7 +138,94 @@ type Attendance struct {
 	UserID  uuid.UUID
 	Status  string
 	//UserName   string
-	UpdateteAt time.Time
+	UpdateteAt  time.Time
+	Reason      string
+	RespondedAt *time.Time
+	UpdatedBy   uuid.NullUUID
+	Overridden  bool
+	//empty until the member checks in or a coach records them
+	CheckInStatus string
+	CheckInMethod string
+}
+
+// RSVPReq answers for the caller, or for UserID when a coach overrides a member's response
+type RSVPReq struct {
+	Status string
+	Reason string
+	UserID uuid.UUID
+}
+
+// AttendanceSummary counts the RSVP statuses of an event
+type AttendanceSummary struct {
+	Going    int
+	NotGoing int
+	Maybe    int
+	Pending  int
+	Total    int
+}
+
+type RSVPResponse struct {
+	Attendance Attendance
+	Summary    AttendanceSummary
+}
+
+type CheckIn struct {
+	EventID    uuid.UUID
+	TeamID     uuid.UUID
+	UserID     uuid.UUID
+	Status     string
+	Method     string
+	Note       string
+	RecordedBy uuid.UUID
+	RecordedAt time.Time
+	UpdatedAt  time.Time
+}
+
+type CheckInReq struct {
+	UserID uuid.UUID
+	Status string
+	Note   string
+}
+
+// RecordCheckInsReq is a coach taking attendance, one entry per player
+type RecordCheckInsReq struct {
+	CheckIns []CheckInReq
+}
+
+type SelfCheckInReq struct {
+	Code string
+}
+
+// CheckInCode is shown by the coach, CheckInURL is what the QR code encodes
+type CheckInCode struct {
+	EventID    uuid.UUID
+	Code       string
+	CheckInURL string
+	OpensAt    time.Time
+	ClosesAt   time.Time
+}
+
+// PlayerAttendanceStats covers the past, not cancelled events of a date range the player was on
+// the roster for. Rate is (present+late)/(present+late+absent), excused and unrecorded events
+// do not count against it
+type PlayerAttendanceStats struct {
+	UserID     uuid.UUID
+	FirstName  string
+	LastName   string
+	Events     int
+	Present    int
+	Late       int
+	Absent     int
+	Excused    int
+	Unrecorded int
+	Rate       float64
+}
+
+type AttendanceReport struct {
+	TeamID  uuid.UUID
+	From    time.Time
+	To      time.Time
+	Players []PlayerAttendanceStats
 }
 
 type CreateEventReq struct {
@@ -33,8 +234,19 @@ type CreateEventReq struct {
 	Name      string
 	EventType string
 	Location  string
+	Notes     string
 	StartTime time.Time
 	EndTime   time.Time
+	//optional, makes the event recurring from StartTime e.g. FREQ=WEEKLY;BYDAY=TU,TH;COUNT=20
+	RRule    string
+	TimeZone string
+	//single events only, occurrences of a series close when they start
+	RSVPDeadline *time.Time
+	//optional, books a venue or one of its courts, Location defaults to their names
+	VenueID    uuid.UUID
+	ResourceID uuid.UUID
+	//schedule despite conflicts, needs events.manage
+	Force bool
 }
 
 type AttendanceResponse struct {
@@ -43,24 +255,350 @@ type AttendanceResponse struct {
 	LastName    string
 	EventStatus string
 	Email       string
+	Reason      string
+	RespondedAt *time.Time
+	Overridden  bool
+	CheckIn     string
 }
 
 type EventDetails struct {
 	EventID    uuid.UUID
 	TeamID     uuid.UUID
 	EventName  string
+	EventType  string
 	Location   string
+	VenueID    uuid.NullUUID
+	ResourceID uuid.NullUUID
+	Notes      string
+	CreatedBy  uuid.UUID
+	Status     string
+	SeriesID   uuid.NullUUID
+	Occurrence string
 	StartTime  time.Time
 	EndTime    time.Time
-	Attendance []AttendanceResponse
+	//RSVPs are accepted until here
+	RSVPDeadline time.Time
+	RSVPSummary  AttendanceSummary
+	Attendance   []AttendanceResponse
+	//games only, nil until the result is recorded
+	Result *GameResult
 }
 
 type UpdateEventReq struct {
 	Title     string
 	EventType string
 	Location  string
+	Notes     string
 	StartTime time.Time
 	EndTime   time.Time
+	//only read when a whole series or the following occurrences are edited
+	RRule    string
+	TimeZone string
+	//only applied to a single event or occurrence
+	RSVPDeadline *time.Time
+	//empty moves the event off its venue
+	VenueID    uuid.UUID
+	ResourceID uuid.UUID
+	//save despite conflicts, needs events.manage
+	Force bool
+}
+
+// ConflictReport lists the scheduled events a create or update overlaps. Team holds events of the
+// same team, Players the other teams' events of its rostered players and Venue events at the same
+// place. BlockOuts and OutsideHours are venue rules, forcing does not skip them
+type ConflictReport struct {
+	Team         []ConflictingEvent
+	Players      []PlayerConflict
+	Venue        []ConflictingEvent
+	BlockOuts    []BlockOutConflict
+	OutsideHours []uuid.UUID
+}
+
+// ConflictingEvent is an existing event, ConflictsWith is the event or occurrence being scheduled
+// that it overlaps
+type ConflictingEvent struct {
+	EventID       uuid.UUID
+	TeamID        uuid.UUID
+	Title         string
+	Location      string
+	StartTime     time.Time
+	EndTime       time.Time
+	ConflictsWith uuid.UUID
+}
+
+type PlayerConflict struct {
+	UserID uuid.UUID
+	Events []ConflictingEvent
+}
+
+// BlockOutConflict is venue time an admin blocked out that the event being scheduled overlaps
+type BlockOutConflict struct {
+	BlockOutID    uuid.UUID
+	ResourceID    uuid.NullUUID
+	StartTime     time.Time
+	EndTime       time.Time
+	Reason        string
+	ConflictsWith uuid.UUID
+}
+
+// Venue is a place events are booked at, Resources are its courts or pitches. Without
+// OpeningHours it is always open, the hours are read in TimeZone
+type Venue struct {
+	VenueID      uuid.UUID
+	Name         string
+	Address      string
+	Latitude     *float64
+	Longitude    *float64
+	Capacity     int
+	TimeZone     string
+	CreatedBy    uuid.UUID
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	Resources    []VenueResource
+	OpeningHours []OpeningHours
+}
+
+type VenueResource struct {
+	ResourceID uuid.UUID
+	VenueID    uuid.UUID
+	Name       string
+	Surface    string
+	Capacity   int
+}
+
+// OpeningHours is one opening on a weekday, 0 is Sunday, Opens and Closes are HH:MM
+type OpeningHours struct {
+	Weekday int
+	Opens   string
+	Closes  string
+}
+
+// VenueReq creates a venue or replaces its details and opening hours, Resources are only read on create
+type VenueReq struct {
+	Name         string
+	Address      string
+	Latitude     *float64
+	Longitude    *float64
+	Capacity     int
+	TimeZone     string
+	Resources    []VenueResourceReq
+	OpeningHours []OpeningHours
+}
+
+type VenueResourceReq struct {
+	Name     string
+	Surface  string
+	Capacity int
+}
+
+type VenueAdminReq struct {
+	UserID uuid.UUID
+}
+
+// BlockOut keeps events off a venue, or off one resource when ResourceID is set
+type BlockOut struct {
+	BlockOutID uuid.UUID
+	VenueID    uuid.UUID
+	ResourceID uuid.NullUUID
+	StartTime  time.Time
+	EndTime    time.Time
+	Reason     string
+	CreatedBy  uuid.UUID
+	CreatedAt  time.Time
+}
+
+type BlockOutReq struct {
+	ResourceID uuid.UUID
+	StartTime  time.Time
+	EndTime    time.Time
+	Reason     string
+}
+
+// BlockOutResult is a new block-out with the scheduled events already booked in it, they are left
+// for their teams to move
+type BlockOutResult struct {
+	BlockOut BlockOut
+	Affected []ConflictingEvent
+}
+
+// kinds of venue bookings
+const (
+	BookingEvent    = "EVENT"
+	BookingBlockOut = "BLOCKOUT"
+)
+
+// VenueBooking is an event or block-out on a venue calendar, ID is the event or block-out and Title
+// a block-out's reason. Only venue admins see ID, TeamID and Title, everyone else sees when it is busy
+type VenueBooking struct {
+	Kind         string
+	ID           uuid.UUID
+	TeamID       uuid.UUID
+	Title        string
+	ResourceID   uuid.NullUUID
+	ResourceName string
+	StartTime    time.Time
+	EndTime      time.Time
+}
+
+type VenueCalendar struct {
+	VenueID  uuid.UUID
+	From     time.Time
+	To       time.Time
+	Bookings []VenueBooking
+}
+
+// results are recorded on events of this type
+const EventTypeGame = "game"
+
+// where a game was played, from the team's point of view
+const (
+	GameHome    = "HOME"
+	GameAway    = "AWAY"
+	GameNeutral = "NEUTRAL"
+)
+
+const (
+	ResultWin  = "W"
+	ResultDraw = "D"
+	ResultLoss = "L"
+)
+
+// PeriodScore is the score of one half, quarter, set, overtime or shootout
+type PeriodScore struct {
+	Period  string
+	For     int
+	Against int
+}
+
+// GameResult is the outcome of a game event for the team that owns it. DecidedBy is the shootout
+// period that broke a tie, the final score does not include it
+type GameResult struct {
+	EventID        uuid.UUID
+	TeamID         uuid.UUID
+	Title          string
+	StartTime      time.Time
+	SeasonID       uuid.NullUUID
+	SeasonName     string
+	OpponentTeamID uuid.NullUUID
+	OpponentName   string
+	HomeAway       string
+	ScoreFor       int
+	ScoreAgainst   int
+	Periods        []PeriodScore
+	Result         string
+	DecidedBy      string
+	RecordedBy     uuid.UUID
+	RecordedAt     time.Time
+	UpdatedAt      time.Time
+}
+
+// GameResultReq records or corrects a result. The opponent is OpponentTeamID when it is a team in
+// the system, OpponentName otherwise. The score can be left out when Periods are sent
+type GameResultReq struct {
+	OpponentTeamID uuid.UUID
+	OpponentName   string
+	HomeAway       string
+	ScoreFor       *int
+	ScoreAgainst   *int
+	Periods        []PeriodScore
+}
+
+// SeasonRecord is a team's results over a season, or over every result when the team has no
+// season. Form is the last five results, oldest first
+type SeasonRecord struct {
+	TeamID          uuid.UUID
+	SeasonID        uuid.NullUUID
+	SeasonName      string
+	Played          int
+	Won             int
+	Drawn           int
+	Lost            int
+	PointsFor       int
+	PointsAgainst   int
+	PointDifference int
+	Form            string
+	Results         []GameResult
+}
+
+// StatDefinition is a stat tracked per player and game. TeamID is set on a team's own stats, which
+// add to the defaults of its sport or replace the default with the same Key
+type StatDefinition struct {
+	Key       string
+	Name      string
+	MinValue  int
+	MaxValue  *int
+	SortOrder int
+	TeamID    uuid.NullUUID
+}
+
+type StatDefinitionReq struct {
+	Name      string
+	MinValue  int
+	MaxValue  *int
+	SortOrder int
+}
+
+// PlayerStatsReq replaces a player's stats for a game, empty Stats removes them
+type PlayerStatsReq struct {
+	UserID uuid.UUID
+	Stats  map[string]int
+}
+
+type RecordGameStatsReq struct {
+	Players []PlayerStatsReq
+}
+
+type PlayerGameStats struct {
+	UserID uuid.UUID
+	Stats  map[string]int
+}
+
+type GameStats struct {
+	EventID     uuid.UUID
+	TeamID      uuid.UUID
+	SeasonID    uuid.NullUUID
+	SeasonName  string
+	Definitions []StatDefinition
+	Players     []PlayerGameStats
+}
+
+// PlayerSeasonStats totals a player's stats over the games they have stats for, Averages are per
+// one of those games
+type PlayerSeasonStats struct {
+	UserID      uuid.UUID
+	FirstName   string
+	LastName    string
+	GamesPlayed int
+	Totals      map[string]int
+	Averages    map[string]float64
+}
+
+// SeasonStats covers a season, or every game when the team has no season
+type SeasonStats struct {
+	TeamID      uuid.UUID
+	SeasonID    uuid.NullUUID
+	SeasonName  string
+	Definitions []StatDefinition
+	Players     []PlayerSeasonStats
+}
+
+// StatLeader is a place on a leaderboard, players with the same Value share the Rank
+type StatLeader struct {
+	Rank        int
+	UserID      uuid.UUID
+	FirstName   string
+	LastName    string
+	GamesPlayed int
+	Value       float64
+}
+
+type StatLeaderboard struct {
+	TeamID     uuid.UUID
+	SeasonID   uuid.NullUUID
+	SeasonName string
+	Stat       string
+	PerGame    bool
+	Leaders    []StatLeader
 }
 
 func NewEvent(teamID uuid.UUID, name string, eventype string, Location string, start, end time.Time) (*Event, error) {
@@ -0,0 +1,67 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/wycliff-ochieng/common_packages/events"
+)
+
+// ProducerName is stamped on the envelope of every event this service publishes
+const ProducerName = "event-service"
+
+type KafkaProducer interface {
+	PublishEventUpdate(ctx context.Context, event events.Payload) error
+}
+
+type UpdateEvent struct {
+	producer   *kafka.Producer
+	topic      string
+	deliverych chan kafka.Event
+}
+
+func NewUpdateEvent(p *kafka.Producer, topic string) *UpdateEvent {
+	u := &UpdateEvent{
+		producer:   p,
+		topic:      topic,
+		deliverych: make(chan kafka.Event, 1000),
+	}
+	//every RSVP publishes, delivery reports are drained so they never back up the producer
+	go u.drainDeliveries()
+	return u
+}
+
+// PublishEventUpdate validates the event and publishes it wrapped in the shared envelope
+func (u *UpdateEvent) PublishEventUpdate(ctx context.Context, event events.Payload) error {
+
+	data, err := events.Marshal(ProducerName, event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", event.EventType(), err)
+	}
+
+	return u.producer.Produce(&kafka.Message{
+		TopicPartition: kafka.TopicPartition{
+			Topic:     &u.topic,
+			Partition: kafka.PartitionAny,
+		},
+		Value: data,
+	}, u.deliverych)
+}
+
+func (u *UpdateEvent) drainDeliveries() {
+	for e := range u.deliverych {
+		if m, ok := e.(*kafka.Message); ok && m.TopicPartition.Error != nil {
+			log.Printf("event not delivered to %s: %v", u.topic, m.TopicPartition.Error)
+		}
+	}
+}
+
+func InitKafkaProducer(broker string) (*kafka.Producer, error) {
+	return kafka.NewProducer(&kafka.ConfigMap{
+		"bootstrap.servers": broker,
+		"client.id":         ProducerName,
+		"acks":              "all",
+	})
+}
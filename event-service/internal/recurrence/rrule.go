@@ -0,0 +1,226 @@
+// Package recurrence expands the subset of iCalendar RRULEs (RFC 5545) that team schedules need:
+// daily or weekly rules with INTERVAL, BYDAY and either UNTIL or COUNT.
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	// the runtime image ships without a zoneinfo database
+	_ "time/tzdata"
+)
+
+const (
+	FreqDaily  = "DAILY"
+	FreqWeekly = "WEEKLY"
+
+	// MaxCount bounds COUNT so a single rule cannot create years of practices
+	MaxCount = 730
+
+	untilLayout    = "20060102T150405"
+	untilLayoutUTC = "20060102T150405Z"
+	dateLayout     = "20060102"
+)
+
+var ErrInvalidRule = errors.New("invalid recurrence rule")
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// Rule is a parsed RRULE. Until is an absolute instant, zero when the rule ends by count or never
+type Rule struct {
+	Freq     string
+	Interval int
+	ByDay    []time.Weekday
+	Until    time.Time
+	Count    int
+}
+
+// Parse reads an RRULE value, with or without the "RRULE:" prefix. A floating UNTIL (no Z) is
+// read in loc
+func Parse(s string, loc *time.Location) (*Rule, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+	if s == "" {
+		return nil, fmt.Errorf("%w: empty rule", ErrInvalidRule)
+	}
+
+	r := &Rule{Interval: 1}
+	for _, part := range strings.Split(s, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return nil, fmt.Errorf("%w: malformed part %q", ErrInvalidRule, part)
+		}
+
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			r.Freq = strings.ToUpper(value)
+			if r.Freq != FreqDaily && r.Freq != FreqWeekly {
+				return nil, fmt.Errorf("%w: only DAILY and WEEKLY rules are supported", ErrInvalidRule)
+			}
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("%w: INTERVAL must be a positive number", ErrInvalidRule)
+			}
+			r.Interval = n
+		case "BYDAY":
+			seen := map[time.Weekday]bool{}
+			for _, day := range strings.Split(value, ",") {
+				wd, ok := weekdays[strings.ToUpper(day)]
+				if !ok {
+					return nil, fmt.Errorf("%w: unsupported BYDAY value %q", ErrInvalidRule, day)
+				}
+				if !seen[wd] {
+					seen[wd] = true
+					r.ByDay = append(r.ByDay, wd)
+				}
+			}
+		case "UNTIL":
+			until, err := parseUntil(value, loc)
+			if err != nil {
+				return nil, err
+			}
+			r.Until = until
+		case "COUNT":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 || n > MaxCount {
+				return nil, fmt.Errorf("%w: COUNT must be between 1 and %d", ErrInvalidRule, MaxCount)
+			}
+			r.Count = n
+		case "WKST":
+			//weeks always start on Monday
+			if strings.ToUpper(value) != "MO" {
+				return nil, fmt.Errorf("%w: only WKST=MO is supported", ErrInvalidRule)
+			}
+		default:
+			return nil, fmt.Errorf("%w: unsupported part %s", ErrInvalidRule, key)
+		}
+	}
+
+	if r.Freq == "" {
+		return nil, fmt.Errorf("%w: FREQ is required", ErrInvalidRule)
+	}
+	if !r.Until.IsZero() && r.Count > 0 {
+		return nil, fmt.Errorf("%w: UNTIL and COUNT cannot be combined", ErrInvalidRule)
+	}
+	if r.Freq == FreqDaily && len(r.ByDay) > 0 {
+		return nil, fmt.Errorf("%w: BYDAY is only supported on WEEKLY rules", ErrInvalidRule)
+	}
+
+	sort.Slice(r.ByDay, func(i, j int) bool { return mondayFirst(r.ByDay[i]) < mondayFirst(r.ByDay[j]) })
+	return r, nil
+}
+
+func parseUntil(value string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(untilLayoutUTC, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation(untilLayout, value, loc); err == nil {
+		return t, nil
+	}
+	//a date only UNTIL includes the whole day
+	if t, err := time.ParseInLocation(dateLayout, value, loc); err == nil {
+		return t.AddDate(0, 0, 1).Add(-time.Second), nil
+	}
+	return time.Time{}, fmt.Errorf("%w: UNTIL must look like 20250131T000000Z", ErrInvalidRule)
+}
+
+// String writes the rule back as an RRULE value, UNTIL in UTC
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + r.Freq}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, 0, len(r.ByDay))
+		for _, wd := range r.ByDay {
+			days = append(days, strings.ToUpper(wd.String()[:2]))
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format(untilLayoutUTC))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Occurrences returns the starts of the occurrences of the series starting at dtstart that fall in
+// [from, to). Every occurrence keeps the wall clock time of dtstart in dtstart's location, so a
+// 6pm practice stays at 6pm across daylight saving changes
+func (r *Rule) Occurrences(dtstart time.Time, from time.Time, to time.Time) []time.Time {
+	var out []time.Time
+
+	emitted := 0
+	// ends reports whether the series is over at start
+	ends := func(start time.Time) bool {
+		if r.Count > 0 && emitted >= r.Count {
+			return true
+		}
+		if !r.Until.IsZero() && start.After(r.Until) {
+			return true
+		}
+		return !start.Before(to)
+	}
+
+	loc := dtstart.Location()
+	clock := func(day time.Time) time.Time {
+		return time.Date(day.Year(), day.Month(), day.Day(), dtstart.Hour(), dtstart.Minute(), dtstart.Second(), 0, loc)
+	}
+
+	byDay := r.ByDay
+	if r.Freq == FreqWeekly && len(byDay) == 0 {
+		byDay = []time.Weekday{dtstart.Weekday()}
+	}
+
+	//COUNT counts from dtstart, so the walk always starts there; MaxCount periods of the
+	//longest interval is more than any horizon needs
+	for period := 0; period <= MaxCount*7; period++ {
+		var candidates []time.Time
+		switch r.Freq {
+		case FreqDaily:
+			candidates = []time.Time{clock(dtstart.AddDate(0, 0, period*r.Interval))}
+		case FreqWeekly:
+			weekStart := dtstart.AddDate(0, 0, -mondayFirst(dtstart.Weekday())+period*7*r.Interval)
+			for _, wd := range byDay {
+				candidates = append(candidates, clock(weekStart.AddDate(0, 0, mondayFirst(wd))))
+			}
+		}
+
+		for _, start := range candidates {
+			if start.Before(dtstart) {
+				continue
+			}
+			if ends(start) {
+				return out
+			}
+			emitted++
+			if !start.Before(from) {
+				out = append(out, start)
+			}
+		}
+	}
+	return out
+}
+
+// Before returns how many occurrences start before t, used to carry COUNT over when a series is split
+func (r *Rule) Before(dtstart time.Time, t time.Time) int {
+	return len(r.Occurrences(dtstart, dtstart, t))
+}
+
+func mondayFirst(wd time.Weekday) int {
+	return (int(wd) + 6) % 7
+}
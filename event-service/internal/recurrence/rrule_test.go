@@ -0,0 +1,93 @@
+package recurrence
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func mustLoad(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return loc
+}
+
+func TestWeeklyByDayKeepsWallClockAcrossDST(t *testing.T) {
+	loc := mustLoad(t, "Europe/London")
+	rule, err := Parse("RRULE:FREQ=WEEKLY;BYDAY=TU,TH;UNTIL=20251106T235959Z", loc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	//Thursday 23 October 2025, the clocks go back on the 26th
+	dtstart := time.Date(2025, 10, 23, 18, 0, 0, 0, loc)
+	got := rule.Occurrences(dtstart, dtstart, dtstart.AddDate(1, 0, 0))
+
+	want := []string{"2025-10-23", "2025-10-28", "2025-10-30", "2025-11-04", "2025-11-06"}
+	if len(got) != len(want) {
+		t.Fatalf("got %d occurrences %v, want %d", len(got), got, len(want))
+	}
+	for i, start := range got {
+		if start.Format("2006-01-02") != want[i] || start.Hour() != 18 {
+			t.Errorf("occurrence %d = %v, want %s at 18:00", i, start, want[i])
+		}
+	}
+	if before, after := got[0].UTC().Hour(), got[1].UTC().Hour(); before != 17 || after != 18 {
+		t.Errorf("UTC hours = %d, %d, want 17 then 18", before, after)
+	}
+}
+
+func TestCountIsCountedFromDtstart(t *testing.T) {
+	loc := mustLoad(t, "Africa/Nairobi")
+	rule, err := Parse("FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;COUNT=5", loc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	//a Wednesday, so the Monday of the first week is skipped
+	dtstart := time.Date(2025, 1, 15, 17, 30, 0, 0, loc)
+	all := rule.Occurrences(dtstart, dtstart, dtstart.AddDate(1, 0, 0))
+	if len(all) != 5 {
+		t.Fatalf("got %d occurrences, want 5", len(all))
+	}
+	if all[1].Format("2006-01-02") != "2025-01-27" {
+		t.Errorf("second occurrence = %v, want the Monday two weeks on", all[1])
+	}
+
+	//a window after the first occurrences still stops at the fifth
+	later := rule.Occurrences(dtstart, all[2], dtstart.AddDate(1, 0, 0))
+	if len(later) != 3 || !later[2].Equal(all[4]) {
+		t.Errorf("windowed occurrences = %v, want the last three", later)
+	}
+	if n := rule.Before(dtstart, all[3]); n != 3 {
+		t.Errorf("Before = %d, want 3", n)
+	}
+}
+
+func TestParseRejectsUnsupportedRules(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"FREQ=MONTHLY;BYMONTHDAY=1",
+		"FREQ=WEEKLY;BYDAY=1MO",
+		"FREQ=WEEKLY;COUNT=3;UNTIL=20250101T000000Z",
+		"FREQ=WEEKLY;COUNT=10000",
+		"BYDAY=MO",
+	} {
+		if _, err := Parse(s, time.UTC); !errors.Is(err, ErrInvalidRule) {
+			t.Errorf("Parse(%q) error = %v, want ErrInvalidRule", s, err)
+		}
+	}
+}
+
+func TestStringRoundTrips(t *testing.T) {
+	rule, err := Parse("FREQ=WEEKLY;BYDAY=TH,TU;INTERVAL=2;UNTIL=20251231", time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := rule.String(), "FREQ=WEEKLY;INTERVAL=2;BYDAY=TU,TH;UNTIL=20251231T235959Z"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
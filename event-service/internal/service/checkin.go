@@ -0,0 +1,451 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/wycliff-ochieng/common_packages/events"
+	"github.com/wycliff-ochieng/common_packages/team_grpc/team_proto"
+	"github.com/wycliff-ochieng/internal/models"
+	"github.com/wycliff-ochieng/sports-common-package/user_grpc/user_proto"
+)
+
+var (
+	ErrCheckInNotOpen     = fmt.Errorf("%w: check-in is not open", ErrConflict)
+	ErrCheckInClosed      = fmt.Errorf("%w: check-in has closed", ErrConflict)
+	ErrCheckInRecorded    = fmt.Errorf("%w: a coach has already recorded this player", ErrConflict)
+	ErrInvalidCheckInCode = fmt.Errorf("%w: wrong check-in code", ErrForbidden)
+	ErrInvalidRange       = errors.New("invalid date range")
+)
+
+const (
+	// self check-in opens this long before the start and closes this long after it, or at the end
+	checkInOpensBefore = 30 * time.Minute
+	checkInClosesAfter = 30 * time.Minute
+	// self check-ins after the start plus this grace are recorded LATE
+	lateAfter = 5 * time.Minute
+
+	// unambiguous characters only, no 0/O or 1/I
+	checkInCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	checkInCodeLength   = 6
+
+	maxCheckInsPerRequest = 200
+	defaultStatsRange     = 90 * 24 * time.Hour
+	maxStatsRange         = 366 * 24 * time.Hour
+)
+
+var checkInStatuses = map[string]bool{
+	models.CheckInPresent: true,
+	models.CheckInLate:    true,
+	models.CheckInAbsent:  true,
+	models.CheckInExcused: true,
+}
+
+// checkInWindow is when players can check themselves in
+func checkInWindow(e *models.Event) (opens time.Time, closes time.Time) {
+	opens = e.StartTime.Add(-checkInOpensBefore)
+	closes = e.StartTime.Add(checkInClosesAfter)
+	if e.EndTime.After(e.StartTime) && e.EndTime.Before(closes) {
+		closes = e.EndTime
+	}
+	return opens, closes
+}
+
+func selfCheckInStatus(e *models.Event, at time.Time) string {
+	if at.After(e.StartTime.Add(lateAfter)) {
+		return models.CheckInLate
+	}
+	return models.CheckInPresent
+}
+
+// attendanceRate leaves excused and unrecorded events out, a player nobody took attendance for
+// is not absent
+func attendanceRate(s models.PlayerAttendanceStats) float64 {
+	attended := s.Present + s.Late
+	counted := attended + s.Absent
+	if counted == 0 {
+		return 0
+	}
+	return float64(attended) / float64(counted)
+}
+
+func newCheckInCode() (string, error) {
+	b := make([]byte, checkInCodeLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	//256 is a multiple of the alphabet size so every character is equally likely
+	for i := range b {
+		b[i] = checkInCodeAlphabet[int(b[i])%len(checkInCodeAlphabet)]
+	}
+	return string(b), nil
+}
+
+// IssueCheckInCode gives the event a new self check-in code, the previous one stops working
+func (es *EventService) IssueCheckInCode(ctx context.Context, reqUserID uuid.UUID, eventID uuid.UUID) (*models.CheckInCode, error) {
+	event, err := es.GetEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if event.Status == models.StatusCancelled {
+		return nil, ErrEventCanceled
+	}
+
+	if err := es.requireTeamPermission(ctx, event.TeamID, reqUserID, PermEventsManage); err != nil {
+		return nil, err
+	}
+
+	opens, closes := checkInWindow(event)
+	if time.Now().After(closes) {
+		return nil, ErrCheckInClosed
+	}
+
+	code, err := newCheckInCode()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := es.db.ExecContext(ctx, `UPDATE events SET check_in_code=$1 WHERE event_id=$2`, code, eventID); err != nil {
+		return nil, fmt.Errorf("issue storing check-in code: %w", err)
+	}
+
+	return &models.CheckInCode{
+		EventID:    eventID,
+		Code:       code,
+		CheckInURL: "/api/events/" + eventID.String() + "/check-in?code=" + code,
+		OpensAt:    opens,
+		ClosesAt:   closes,
+	}, nil
+}
+
+// SelfCheckIn checks the caller in with the code the coach is showing. Checking in twice returns
+// the first check-in
+func (es *EventService) SelfCheckIn(ctx context.Context, userID uuid.UUID, eventID uuid.UUID, code string) (*models.CheckIn, error) {
+	event, err := es.GetEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if event.Status == models.StatusCancelled {
+		return nil, ErrEventCanceled
+	}
+
+	now := time.Now()
+	opens, closes := checkInWindow(event)
+	if now.Before(opens) {
+		return nil, ErrCheckInNotOpen
+	}
+	if now.After(closes) {
+		return nil, ErrCheckInClosed
+	}
+
+	var stored sql.NullString
+	if err := es.db.QueryRowContext(ctx, `SELECT check_in_code FROM events WHERE event_id=$1`, eventID).Scan(&stored); err != nil {
+		return nil, err
+	}
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if !stored.Valid || subtle.ConstantTimeCompare([]byte(stored.String), []byte(code)) != 1 {
+		return nil, ErrInvalidCheckInCode
+	}
+
+	tx, err := es.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := es.ensureRoster(ctx, tx, event, []uuid.UUID{userID}); err != nil {
+		return nil, err
+	}
+
+	existing, err := scanCheckIn(tx.QueryRowContext(ctx, `SELECT `+checkInColumns+` FROM check_ins WHERE event_id=$1 AND user_id=$2 FOR UPDATE`,
+		eventID, userID))
+	switch {
+	case err == nil && existing.Method == models.CheckInByCoach:
+		return nil, ErrCheckInRecorded
+	case err == nil:
+		return existing, nil
+	case err != sql.ErrNoRows:
+		return nil, err
+	}
+
+	checkIn, err := scanCheckIn(tx.QueryRowContext(ctx, `INSERT INTO check_ins(event_id,user_id,team_id,status,method,recorded_by)
+	VALUES($1,$2,$3,$4,$5,$2) RETURNING `+checkInColumns,
+		eventID, userID, event.TeamID, selfCheckInStatus(event, now), models.CheckInBySelf))
+	if err != nil {
+		return nil, fmt.Errorf("issue storing check-in: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return checkIn, nil
+}
+
+// RecordCheckIns is a coach taking attendance. It replaces self check-ins and earlier records of
+// the same players. Players can be excused ahead of time, the rest waits for the check-in window
+func (es *EventService) RecordCheckIns(ctx context.Context, reqUserID uuid.UUID, eventID uuid.UUID, req models.RecordCheckInsReq) ([]models.CheckIn, error) {
+	if len(req.CheckIns) == 0 || len(req.CheckIns) > maxCheckInsPerRequest {
+		return nil, fmt.Errorf("%w: between 1 and %d check-ins per request", ErrInvalidEvent, maxCheckInsPerRequest)
+	}
+
+	seen := map[uuid.UUID]bool{}
+	userIDs := make([]uuid.UUID, 0, len(req.CheckIns))
+	for i, c := range req.CheckIns {
+		req.CheckIns[i].Status = strings.ToUpper(strings.TrimSpace(c.Status))
+		req.CheckIns[i].Note = strings.TrimSpace(c.Note)
+		switch {
+		case c.UserID == uuid.Nil:
+			return nil, fmt.Errorf("%w: every check-in needs a user", ErrInvalidEvent)
+		case seen[c.UserID]:
+			return nil, fmt.Errorf("%w: user %s is listed twice", ErrInvalidEvent, c.UserID)
+		case !checkInStatuses[req.CheckIns[i].Status]:
+			return nil, fmt.Errorf("%w: status must be PRESENT, LATE, ABSENT or EXCUSED", ErrInvalidEvent)
+		case len([]rune(req.CheckIns[i].Note)) > maxReasonLength:
+			return nil, fmt.Errorf("%w: note is longer than %d characters", ErrInvalidEvent, maxReasonLength)
+		}
+		seen[c.UserID] = true
+		userIDs = append(userIDs, c.UserID)
+	}
+
+	event, err := es.GetEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if event.Status == models.StatusCancelled {
+		return nil, ErrEventCanceled
+	}
+
+	if err := es.requireTeamPermission(ctx, event.TeamID, reqUserID, PermEventsManage); err != nil {
+		return nil, err
+	}
+
+	opens, _ := checkInWindow(event)
+	if time.Now().Before(opens) {
+		for _, c := range req.CheckIns {
+			if c.Status != models.CheckInExcused {
+				return nil, ErrCheckInNotOpen
+			}
+		}
+	}
+
+	tx, err := es.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := es.ensureRoster(ctx, tx, event, userIDs); err != nil {
+		return nil, err
+	}
+
+	checkIns := make([]models.CheckIn, 0, len(req.CheckIns))
+	for _, c := range req.CheckIns {
+		checkIn, err := scanCheckIn(tx.QueryRowContext(ctx, `INSERT INTO check_ins(event_id,user_id,team_id,status,method,note,recorded_by)
+		VALUES($1,$2,$3,$4,$5,NULLIF($6,''),$7)
+		ON CONFLICT (event_id,user_id) DO UPDATE SET status=EXCLUDED.status,method=EXCLUDED.method,note=EXCLUDED.note,
+		recorded_by=EXCLUDED.recorded_by,updated_at=NOW()
+		RETURNING `+checkInColumns,
+			eventID, c.UserID, event.TeamID, c.Status, models.CheckInByCoach, c.Note, reqUserID))
+		if err != nil {
+			return nil, fmt.Errorf("issue storing check-in: %w", err)
+		}
+		checkIns = append(checkIns, *checkIn)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return checkIns, nil
+}
+
+// ensureRoster gives players who joined the team after the event was created an attendance row,
+// anyone who is not on the team gets ErrForbidden
+func (es *EventService) ensureRoster(ctx context.Context, tx *sql.Tx, event *models.Event, userIDs []uuid.UUID) error {
+	ids := make([]string, 0, len(userIDs))
+	for _, id := range userIDs {
+		ids = append(ids, id.String())
+	}
+
+	rows, err := tx.QueryContext(ctx, `SELECT user_id FROM attendance WHERE event_id=$1 AND user_id = ANY($2::uuid[])`,
+		event.ID, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	onRoster := map[string]bool{}
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return err
+		}
+		onRoster[id.String()] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	var missing []string
+	for _, id := range ids {
+		if !onRoster[id] {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	res, err := es.teamClient.CheckTeamMembership(ctx, &team_proto.GetTeamMembershipRequest{
+		TeamId: event.TeamID.String(),
+		UserId: missing,
+	})
+	if err != nil {
+		es.l.Error("gRPC membership check to team service failed", "error", err)
+		return err
+	}
+
+	for _, id := range missing {
+		if _, ok := res.Members[id]; !ok {
+			return fmt.Errorf("%w: user %s is not on the team", ErrForbidden, id)
+		}
+		_, err := tx.ExecContext(ctx, `INSERT INTO attendance(event_id,user_id,team_id,event_status,updated_at) VALUES($1,$2,$3,$4,NOW())
+		ON CONFLICT (event_id,user_id) DO NOTHING`, event.ID, id, event.TeamID, events.RSVPPending)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+const checkInColumns = `event_id,team_id,user_id,status,method,COALESCE(note,''),recorded_by,recorded_at,updated_at`
+
+func scanCheckIn(row *sql.Row) (*models.CheckIn, error) {
+	var c models.CheckIn
+	err := row.Scan(
+		&c.EventID,
+		&c.TeamID,
+		&c.UserID,
+		&c.Status,
+		&c.Method,
+		&c.Note,
+		&c.RecordedBy,
+		&c.RecordedAt,
+		&c.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// AttendanceStats reports per player attendance over events that started in [from, to). Coaches
+// with events.manage see the whole team, other members only themselves
+func (es *EventService) AttendanceStats(ctx context.Context, reqUserID uuid.UUID, teamID uuid.UUID, from time.Time, to time.Time) (*models.AttendanceReport, error) {
+	if to.IsZero() {
+		to = time.Now()
+	}
+	if from.IsZero() {
+		from = to.Add(-defaultStatsRange)
+	}
+	if !from.Before(to) {
+		return nil, fmt.Errorf("%w: from must be before to", ErrInvalidRange)
+	}
+	if to.Sub(from) > maxStatsRange {
+		return nil, fmt.Errorf("%w: at most 366 days", ErrInvalidRange)
+	}
+
+	var onlyUser uuid.NullUUID
+	err := es.requireTeamPermission(ctx, teamID, reqUserID, PermEventsManage)
+	if errors.Is(err, ErrForbidden) {
+		if err := es.requireTeamPermission(ctx, teamID, reqUserID, PermEventsView); err != nil {
+			return nil, err
+		}
+		onlyUser = uuid.NullUUID{UUID: reqUserID, Valid: true}
+	} else if err != nil {
+		return nil, err
+	}
+
+	//events that have not happened yet have no attendance to count
+	until := to
+	if now := time.Now(); now.Before(until) {
+		until = now
+	}
+
+	rows, err := es.db.QueryContext(ctx, `SELECT a.user_id,COUNT(*),
+		COUNT(*) FILTER (WHERE c.status = $6),
+		COUNT(*) FILTER (WHERE c.status = $7),
+		COUNT(*) FILTER (WHERE c.status = $8),
+		COUNT(*) FILTER (WHERE c.status = $9),
+		COUNT(*) FILTER (WHERE c.status IS NULL)
+	FROM events e
+	JOIN attendance a ON a.event_id=e.event_id
+	LEFT JOIN check_ins c ON c.event_id=a.event_id AND c.user_id=a.user_id
+	WHERE e.team_id=$1 AND e.status=$2 AND e.start_time >= $3 AND e.start_time < $4 AND ($5::uuid IS NULL OR a.user_id=$5)
+	GROUP BY a.user_id`,
+		teamID, models.StatusScheduled, from.UTC(), until.UTC(), onlyUser,
+		models.CheckInPresent, models.CheckInLate, models.CheckInAbsent, models.CheckInExcused)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	report := &models.AttendanceReport{TeamID: teamID, From: from, To: to, Players: []models.PlayerAttendanceStats{}}
+	for rows.Next() {
+		var s models.PlayerAttendanceStats
+		if err := rows.Scan(&s.UserID, &s.Events, &s.Present, &s.Late, &s.Absent, &s.Excused, &s.Unrecorded); err != nil {
+			return nil, err
+		}
+		s.Rate = attendanceRate(s)
+		report.Players = append(report.Players, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	es.addPlayerNames(ctx, report.Players)
+	sort.SliceStable(report.Players, func(i, j int) bool {
+		a, b := report.Players[i], report.Players[j]
+		if a.LastName != b.LastName {
+			return a.LastName < b.LastName
+		}
+		if a.FirstName != b.FirstName {
+			return a.FirstName < b.FirstName
+		}
+		return a.UserID.String() < b.UserID.String()
+	})
+	return report, nil
+}
+
+// addPlayerNames fills in names from user-service, the report is still useful without them
+func (es *EventService) addPlayerNames(ctx context.Context, players []models.PlayerAttendanceStats) {
+	if len(players) == 0 {
+		return
+	}
+
+	ids := make([]string, 0, len(players))
+	for _, p := range players {
+		ids = append(ids, p.UserID.String())
+	}
+
+	res, err := es.userClient.GetUserProfiles(ctx, &user_proto.GetUserRequest{Userid: ids})
+	if err != nil {
+		es.l.Warn("attendance report without names, user service lookup failed", "error", err)
+		return
+	}
+
+	for i := range players {
+		if profile, ok := res.Profiles[players[i].UserID.String()]; ok {
+			players[i].FirstName = profile.Firstname
+			players[i].LastName = profile.Lastname
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wycliff-ochieng/internal/models"
+)
+
+func TestCheckInWindow(t *testing.T) {
+	start := time.Date(2025, 3, 4, 18, 0, 0, 0, time.UTC)
+
+	long := &models.Event{StartTime: start, EndTime: start.Add(2 * time.Hour)}
+	opens, closes := checkInWindow(long)
+	if !opens.Equal(start.Add(-30*time.Minute)) || !closes.Equal(start.Add(30*time.Minute)) {
+		t.Errorf("window = %s - %s", opens, closes)
+	}
+
+	//a short event closes check-in when it ends
+	short := &models.Event{StartTime: start, EndTime: start.Add(20 * time.Minute)}
+	if _, closes := checkInWindow(short); !closes.Equal(short.EndTime) {
+		t.Errorf("short event closes at %s, want %s", closes, short.EndTime)
+	}
+
+	if got := selfCheckInStatus(long, start.Add(5*time.Minute)); got != models.CheckInPresent {
+		t.Errorf("within the grace got %s", got)
+	}
+	if got := selfCheckInStatus(long, start.Add(6*time.Minute)); got != models.CheckInLate {
+		t.Errorf("after the grace got %s", got)
+	}
+}
+
+func TestAttendanceRate(t *testing.T) {
+	for _, tc := range []struct {
+		stats models.PlayerAttendanceStats
+		want  float64
+	}{
+		{models.PlayerAttendanceStats{Present: 6, Late: 2, Absent: 2, Excused: 3, Unrecorded: 1}, 0.8},
+		{models.PlayerAttendanceStats{Excused: 2, Unrecorded: 4}, 0},
+		{models.PlayerAttendanceStats{Late: 1}, 1},
+	} {
+		if got := attendanceRate(tc.stats); got != tc.want {
+			t.Errorf("attendanceRate(%+v) = %v, want %v", tc.stats, got, tc.want)
+		}
+	}
+}
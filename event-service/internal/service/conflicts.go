@@ -0,0 +1,208 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/wycliff-ochieng/common_packages/events"
+	"github.com/wycliff-ochieng/internal/models"
+)
+
+var ErrScheduleConflict = fmt.Errorf("%w: the event overlaps other events", ErrConflict)
+
+// ConflictError is returned instead of saving an event that overlaps others, the write is rolled back
+type ConflictError struct {
+	Report *models.ConflictReport
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%s: %d team, %d player, %d venue, %d block-out conflicts and %d outside opening hours", ErrScheduleConflict,
+		len(e.Report.Team), len(e.Report.Players), len(e.Report.Venue), len(e.Report.BlockOuts), len(e.Report.OutsideHours))
+}
+
+func (e *ConflictError) Unwrap() error { return ErrScheduleConflict }
+
+// allowForce checks that whoever skips conflict detection may do so, forcing is for coaches
+func (es *EventService) allowForce(ctx context.Context, teamID uuid.UUID, userID uuid.UUID, force bool) error {
+	if !force {
+		return nil
+	}
+	return es.requireTeamPermission(ctx, teamID, userID, PermEventsManage)
+}
+
+// checkConflicts runs after the events are written in tx, so it sees them as they will be saved,
+// and fails with a ConflictError when any of them overlaps another scheduled event. Forcing skips
+// the overlaps but never a venue's block-outs or opening hours
+func (es *EventService) checkConflicts(ctx context.Context, q queryer, force bool, eventIDs []uuid.UUID) error {
+	if len(eventIDs) == 0 {
+		return nil
+	}
+
+	report := &models.ConflictReport{
+		Team:    []models.ConflictingEvent{},
+		Players: []models.PlayerConflict{},
+		Venue:   []models.ConflictingEvent{},
+	}
+	if !force {
+		var err error
+		report, err = es.findConflicts(ctx, q, eventIDs)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := es.findVenueRuleConflicts(ctx, q, eventIDs, report); err != nil {
+		return err
+	}
+
+	if len(report.Team) == 0 && len(report.Players) == 0 && len(report.Venue) == 0 &&
+		len(report.BlockOuts) == 0 && len(report.OutsideHours) == 0 {
+		return nil
+	}
+	return &ConflictError{Report: report}
+}
+
+// conflictColumns are read from e, the existing event, and n, the one being scheduled
+const conflictColumns = `e.event_id,e.team_id,COALESCE(e.event_title,''),COALESCE(e.location,''),e.start_time,e.end_time,n.event_id`
+
+// overlapping joins every scheduled event e that overlaps n and is not one of $1
+const overlapping = `JOIN events e ON e.start_time < n.end_time AND e.end_time > n.start_time AND e.status = $2
+	AND NOT (e.event_id = ANY($1::uuid[]))`
+
+func (es *EventService) findConflicts(ctx context.Context, q queryer, eventIDs []uuid.UUID) (*models.ConflictReport, error) {
+	ids := make([]string, 0, len(eventIDs))
+	for _, id := range eventIDs {
+		ids = append(ids, id.String())
+	}
+
+	report := &models.ConflictReport{
+		Team:    []models.ConflictingEvent{},
+		Players: []models.PlayerConflict{},
+		Venue:   []models.ConflictingEvent{},
+	}
+
+	var err error
+	report.Team, err = es.conflictingEvents(ctx, q, `SELECT `+conflictColumns+` FROM events n `+overlapping+` AND e.team_id = n.team_id
+	WHERE n.event_id = ANY($1::uuid[]) AND n.status = $2 ORDER BY e.start_time, e.event_id`, pq.Array(ids), models.StatusScheduled)
+	if err != nil {
+		return nil, err
+	}
+
+	//events booked at a venue clash on the same resource or when either takes the whole venue,
+	//the location text is only compared between events without one
+	report.Venue, err = es.conflictingEvents(ctx, q, `SELECT `+conflictColumns+` FROM events n `+overlapping+`
+	AND ((n.venue_id IS NOT NULL AND e.venue_id = n.venue_id
+		AND (e.resource_id IS NULL OR n.resource_id IS NULL OR e.resource_id = n.resource_id))
+	OR (n.venue_id IS NULL AND e.venue_id IS NULL AND TRIM(COALESCE(n.location,'')) <> ''
+		AND LOWER(TRIM(e.location)) = LOWER(TRIM(n.location))))
+	WHERE n.event_id = ANY($1::uuid[]) AND n.status = $2 ORDER BY e.start_time, e.event_id`,
+		pq.Array(ids), models.StatusScheduled)
+	if err != nil {
+		return nil, err
+	}
+
+	//events of the same team are already in Team, a player who declined is not double booked
+	rows, err := q.QueryContext(ctx, `SELECT DISTINCT a.user_id,`+conflictColumns+` FROM events n
+	JOIN attendance na ON na.event_id = n.event_id
+	JOIN attendance a ON a.user_id = na.user_id AND a.event_status <> $3
+	`+overlapping+` AND e.event_id = a.event_id AND e.team_id <> n.team_id
+	WHERE n.event_id = ANY($1::uuid[]) AND n.status = $2 ORDER BY a.user_id, e.start_time, e.event_id`,
+		pq.Array(ids), models.StatusScheduled, events.RSVPNotGoing)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var userID uuid.UUID
+		var c models.ConflictingEvent
+		if err := rows.Scan(&userID, &c.EventID, &c.TeamID, &c.Title, &c.Location, &c.StartTime, &c.EndTime, &c.ConflictsWith); err != nil {
+			return nil, err
+		}
+		if n := len(report.Players); n > 0 && report.Players[n-1].UserID == userID {
+			report.Players[n-1].Events = append(report.Players[n-1].Events, c)
+			continue
+		}
+		report.Players = append(report.Players, models.PlayerConflict{UserID: userID, Events: []models.ConflictingEvent{c}})
+	}
+	return report, rows.Err()
+}
+
+func (es *EventService) conflictingEvents(ctx context.Context, q queryer, query string, args ...any) ([]models.ConflictingEvent, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	conflicts := []models.ConflictingEvent{}
+	for rows.Next() {
+		var c models.ConflictingEvent
+		if err := rows.Scan(&c.EventID, &c.TeamID, &c.Title, &c.Location, &c.StartTime, &c.EndTime, &c.ConflictsWith); err != nil {
+			return nil, err
+		}
+		conflicts = append(conflicts, c)
+	}
+	return conflicts, rows.Err()
+}
+
+// findVenueRuleConflicts adds the block-outs the events overlap and the events outside their venue's
+// opening hours to report. Hours are compared in the venue's time zone and an event has to fit in
+// one opening, a venue without hours is always open
+func (es *EventService) findVenueRuleConflicts(ctx context.Context, q queryer, eventIDs []uuid.UUID, report *models.ConflictReport) error {
+	ids := make([]string, 0, len(eventIDs))
+	for _, id := range eventIDs {
+		ids = append(ids, id.String())
+	}
+
+	rows, err := q.QueryContext(ctx, `SELECT b.blockout_id,b.resource_id,b.start_time,b.end_time,b.reason,n.event_id FROM events n
+	JOIN venue_blockouts b ON b.venue_id = n.venue_id AND (b.resource_id IS NULL OR n.resource_id IS NULL OR b.resource_id = n.resource_id)
+		AND b.start_time < n.end_time AND b.end_time > n.start_time
+	WHERE n.event_id = ANY($1::uuid[]) AND n.status = $2 ORDER BY b.start_time, b.blockout_id, n.event_id`,
+		pq.Array(ids), models.StatusScheduled)
+	if err != nil {
+		return err
+	}
+
+	report.BlockOuts = []models.BlockOutConflict{}
+	for rows.Next() {
+		var c models.BlockOutConflict
+		if err := rows.Scan(&c.BlockOutID, &c.ResourceID, &c.StartTime, &c.EndTime, &c.Reason, &c.ConflictsWith); err != nil {
+			rows.Close()
+			return err
+		}
+		report.BlockOuts = append(report.BlockOuts, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	//start_time and end_time are UTC, lt reads them as wall clock time at the venue
+	rows, err = q.QueryContext(ctx, `SELECT n.event_id FROM events n
+	JOIN venues v ON v.venue_id = n.venue_id
+	CROSS JOIN LATERAL (SELECT (n.start_time AT TIME ZONE 'UTC') AT TIME ZONE v.time_zone AS start_at,
+		(n.end_time AT TIME ZONE 'UTC') AT TIME ZONE v.time_zone AS end_at) lt
+	WHERE n.event_id = ANY($1::uuid[]) AND n.status = $2
+	AND EXISTS (SELECT 1 FROM venue_hours h WHERE h.venue_id = v.venue_id)
+	AND NOT EXISTS (SELECT 1 FROM venue_hours h WHERE h.venue_id = v.venue_id
+		AND h.weekday = EXTRACT(DOW FROM lt.start_at) AND lt.start_at::date = lt.end_at::date
+		AND h.opens_at <= lt.start_at::time AND h.closes_at >= lt.end_at::time)
+	ORDER BY n.start_time, n.event_id`, pq.Array(ids), models.StatusScheduled)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	report.OutsideHours = []uuid.UUID{}
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return err
+		}
+		report.OutsideHours = append(report.OutsideHours, id)
+	}
+	return rows.Err()
+}
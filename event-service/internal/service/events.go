@@ -11,15 +11,25 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/wycliff-ochieng/common_packages/events"
+	"github.com/wycliff-ochieng/common_packages/team_grpc/team_proto"
 	"github.com/wycliff-ochieng/internal/database"
 	"github.com/wycliff-ochieng/internal/models"
-	"github.com/wycliff-ochieng/sports-common-package/team_grpc/team_proto"
+	internal "github.com/wycliff-ochieng/internal/producer"
 	"github.com/wycliff-ochieng/sports-common-package/user_grpc/user_proto"
 )
 
 var (
-	ErrForbidden = errors.New("Not allowed")
-	ErrNotFound  = errors.New("Not found in system")
+	ErrForbidden    = errors.New("Not allowed")
+	ErrNotFound     = errors.New("Not found in system")
+	ErrInvalidEvent = errors.New("invalid event")
+)
+
+// team permissions owned by team-service, checked over gRPC CheckPermission
+const (
+	PermEventsView   = "events.view"
+	PermEventsCreate = "events.create"
+	PermEventsManage = "events.manage"
 )
 
 type Events interface {
@@ -35,51 +45,47 @@ type EventService struct {
 	teamClient team_proto.TeamRPCClient
 	userClient user_proto.UserServiceRPCClient
 	l          *slog.Logger
+	// how far ahead occurrences of recurring events are stored
+	horizon time.Duration
+	prod    internal.KafkaProducer
 }
 
-func NewEventService(db database.DBInterface, teamClient team_proto.TeamRPCClient, userCllient user_proto.UserServiceRPCClient, logger *slog.Logger) *EventService {
+func NewEventService(db database.DBInterface, teamClient team_proto.TeamRPCClient, userCllient user_proto.UserServiceRPCClient, logger *slog.Logger, horizon time.Duration, prod internal.KafkaProducer) *EventService {
 	return &EventService{
 		db:         db,
 		teamClient: teamClient,
 		userClient: userCllient,
 		l:          logger,
+		horizon:    horizon,
+		prod:       prod,
 	}
 }
 
-func (es *EventService) CreateTeamEvent(ctx context.Context, reqUserID uuid.UUID, eventID uuid.UUID, eventTitle string, teamID uuid.UUID, eventType string, location string, startTime time.Time, endTime time.Time) (*models.Event, error) {
+func (es *EventService) CreateTeamEvent(ctx context.Context, reqUserID uuid.UUID, req models.CreateEventReq) (*models.Event, error) {
+	teamID := req.TeamID
+
 	//Authorization via gRPC
 	es.l.Info("Creation of event initiated by user")
 
-	//var CreateEventReq models.CreateEventReq
+	log.Printf("User: %s", reqUserID)
+	log.Printf("TeamID: %s", teamID)
 
-	membershipReq := &team_proto.GetTeamMembershipRequest{
-		TeamId: teamID.String(),
-		UserId: []string{reqUserID.String()},
+	if err := es.requireTeamPermission(ctx, teamID, reqUserID, PermEventsCreate); err != nil {
+		es.l.Warn("user is not allowed to create events for this team", "error", err)
+		return nil, err
 	}
+	es.l.Info("Authorization is successfull")
 
-	//log.Printf("T-ID: %s", CreateEventReq.TeamID)
-	members, err := es.teamClient.CheckTeamMembership(ctx, membershipReq)
-	if err != nil {
+	if err := es.allowForce(ctx, teamID, reqUserID, req.Force); err != nil {
 		return nil, err
 	}
 
-	log.Printf("User: %s", reqUserID)
-	log.Printf("TeamID: %s", teamID)
-	
-	//check response/error from grRPC call- > if user is a member of that team or not and if member is coach/manager
-	membersInfo, found := members.Members[reqUserID.String()]
-	if !found {
-		es.l.Error("user does not exist in the team therefore is not a member")
-		return nil, ErrForbidden
+	if err := validateTimes(req.StartTime, req.EndTime); err != nil {
+		return nil, err
 	}
-
-	isAuthorized := membersInfo.Role == "coach" || membersInfo.Role == "manager"
-	if !isAuthorized {
-		es.l.Warn("user is not a coach/manager therefore cant create events")
-		//return nil, err
+	if req.RSVPDeadline != nil && !req.RSVPDeadline.Before(req.EndTime) {
+		return nil, fmt.Errorf("%w: the RSVP deadline must be before the event ends", ErrInvalidEvent)
 	}
-	log.Printf("Role: %s", membersInfo.Role)
-	es.l.Info("Authorization is successfull")
 
 	//get team data for attendance table insert(business logic)
 	teamMembersReq := team_proto.GetTeamSummaryRequest{TeamId: teamID.String()}
@@ -102,91 +108,102 @@ func (es *EventService) CreateTeamEvent(ctx context.Context, reqUserID uuid.UUID
 
 	defer txs.Rollback()
 
-	/*newEvent := &models.Event{
-		TeamID:    CreateEventReq.TeamID,
-		Title:     CreateEventReq.Name,
-		EventType: CreateEventReq.EventType,
-		Location:  CreateEventReq.Location,
-		StartTime: CreateEventReq.StartTime,
-		EndTime:   CreateEventReq.EndTime,
-	}*/
+	venue, err := es.resolveVenue(ctx, txs, req.VenueID, req.ResourceID, req.Location)
+	if err != nil {
+		return nil, err
+	}
 
-	createdEvent, err := es.CreateEvent(ctx, txs, teamID, eventTitle, eventType, location, startTime, endTime)
+	createdEvent, err := es.CreateEvent(ctx, txs, &models.Event{
+		TeamID:       teamID,
+		CreatedBy:    reqUserID,
+		Title:        req.Name,
+		EventType:    req.EventType,
+		Location:     venue.location,
+		Notes:        req.Notes,
+		StartTime:    req.StartTime,
+		EndTime:      req.EndTime,
+		RSVPDeadline: req.RSVPDeadline,
+		VenueID:      venue.venueID,
+		ResourceID:   venue.resourceID,
+	})
 	if err != nil {
 		es.l.Error("error creating event due to ", "error", err)
 		return nil, err
 	}
 	es.l.Info("event created successfully for team", "teamID", teamID)
-	es.l.Info("event", "createdEvent", createdEvent)
 
 	//write to database the attendace list
 	//prepopulate the initial list
-	if len(teamMembers) > 0 {
-		var attendanceRecords []models.Attendance
-		for _, member := range teamMembers {
-			memberID, err := uuid.Parse(member.UserId)
-			if err != nil {
-				return nil, err
-			}
-
-			attendanceRecords = append(attendanceRecords, models.Attendance{
-				EventID:    createdEvent.ID,
-				UserID:     memberID,
-				TeamID:     teamID,
-				Status:     "PENDING",
-				UpdateteAt: time.Now(),
-			})
+	var attendanceRecords []models.Attendance
+	for _, member := range teamMembers {
+		memberID, err := uuid.Parse(member.UserId)
+		if err != nil {
+			return nil, err
 		}
 
-		log.Printf("members: %s", attendanceRecords)
+		attendanceRecords = append(attendanceRecords, models.Attendance{
+			EventID:    createdEvent.ID,
+			UserID:     memberID,
+			TeamID:     teamID,
+			Status:     events.RSVPPending,
+			UpdateteAt: time.Now(),
+		})
+	}
 
-		//insert into attendance table (bulk insert->provides high performance)
-		if err := es.CreateBulkAttendance(ctx, txs, attendanceRecords); err != nil {
-			es.l.Error("error inserting to iniital attendance table")
-			return nil, err
-		}
-		es.l.Info("bulk rcord attendance created ")
+	//insert into attendance table (bulk insert->provides high performance)
+	if err := es.CreateBulkAttendance(ctx, txs, attendanceRecords); err != nil {
+		es.l.Error("error inserting to iniital attendance table")
+		return nil, err
+	}
+	es.l.Info("bulk rcord attendance created ", "attendees", len(attendanceRecords))
 
-		if err := txs.Commit(); err != nil {
-			log.Fatalf("error commiting /creating team event due to: %v", err)
-			return nil, err
-		}
-		es.l.Info("successfully created event for team %s", "teamID", teamID)
+	if err := es.checkConflicts(ctx, txs, req.Force, []uuid.UUID{createdEvent.ID}); err != nil {
+		return nil, err
+	}
 
+	if err := txs.Commit(); err != nil {
+		es.l.Error("error commiting /creating team event", "error", err)
+		return nil, err
 	}
+	es.l.Info("successfully created event for team", "teamID", teamID)
+
+	es.notifyCreated(ctx, createdEvent)
+	return createdEvent, nil
+}
 
-	return &models.Event{}, nil
+// notifyCreated publishes EventCreated for the team's activity feed. Like every publish it is best
+// effort, the event stays created when Kafka is down
+func (es *EventService) notifyCreated(ctx context.Context, event *models.Event) {
+	created := &events.EventCreated{
+		EventID:   event.ID,
+		TeamID:    event.TeamID,
+		Title:     event.Title,
+		Kind:      event.EventType,
+		Location:  event.Location,
+		StartTime: event.StartTime,
+		EndTime:   event.EndTime,
+		CreatedBy: event.CreatedBy,
+	}
+	if err := es.prod.PublishEventUpdate(ctx, created); err != nil {
+		log.Printf("kafka error publishing %s: %s", events.TypeEventCreated, err)
+	}
 }
 
-func (es *EventService) CreateEvent(ctx context.Context, tx *sql.Tx, teamID uuid.UUID, name string, eventtype string, location string, starttime, endtime time.Time) (*models.Event, error) {
+func (es *EventService) CreateEvent(ctx context.Context, tx *sql.Tx, e *models.Event) (*models.Event, error) {
 	es.l.Info("Create event database execution")
 
 	var newEvent models.Event
 
-	query := `INSERT INTO events(event_title,event_type,location,start_time,end_time) VALUES($1,$2,$3,$4,$5)
-	RETURNING event_id,event_title, event_type, location, start_time, end_time`
-
-	err := es.db.QueryRowContext(ctx, query, name, eventtype, location, starttime, endtime).Scan(
-		&newEvent.ID,
-		//&newEvent.TeamID,
-		&newEvent.Title,
-		&newEvent.EventType,
-		&newEvent.Location,
-		&newEvent.StartTime,
-		&newEvent.EndTime,
-	)
+	query := `INSERT INTO events(team_id,created_by,event_title,event_type,location,notes,start_time,end_time,rsvp_deadline,venue_id,resource_id)
+	VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11)
+	RETURNING ` + eventColumns
+
+	err := tx.QueryRowContext(ctx, query, e.TeamID, e.CreatedBy, e.Title, e.EventType, e.Location, e.Notes, e.StartTime.UTC(), e.EndTime.UTC(),
+		utcOrNil(e.RSVPDeadline), e.VenueID, e.ResourceID).Scan(eventFields(&newEvent)...)
 	if err != nil {
-		log.Fatalf("Error executing query:%v", err)
-		return nil, fmt.Errorf("issue inserting events ")
-	}
-	return &models.Event{
-		ID:        newEvent.ID,
-		Title:     newEvent.Title,
-		EventType: newEvent.EventType,
-		Location:  newEvent.Location,
-		StartTime: newEvent.StartTime,
-		EndTime:   newEvent.EndTime,
-	}, err
+		return nil, fmt.Errorf("issue inserting events: %w", err)
+	}
+	return &newEvent, nil
 }
 
 // Renamed for clarity and purpose. 'Insert' is redundant.
@@ -231,16 +248,6 @@ func (es *EventService) CreateBulkAttendance(ctx context.Context, tx *sql.Tx, re
 func (es *EventService) GetTeamEvents(ctx context.Context, eventID uuid.UUID, reqUserID uuid.UUID) (*models.EventDetails, error) {
 	es.l.Info("getting a single event details, ")
 
-	query := `SELECT teamID FROM events WHERE eventID=$1`
-
-	var teamID string
-
-	err := es.db.QueryRowContext(ctx, query, eventID).Scan(&teamID)
-	if err == sql.ErrNoRows {
-		es.l.Error("No team associated with thatt event")
-		return nil, err
-	}
-
 	//call get events
 	event, err := es.GetEvent(ctx, eventID)
 	if err != nil {
@@ -249,37 +256,24 @@ func (es *EventService) GetTeamEvents(ctx context.Context, eventID uuid.UUID, re
 		return nil, err
 	}
 
-	//gRPC call to team service to check membership
-	memberShipReq := &team_proto.GetTeamMembershipRequest{
-		TeamId: event.TeamID.String(),
-		UserId: []string{reqUserID.String()},
-	}
-
-	members, err := es.teamClient.CheckTeamMembership(ctx, memberShipReq)
-	if err != nil {
-		return nil, err
-	}
-
 	log.Printf("TeamID: %s", event.TeamID.String())
 
-	membersInfo, found := members.Members[reqUserID.String()]
-	if !found {
-		es.l.Info("Check if the requesting userId is a member of this team == reqUserId and teamID match")
-		es.l.Error("userID not is not a member")
-		return nil, ErrForbidden
-	}
-
-	isAllowed := membersInfo.Role == "coach" || membersInfo.Role == "manager" || membersInfo.Role == "player"
-	if !isAllowed {
-		es.l.Error("user does not posses a role in this team")
+	//gRPC call to team service to check the requester can view this team's events
+	if err := es.requireTeamPermission(ctx, event.TeamID, reqUserID, PermEventsView); err != nil {
+		es.l.Error("user is not allowed to view this event", "error", err)
+		return nil, err
 	}
 	es.l.Info("authorization done successfully")
 
 	//call get event attendace
 	attendanceList, err := es.GetAttendanceList(ctx, eventID)
 	if err != nil {
-		es.l.Error("")
-		log.Fatalf("Error due to : %v", err)
+		es.l.Error("failed to get attendance list", "error", err)
+		return nil, err
+	}
+
+	summary, err := es.attendanceSummary(ctx, es.db, eventID)
+	if err != nil {
 		return nil, err
 	}
 
@@ -304,7 +298,7 @@ func (es *EventService) GetTeamEvents(ctx context.Context, eventID uuid.UUID, re
 
 	var finalAttendanceList []models.AttendanceResponse
 
-	finalAttendanceList = make([]models.AttendanceResponse, len(attendanceList))
+	finalAttendanceList = make([]models.AttendanceResponse, 0, len(attendanceList))
 
 	for _, attendee := range attendanceList {
 		profile, found := userProfilesMap[attendee.UserID.String()]
@@ -325,19 +319,43 @@ func (es *EventService) GetTeamEvents(ctx context.Context, eventID uuid.UUID, re
 			Email:     profile.Email,
 			//TeamID: attendee.EventID,
 			EventStatus: attendee.Status,
+			Reason:      attendee.Reason,
+			RespondedAt: attendee.RespondedAt,
+			Overridden:  attendee.Overridden,
+			CheckIn:     attendee.CheckInStatus,
 		}
 
 		finalAttendanceList = append(finalAttendanceList, attendanceResp)
 	}
 
+	var result *models.GameResult
+	if strings.EqualFold(event.EventType, models.EventTypeGame) {
+		result, err = es.getResult(ctx, eventID)
+		if err != nil && err != ErrNotFound {
+			return nil, err
+		}
+	}
+
 	enrichedList := models.EventDetails{
 		EventID:    event.ID,
 		TeamID:     event.TeamID,
 		EventName:  event.Title,
+		EventType:  event.EventType,
 		Location:   event.Location,
+		VenueID:    event.VenueID,
+		ResourceID: event.ResourceID,
+		Notes:      event.Notes,
+		CreatedBy:  event.CreatedBy,
+		Status:     event.Status,
+		SeriesID:   event.SeriesID,
+		Occurrence: event.OccurrenceDate,
 		StartTime:  event.StartTime,
 		EndTime:    event.EndTime,
 		Attendance: finalAttendanceList,
+
+		RSVPDeadline: rsvpDeadline(event),
+		RSVPSummary:  *summary,
+		Result:       result,
 	}
 
 	//var userIDs []string
@@ -350,27 +368,39 @@ func (es *EventService) GetAttendanceList(ctx context.Context, eventID uuid.UUID
 
 	var EventAttendance []*models.Attendance
 
-	query := `SELECT user_id,event_status,updated_at FROM attendance WHERE event_id=$1`
+	query := `SELECT a.event_id,a.team_id,a.user_id,a.event_status,a.updated_at,COALESCE(a.reason,''),a.responded_at,a.updated_by,a.overridden,
+	COALESCE(c.status,''),COALESCE(c.method,'')
+	FROM attendance a LEFT JOIN check_ins c ON c.event_id=a.event_id AND c.user_id=a.user_id WHERE a.event_id=$1`
 
 	rows, err := es.db.QueryContext(ctx, query, eventID)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
 	for rows.Next() {
 		var attendance models.Attendance
 		err = rows.Scan(
-			//&attendance.EventID,
-			//&attendance.TeamID,
+			&attendance.EventID,
+			&attendance.TeamID,
 			&attendance.UserID,
 			&attendance.Status,
 			&attendance.UpdateteAt,
+			&attendance.Reason,
+			&attendance.RespondedAt,
+			&attendance.UpdatedBy,
+			&attendance.Overridden,
+			&attendance.CheckInStatus,
+			&attendance.CheckInMethod,
 		)
+		if err != nil {
+			return nil, err
+		}
 
 		EventAttendance = append(EventAttendance, &attendance)
 	}
 
-	return EventAttendance, err
+	return EventAttendance, rows.Err()
 }
 
 func (es *EventService) GetEvent(ctx context.Context, eventID uuid.UUID) (*models.Event, error) {
@@ -378,91 +408,96 @@ func (es *EventService) GetEvent(ctx context.Context, eventID uuid.UUID) (*model
 
 	var event models.Event
 
-	//query := `SELECT event_id,event_title,event_type,location,start_time,end_time FROM events WHERE event_id = $1`
-	query := `SELECT e.event_id,a.team_id,e.event_title,e.event_type,e.location,e.start_time,e.end_time FROM events e JOIN attendance a ON e.event_id = a.event_id`
-
-	err := es.db.QueryRowContext(ctx, query).Scan(
-		&event.ID,
-		&event.TeamID,
-		&event.Title,
-		&event.EventType,
-		&event.Location,
-		&event.StartTime,
-		&event.EndTime,
-	)
+	query := `SELECT ` + eventColumns + ` FROM events WHERE event_id = $1 AND team_id IS NOT NULL`
+
+	err := es.db.QueryRowContext(ctx, query, eventID).Scan(eventFields(&event)...)
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
 		return nil, err
 	}
 	return &event, nil
 }
 
-func (es *EventService) UpdateEventDetails(ctx context.Context, reqUserID uuid.UUID, teamID uuid.UUID, eventID uuid.UUID, toUpdate models.UpdateEventReq) (*models.Event, error) {
+// UpdateEventDetails edits a single event, on a recurring event only this occurrence changes and it
+// stops following later edits of the series
+func (es *EventService) UpdateEventDetails(ctx context.Context, reqUserID uuid.UUID, eventID uuid.UUID, toUpdate models.UpdateEventReq) (*models.Event, error) {
 	es.l.Info("PUT operation for the event service")
 
-	txs, err := es.db.BeginTx(ctx, nil)
+	event, err := es.GetEvent(ctx, eventID)
 	if err != nil {
-		es.l.Error("Error while trying to begin transactions")
+		es.l.Error("failed to get event details")
+		return nil, err
 	}
 
-	defer txs.Rollback()
+	if err := es.requireTeamPermission(ctx, event.TeamID, reqUserID, PermEventsManage); err != nil {
+		es.l.Error("User NOT allowed to update Event details")
+		return nil, err
+	}
 
-	team, err := es.GetEvent(ctx, eventID)
-	if err != nil {
-		es.l.Error("failed to get event details")
+	if err := es.allowForce(ctx, event.TeamID, reqUserID, toUpdate.Force); err != nil {
 		return nil, err
 	}
 
-	teamMembersReq := &team_proto.GetTeamMembershipRequest{
-		UserId: []string{reqUserID.String()},
-		TeamId: team.TeamID.String(),
+	if err := validateTimes(toUpdate.StartTime, toUpdate.EndTime); err != nil {
+		return nil, err
+	}
+	if toUpdate.RSVPDeadline != nil && !toUpdate.RSVPDeadline.Before(toUpdate.EndTime) {
+		return nil, fmt.Errorf("%w: the RSVP deadline must be before the event ends", ErrInvalidEvent)
 	}
 
-	membersResp, err := es.teamClient.CheckTeamMembership(ctx, teamMembersReq)
+	tx, err := es.db.BeginTx(ctx, nil)
 	if err != nil {
-		es.l.Error("Error checking team membership response")
 		return nil, err
 	}
+	defer tx.Rollback()
 
-	userMembersMap := membersResp.Members[reqUserID.String()]
-
-	isAuthorized := userMembersMap.Role == "coach" || userMembersMap.Role == "manager"
-
-	if !isAuthorized {
-		es.l.Error("User NOT allowed to update Event details")
-		return nil, ErrForbidden
+	venue, err := es.resolveVenue(ctx, tx, toUpdate.VenueID, toUpdate.ResourceID, toUpdate.Location)
+	if err != nil {
+		return nil, err
 	}
 
-	//perform database write
-	//updatedEvent,err := es.UpdateEvent
-	//query := `UPDATE event SET  name=$1,location=$2,start_time=$3,end_time=$4 WHERE event_id=$1`
-	updatedEvent, err := es.UpdateEvent(ctx, team.ID, toUpdate.Title, toUpdate.Location, toUpdate.StartTime, toUpdate.EndTime)
+	updatedEvent, err := es.UpdateEvent(ctx, tx, event.ID, &models.Event{
+		Title:        toUpdate.Title,
+		Location:     venue.location,
+		Notes:        toUpdate.Notes,
+		StartTime:    toUpdate.StartTime,
+		EndTime:      toUpdate.EndTime,
+		RSVPDeadline: toUpdate.RSVPDeadline,
+		VenueID:      venue.venueID,
+		ResourceID:   venue.resourceID,
+	})
 	if err != nil {
-		//
 		return nil, err
 	}
 
+	if err := es.checkConflicts(ctx, tx, toUpdate.Force, []uuid.UUID{updatedEvent.ID}); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
 	return updatedEvent, nil
 }
 
-func (es *EventService) UpdateEvent(ctx context.Context, eventID uuid.UUID, name string, location string, start time.Time, end time.Time) (*models.Event, error) {
+// UpdateEvent writes the title, place, notes, times and RSVP deadline of e to the event
+func (es *EventService) UpdateEvent(ctx context.Context, q queryer, eventID uuid.UUID, e *models.Event) (*models.Event, error) {
 	es.l.Info("update team details database write")
 
 	var updateEvent models.Event
 
-	query := `UPDATE events SET name=$1,location=$2,start_time=$3,end_time=$4 WHERE event_id=$5
-	RETURNING event_id,team_id,name,event_type,location,start_time,endtime`
+	query := `UPDATE events SET sequence=sequence+1,event_title=$1,location=$2,notes=$3,start_time=$4,end_time=$5,rsvp_deadline=$6,
+	venue_id=$7,resource_id=$8,updated_at=NOW(),is_exception = series_id IS NOT NULL WHERE event_id=$9
+	RETURNING ` + eventColumns
 
-	err := es.db.QueryRowContext(ctx, query, name, location, start, end).Scan(
-		&updateEvent.Title,
-		&updateEvent.EventType,
-		&updateEvent.Location,
-		&updateEvent.StartTime,
-		&updateEvent.EndTime,
-	)
+	err := q.QueryRowContext(ctx, query, e.Title, e.Location, e.Notes, e.StartTime.UTC(), e.EndTime.UTC(), utcOrNil(e.RSVPDeadline),
+		e.VenueID, e.ResourceID, eventID).Scan(eventFields(&updateEvent)...)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			es.l.Error("")
+			es.l.Error("no event to update", "eventID", eventID)
 			return nil, ErrNotFound
 
 		}
@@ -471,10 +506,22 @@ func (es *EventService) UpdateEvent(ctx context.Context, eventID uuid.UUID, name
 	return &updateEvent, nil
 }
 
-func (es *EventService) GetAllevents(ctx context.Context, reqUserID uuid.UUID) ([]*models.Event, error) {
-	return nil, nil
-}
+// requireTeamPermission asks team-service whether the user holds the permission on the team
+func (es *EventService) requireTeamPermission(ctx context.Context, teamID uuid.UUID, userID uuid.UUID, permission string) error {
+	permissionReq := &team_proto.CheckPermissionRequest{
+		TeamId:     teamID.String(),
+		UserId:     userID.String(),
+		Permission: permission,
+	}
 
-func (es *EventService) DeleteEvent(ctx context.Context, reqUerId uuid.UUID) (*models.Event, error) {
-	return nil, nil
+	permissionRes, err := es.teamClient.CheckPermission(ctx, permissionReq)
+	if err != nil {
+		es.l.Error("gRPC permission check to team service failed", "error", err)
+		return err
+	}
+
+	if !permissionRes.Allowed {
+		return ErrForbidden
+	}
+	return nil
 }
@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/wycliff-ochieng/common_packages/team_grpc/team_proto"
+	"github.com/wycliff-ochieng/internal/ics"
+	"github.com/wycliff-ochieng/internal/models"
+)
+
+var ErrInvalidFeedToken = errors.New("invalid or revoked feed token")
+
+const (
+	// feeds carry events that started up to this long ago, older ones drop out of subscribed calendars
+	feedHistory = 90 * 24 * time.Hour
+	// calendar apps are asked to refetch this often
+	feedRefresh = time.Hour
+)
+
+func hashFeedToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// CreateFeedToken issues a new calendar feed token for the user and revokes the previous one, so
+// rotating it cuts off every calendar subscribed with the old link
+func (es *EventService) CreateFeedToken(ctx context.Context, userID uuid.UUID) (*models.FeedToken, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, err
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	tx, err := es.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `UPDATE calendar_feed_tokens SET revoked_at=NOW() WHERE user_id=$1 AND revoked_at IS NULL`, userID); err != nil {
+		return nil, err
+	}
+
+	feedToken := models.FeedToken{Token: token}
+	err = tx.QueryRowContext(ctx, `INSERT INTO calendar_feed_tokens(token_hash,user_id) VALUES($1,$2) RETURNING created_at`,
+		hashFeedToken(token), userID).Scan(&feedToken.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("issue storing feed token: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	feedToken.PersonalFeed = "/api/events/me.ics?token=" + token
+	feedToken.TeamFeed = "/api/events/team/{team_id}.ics?token=" + token
+	return &feedToken, nil
+}
+
+// RevokeFeedToken stops the user's feed links from working
+func (es *EventService) RevokeFeedToken(ctx context.Context, userID uuid.UUID) error {
+	res, err := es.db.ExecContext(ctx, `UPDATE calendar_feed_tokens SET revoked_at=NOW() WHERE user_id=$1 AND revoked_at IS NULL`, userID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// feedUser resolves a feed token to its user
+func (es *EventService) feedUser(ctx context.Context, token string) (uuid.UUID, error) {
+	var userID uuid.UUID
+	if token == "" {
+		return userID, ErrInvalidFeedToken
+	}
+
+	err := es.db.QueryRowContext(ctx, `UPDATE calendar_feed_tokens SET last_used_at=NOW() WHERE token_hash=$1 AND revoked_at IS NULL
+	RETURNING user_id`, hashFeedToken(token)).Scan(&userID)
+	if err == sql.ErrNoRows {
+		return userID, ErrInvalidFeedToken
+	}
+	return userID, err
+}
+
+// TeamFeed is the calendar of one team for the holder of the feed token
+func (es *EventService) TeamFeed(ctx context.Context, token string, teamID uuid.UUID) (*ics.Calendar, error) {
+	userID, err := es.feedUser(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := es.requireTeamPermission(ctx, teamID, userID, PermEventsView); err != nil {
+		return nil, err
+	}
+
+	team, err := es.teamClient.GetTeamSummary(ctx, &team_proto.GetTeamSummaryRequest{TeamId: teamID.String()})
+	if err != nil {
+		return nil, fmt.Errorf("cause of failure: %v", err)
+	}
+
+	events, err := es.feedEvents(ctx, []uuid.UUID{teamID})
+	if err != nil {
+		return nil, err
+	}
+
+	cal := &ics.Calendar{Name: team.Name, RefreshInterval: feedRefresh}
+	for _, e := range events {
+		cal.Events = append(cal.Events, toICSEvent(e, ""))
+	}
+	return cal, nil
+}
+
+// PersonalFeed is the calendar of every team the holder of the feed token plays for
+func (es *EventService) PersonalFeed(ctx context.Context, token string) (*ics.Calendar, error) {
+	userID, err := es.feedUser(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := es.teamClient.GetUserTeams(ctx, &team_proto.GetUserTeamsRequest{UserId: userID.String()})
+	if err != nil {
+		return nil, fmt.Errorf("cause of failure: %v", err)
+	}
+
+	teamNames := map[uuid.UUID]string{}
+	teamIDs := make([]uuid.UUID, 0, len(res.Teams))
+	for _, t := range res.Teams {
+		id, err := uuid.Parse(t.TeamId)
+		if err != nil {
+			return nil, err
+		}
+		teamNames[id] = t.Name
+		teamIDs = append(teamIDs, id)
+	}
+
+	cal := &ics.Calendar{Name: "My team events", RefreshInterval: feedRefresh}
+	if len(teamIDs) == 0 {
+		return cal, nil
+	}
+
+	events, err := es.feedEvents(ctx, teamIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range events {
+		cal.Events = append(cal.Events, toICSEvent(e, teamNames[e.TeamID]))
+	}
+	return cal, nil
+}
+
+func (es *EventService) feedEvents(ctx context.Context, teamIDs []uuid.UUID) ([]models.Event, error) {
+	ids := make([]string, 0, len(teamIDs))
+	for _, id := range teamIDs {
+		ids = append(ids, id.String())
+	}
+
+	rows, err := es.db.QueryContext(ctx, `SELECT `+eventColumns+` FROM events WHERE team_id = ANY($1::uuid[]) AND start_time >= $2
+	ORDER BY start_time`, pq.Array(ids), time.Now().Add(-feedHistory).UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []models.Event
+	for rows.Next() {
+		var event models.Event
+		if err := rows.Scan(eventFields(&event)...); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+// toICSEvent maps an event, the event id is the UID so it survives edits. teamName prefixes the
+// summary on feeds that mix teams
+func toICSEvent(e models.Event, teamName string) ics.Event {
+	summary := e.Title
+	if teamName != "" {
+		summary = teamName + ": " + e.Title
+	}
+
+	status := ics.StatusConfirmed
+	if e.Status == models.StatusCancelled {
+		status = ics.StatusCancelled
+	}
+
+	return ics.Event{
+		UID:          e.ID.String() + "@event-service",
+		Sequence:     e.Sequence,
+		Status:       status,
+		Summary:      summary,
+		Description:  e.Notes,
+		Location:     e.Location,
+		Categories:   e.EventType,
+		Start:        e.StartTime,
+		End:          e.EndTime,
+		Created:      e.CreatedAt,
+		LastModified: e.UpdatedAt,
+	}
+}
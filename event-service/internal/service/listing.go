@@ -0,0 +1,237 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/wycliff-ochieng/common_packages/team_grpc/team_proto"
+	"github.com/wycliff-ochieng/internal/models"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+const (
+	defaultListLimit  = 50
+	defaultAgendaSpan = 90 * 24 * time.Hour
+	// week and month views are not paginated, this keeps a busy month bounded
+	maxCalendarEvents = 1000
+)
+
+// EventCursor is the position of the last event on an agenda page
+type EventCursor struct {
+	StartTime time.Time
+	EventID   uuid.UUID
+}
+
+func encodeCursor(c interface{}) (string, error) {
+	cursorJSON, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(cursorJSON), nil
+}
+
+func decodeCursor(cursor string, c interface{}) error {
+	cursorJSON, err := base64.StdEncoding.DecodeString(cursor)
+	if err != nil {
+		return ErrInvalidCursor
+	}
+	if err := json.Unmarshal(cursorJSON, c); err != nil {
+		return ErrInvalidCursor
+	}
+	return nil
+}
+
+// calendarRange is the range a view covers. Weeks start on Monday and months on the 1st, in the
+// local time of loc, around the date of from. The agenda runs from from, today by default
+func calendarRange(view string, from time.Time, to time.Time, loc *time.Location) (time.Time, time.Time, error) {
+	anchor := from
+	if anchor.IsZero() {
+		anchor = time.Now()
+	}
+	local := anchor.In(loc)
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+
+	switch view {
+	case models.ViewWeek:
+		start := day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+		return start, start.AddDate(0, 0, 7), nil
+	case models.ViewMonth:
+		start := time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, loc)
+		return start, start.AddDate(0, 1, 0), nil
+	case models.ViewAgenda:
+		if from.IsZero() {
+			from = day
+		}
+		if to.IsZero() {
+			to = from.Add(defaultAgendaSpan)
+		}
+		if !from.Before(to) {
+			return from, to, fmt.Errorf("%w: from must be before to", ErrInvalidRange)
+		}
+		if to.Sub(from) > maxStatsRange {
+			return from, to, fmt.Errorf("%w: at most 366 days", ErrInvalidRange)
+		}
+		return from, to, nil
+	}
+	return from, to, fmt.Errorf("%w: view must be agenda, week or month", ErrInvalidRange)
+}
+
+// ListEvents lists the events of every team the caller is on, or of one of them. The teams come
+// from a single GetUserTeams call to team-service
+func (es *EventService) ListEvents(ctx context.Context, reqUserID uuid.UUID, params models.ListEventsParams) (*models.EventCalendar, error) {
+	if params.View == "" {
+		params.View = models.ViewAgenda
+	}
+	if params.Location == nil {
+		params.Location = time.UTC
+	}
+	if params.Limit == 0 {
+		params.Limit = defaultListLimit
+	}
+
+	from, to, err := calendarRange(params.View, params.From, params.To, params.Location)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor *EventCursor
+	if params.Cursor != "" {
+		if params.View != models.ViewAgenda {
+			return nil, fmt.Errorf("%w: only the agenda is paginated", ErrInvalidCursor)
+		}
+		cursor = &EventCursor{}
+		if err := decodeCursor(params.Cursor, cursor); err != nil {
+			return nil, err
+		}
+	}
+
+	res, err := es.teamClient.GetUserTeams(ctx, &team_proto.GetUserTeamsRequest{UserId: reqUserID.String()})
+	if err != nil {
+		return nil, fmt.Errorf("cause of failure: %v", err)
+	}
+
+	teamNames := map[string]string{}
+	for _, t := range res.Teams {
+		teamNames[t.TeamId] = t.Name
+	}
+
+	teamIDs := make([]string, 0, len(teamNames))
+	if params.TeamID != uuid.Nil {
+		if _, ok := teamNames[params.TeamID.String()]; !ok {
+			return nil, ErrForbidden
+		}
+		teamIDs = append(teamIDs, params.TeamID.String())
+	} else {
+		for id := range teamNames {
+			teamIDs = append(teamIDs, id)
+		}
+	}
+
+	calendar := &models.EventCalendar{
+		View:     params.View,
+		TimeZone: params.Location.String(),
+		From:     from,
+		To:       to,
+	}
+
+	var events []models.CalendarEvent
+	if len(teamIDs) > 0 {
+		limit := params.Limit
+		if params.View != models.ViewAgenda {
+			limit = maxCalendarEvents
+		}
+		events, err = es.listEvents(ctx, reqUserID, teamIDs, from, to, params, cursor, limit+1)
+		if err != nil {
+			return nil, err
+		}
+		if len(events) > limit {
+			if params.View != models.ViewAgenda {
+				return nil, fmt.Errorf("%w: more than %d events, filter by team or type", ErrInvalidRange, maxCalendarEvents)
+			}
+			events = events[:limit]
+			last := events[len(events)-1]
+			nextCursor, err := encodeCursor(EventCursor{StartTime: last.StartTime, EventID: last.ID})
+			if err != nil {
+				return nil, err
+			}
+			calendar.NextCursor = nextCursor
+		}
+	}
+
+	for i := range events {
+		events[i].TeamName = teamNames[events[i].TeamID.String()]
+	}
+
+	if params.View == models.ViewAgenda {
+		calendar.Events = append([]models.CalendarEvent{}, events...)
+		return calendar, nil
+	}
+
+	byDate := map[string][]models.CalendarEvent{}
+	for _, e := range events {
+		date := e.StartTime.In(params.Location).Format("2006-01-02")
+		byDate[date] = append(byDate[date], e)
+	}
+	for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+		date := day.Format("2006-01-02")
+		dayEvents := byDate[date]
+		if dayEvents == nil {
+			dayEvents = []models.CalendarEvent{}
+		}
+		calendar.Days = append(calendar.Days, models.CalendarDay{Date: date, Events: dayEvents})
+	}
+	return calendar, nil
+}
+
+func (es *EventService) listEvents(ctx context.Context, userID uuid.UUID, teamIDs []string, from time.Time, to time.Time, params models.ListEventsParams, cursor *EventCursor, limit int) ([]models.CalendarEvent, error) {
+	var queryBuilder strings.Builder
+	queryBuilder.WriteString(`SELECT ` + eventColumns + `,
+	COALESCE((SELECT a.event_status FROM attendance a WHERE a.event_id=events.event_id AND a.user_id=$2),'')
+	FROM events WHERE team_id = ANY($1::uuid[]) AND start_time >= $3 AND start_time < $4`)
+
+	args := []interface{}{pq.Array(teamIDs), userID, from.UTC(), to.UTC()}
+	paramIndex := 5
+
+	if !params.IncludeCancelled {
+		queryBuilder.WriteString(fmt.Sprintf(" AND status <> $%d", paramIndex))
+		args = append(args, models.StatusCancelled)
+		paramIndex++
+	}
+	if len(params.Types) > 0 {
+		queryBuilder.WriteString(fmt.Sprintf(" AND LOWER(event_type) = ANY($%d)", paramIndex))
+		args = append(args, pq.Array(params.Types))
+		paramIndex++
+	}
+	if cursor != nil {
+		queryBuilder.WriteString(fmt.Sprintf(" AND (start_time, event_id) > ($%d, $%d)", paramIndex, paramIndex+1))
+		args = append(args, cursor.StartTime.UTC(), cursor.EventID)
+		paramIndex += 2
+	}
+
+	queryBuilder.WriteString(fmt.Sprintf(" ORDER BY start_time, event_id LIMIT $%d", paramIndex))
+	args = append(args, limit)
+
+	rows, err := es.db.QueryContext(ctx, queryBuilder.String(), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []models.CalendarEvent
+	for rows.Next() {
+		var e models.CalendarEvent
+		if err := rows.Scan(append(eventFields(&e.Event), &e.MyRSVP)...); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
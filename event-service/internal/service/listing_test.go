@@ -0,0 +1,41 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/wycliff-ochieng/internal/models"
+)
+
+func TestCalendarRange(t *testing.T) {
+	nairobi, err := time.LoadLocation("Africa/Nairobi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	//late on a Sunday still belongs to the week that started the Monday before
+	anchor := time.Date(2025, 3, 9, 23, 30, 0, 0, nairobi)
+
+	from, to, err := calendarRange(models.ViewWeek, anchor, time.Time{}, nairobi)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2025, 3, 3, 0, 0, 0, 0, nairobi); !from.Equal(want) || !to.Equal(want.AddDate(0, 0, 7)) {
+		t.Errorf("week = %s - %s, want the week starting %s", from, to, want)
+	}
+
+	from, to, err = calendarRange(models.ViewMonth, anchor, time.Time{}, nairobi)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2025, 3, 1, 0, 0, 0, 0, nairobi); !from.Equal(want) || !to.Equal(time.Date(2025, 4, 1, 0, 0, 0, 0, nairobi)) {
+		t.Errorf("month = %s - %s", from, to)
+	}
+
+	if _, _, err := calendarRange(models.ViewAgenda, anchor, anchor.AddDate(2, 0, 0), nairobi); !errors.Is(err, ErrInvalidRange) {
+		t.Errorf("two year agenda: got %v, want ErrInvalidRange", err)
+	}
+	if _, _, err := calendarRange("year", anchor, time.Time{}, nairobi); !errors.Is(err, ErrInvalidRange) {
+		t.Errorf("unknown view: got %v, want ErrInvalidRange", err)
+	}
+}
@@ -0,0 +1,423 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wycliff-ochieng/common_packages/team_grpc/team_proto"
+	"github.com/wycliff-ochieng/internal/models"
+)
+
+var ErrGameNotStarted = fmt.Errorf("%w: the game has not started", ErrConflict)
+
+const (
+	maxOpponentNameLength = 150
+	maxPeriods            = 20
+	maxPeriodLength       = 10
+	formLength            = 5
+)
+
+// scoreFormat is how a sport splits a game into periods
+type scoreFormat struct {
+	//in playing order
+	periods []string
+	//prefix of the numbered overtime periods played after them, OT or OT1, OT2...
+	overtime string
+	//breaks a tie without adding to the score
+	shootout string
+	//the score is the number of periods won, as in volleyball sets
+	bySets bool
+}
+
+var (
+	halvesFormat   = scoreFormat{periods: []string{"1H", "2H", "ET1", "ET2"}, shootout: "PEN"}
+	quartersFormat = scoreFormat{periods: []string{"Q1", "Q2", "Q3", "Q4"}, overtime: "OT"}
+)
+
+// scoreFormats are keyed by the team's sport in lower case, other sports accept any period names
+var scoreFormats = map[string]scoreFormat{
+	"soccer":     halvesFormat,
+	"football":   halvesFormat,
+	"futsal":     halvesFormat,
+	"handball":   halvesFormat,
+	"rugby":      {periods: []string{"1H", "2H", "ET1", "ET2"}},
+	"basketball": quartersFormat,
+	"netball":    {periods: []string{"Q1", "Q2", "Q3", "Q4"}, overtime: "ET"},
+	"hockey":     {periods: []string{"Q1", "Q2", "Q3", "Q4"}, shootout: "SO"},
+	"ice hockey": {periods: []string{"P1", "P2", "P3"}, overtime: "OT", shootout: "SO"},
+	"volleyball": {periods: []string{"S1", "S2", "S3", "S4", "S5"}, bySets: true},
+}
+
+// position orders the period within a game of f, ok is false when f has no such period
+func (f *scoreFormat) position(period string) (int, bool) {
+	for i, p := range f.periods {
+		if p == period {
+			return i, true
+		}
+	}
+	if f.overtime != "" && strings.HasPrefix(period, f.overtime) {
+		n := 1
+		if rest := strings.TrimPrefix(period, f.overtime); rest != "" {
+			var err error
+			if n, err = strconv.Atoi(rest); err != nil || n < 1 {
+				return 0, false
+			}
+		}
+		return len(f.periods) + n, true
+	}
+	if f.shootout != "" && period == f.shootout {
+		return 1 << 30, true
+	}
+	return 0, false
+}
+
+type gameScore struct {
+	scoreFor     int
+	scoreAgainst int
+	result       string
+	decidedBy    string
+	periods      []models.PeriodScore
+}
+
+// scoreGame checks the periods of req against the sport's format, nil for sports without one, and
+// works out the final score and result. A score sent alongside the periods has to match them
+func scoreGame(format *scoreFormat, req *models.GameResultReq) (*gameScore, error) {
+	if len(req.Periods) > maxPeriods {
+		return nil, fmt.Errorf("%w: at most %d periods", ErrInvalidEvent, maxPeriods)
+	}
+
+	score := &gameScore{periods: []models.PeriodScore{}}
+	var shootout *models.PeriodScore
+	seen := map[string]bool{}
+	last := -1
+
+	for _, p := range req.Periods {
+		p.Period = strings.ToUpper(strings.TrimSpace(p.Period))
+		if p.Period == "" || len(p.Period) > maxPeriodLength {
+			return nil, fmt.Errorf("%w: periods need a name of up to %d characters", ErrInvalidEvent, maxPeriodLength)
+		}
+		if seen[p.Period] {
+			return nil, fmt.Errorf("%w: period %s is sent twice", ErrInvalidEvent, p.Period)
+		}
+		seen[p.Period] = true
+		if p.For < 0 || p.Against < 0 {
+			return nil, fmt.Errorf("%w: scores cannot be negative", ErrInvalidEvent)
+		}
+
+		if format != nil {
+			pos, ok := format.position(p.Period)
+			if !ok {
+				return nil, fmt.Errorf("%w: unknown period %s, expected %s", ErrInvalidEvent, p.Period, strings.Join(format.periods, ", "))
+			}
+			if pos <= last {
+				return nil, fmt.Errorf("%w: period %s is out of order", ErrInvalidEvent, p.Period)
+			}
+			last = pos
+
+			if p.Period == format.shootout {
+				shootout = &p
+				score.periods = append(score.periods, p)
+				continue
+			}
+		}
+
+		if format != nil && format.bySets {
+			if p.For > p.Against {
+				score.scoreFor++
+			} else if p.Against > p.For {
+				score.scoreAgainst++
+			}
+		} else {
+			score.scoreFor += p.For
+			score.scoreAgainst += p.Against
+		}
+		score.periods = append(score.periods, p)
+	}
+
+	switch {
+	case len(req.Periods) == 0:
+		if req.ScoreFor == nil || req.ScoreAgainst == nil {
+			return nil, fmt.Errorf("%w: send the score or its periods", ErrInvalidEvent)
+		}
+		if *req.ScoreFor < 0 || *req.ScoreAgainst < 0 {
+			return nil, fmt.Errorf("%w: scores cannot be negative", ErrInvalidEvent)
+		}
+		score.scoreFor, score.scoreAgainst = *req.ScoreFor, *req.ScoreAgainst
+	case (req.ScoreFor != nil && *req.ScoreFor != score.scoreFor) || (req.ScoreAgainst != nil && *req.ScoreAgainst != score.scoreAgainst):
+		return nil, fmt.Errorf("%w: the score does not add up to its periods, %d-%d", ErrInvalidEvent, score.scoreFor, score.scoreAgainst)
+	}
+
+	switch {
+	case score.scoreFor > score.scoreAgainst:
+		score.result = models.ResultWin
+	case score.scoreFor < score.scoreAgainst:
+		score.result = models.ResultLoss
+	default:
+		score.result = models.ResultDraw
+	}
+
+	if shootout != nil {
+		if score.result != models.ResultDraw {
+			return nil, fmt.Errorf("%w: a %s shootout is only played when the score is level", ErrInvalidEvent, shootout.Period)
+		}
+		if shootout.For == shootout.Against {
+			return nil, fmt.Errorf("%w: a shootout cannot end level", ErrInvalidEvent)
+		}
+		score.result = models.ResultLoss
+		if shootout.For > shootout.Against {
+			score.result = models.ResultWin
+		}
+		score.decidedBy = shootout.Period
+	}
+	return score, nil
+}
+
+// RecordResult records or corrects the result of a game that has started. The game is filed under
+// the team-service season its date falls in
+func (es *EventService) RecordResult(ctx context.Context, reqUserID uuid.UUID, eventID uuid.UUID, req models.GameResultReq) (*models.GameResult, error) {
+	es.l.Info("recording game result", "eventID", eventID)
+
+	event, err := es.GetEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := es.requireTeamPermission(ctx, event.TeamID, reqUserID, PermEventsManage); err != nil {
+		return nil, err
+	}
+
+	if !strings.EqualFold(event.EventType, models.EventTypeGame) {
+		return nil, fmt.Errorf("%w: results are only recorded for games", ErrInvalidEvent)
+	}
+	if event.Status == models.StatusCancelled {
+		return nil, ErrEventCanceled
+	}
+	if event.StartTime.After(time.Now()) {
+		return nil, ErrGameNotStarted
+	}
+
+	homeAway := strings.ToUpper(strings.TrimSpace(req.HomeAway))
+	if homeAway != models.GameHome && homeAway != models.GameAway && homeAway != models.GameNeutral {
+		return nil, fmt.Errorf("%w: HomeAway must be HOME, AWAY or NEUTRAL", ErrInvalidEvent)
+	}
+
+	team, err := es.teamClient.GetTeamSummary(ctx, &team_proto.GetTeamSummaryRequest{TeamId: event.TeamID.String()})
+	if err != nil {
+		return nil, fmt.Errorf("cause of failure: %v", err)
+	}
+
+	var format *scoreFormat
+	if f, ok := scoreFormats[strings.ToLower(strings.TrimSpace(team.Sport))]; ok {
+		format = &f
+	}
+	score, err := scoreGame(format, &req)
+	if err != nil {
+		return nil, err
+	}
+
+	opponentName := strings.TrimSpace(req.OpponentName)
+	opponentID := uuid.NullUUID{UUID: req.OpponentTeamID, Valid: req.OpponentTeamID != uuid.Nil}
+	if opponentID.Valid {
+		if opponentID.UUID == event.TeamID {
+			return nil, fmt.Errorf("%w: a team cannot play itself", ErrInvalidEvent)
+		}
+		opponent, err := es.teamClient.GetTeamSummary(ctx, &team_proto.GetTeamSummaryRequest{TeamId: opponentID.UUID.String()})
+		if err != nil {
+			return nil, fmt.Errorf("%w: unknown opponent team: %v", ErrInvalidEvent, err)
+		}
+		if opponentName == "" {
+			opponentName = opponent.Name
+		}
+	}
+	if opponentName == "" {
+		return nil, fmt.Errorf("%w: the opponent is required", ErrInvalidEvent)
+	}
+	if len([]rune(opponentName)) > maxOpponentNameLength {
+		return nil, fmt.Errorf("%w: opponent name is longer than %d characters", ErrInvalidEvent, maxOpponentNameLength)
+	}
+
+	season, err := es.teamClient.GetRosterOnDate(ctx, &team_proto.GetRosterOnDateRequest{
+		TeamId: event.TeamID.String(),
+		Date:   event.StartTime.UTC().Format(dateFormat),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cause of failure: %v", err)
+	}
+	var seasonID uuid.NullUUID
+	if season.SeasonId != "" {
+		id, err := uuid.Parse(season.SeasonId)
+		if err != nil {
+			return nil, err
+		}
+		seasonID = uuid.NullUUID{UUID: id, Valid: true}
+	}
+
+	periods, err := json.Marshal(score.periods)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = es.db.ExecContext(ctx, `INSERT INTO game_results(event_id,team_id,season_id,season_name,opponent_team_id,opponent_name,home_away,
+	score_for,score_against,periods,result,decided_by,recorded_by)
+	VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13)
+	ON CONFLICT (event_id) DO UPDATE SET season_id=EXCLUDED.season_id,season_name=EXCLUDED.season_name,
+	opponent_team_id=EXCLUDED.opponent_team_id,opponent_name=EXCLUDED.opponent_name,home_away=EXCLUDED.home_away,
+	score_for=EXCLUDED.score_for,score_against=EXCLUDED.score_against,periods=EXCLUDED.periods,result=EXCLUDED.result,
+	decided_by=EXCLUDED.decided_by,recorded_by=EXCLUDED.recorded_by,updated_at=NOW()`,
+		event.ID, event.TeamID, seasonID, season.SeasonName, opponentID, opponentName, homeAway,
+		score.scoreFor, score.scoreAgainst, string(periods), score.result, score.decidedBy, reqUserID)
+	if err != nil {
+		return nil, fmt.Errorf("issue recording game result: %w", err)
+	}
+
+	return es.getResult(ctx, event.ID)
+}
+
+const resultColumns = `r.event_id,r.team_id,COALESCE(e.event_title,''),e.start_time,r.season_id,r.season_name,r.opponent_team_id,
+	r.opponent_name,r.home_away,r.score_for,r.score_against,r.periods,r.result,r.decided_by,r.recorded_by,r.recorded_at,r.updated_at`
+
+// rowScanner is satisfied by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanResult(row rowScanner) (*models.GameResult, error) {
+	var r models.GameResult
+	var periods []byte
+	err := row.Scan(
+		&r.EventID,
+		&r.TeamID,
+		&r.Title,
+		&r.StartTime,
+		&r.SeasonID,
+		&r.SeasonName,
+		&r.OpponentTeamID,
+		&r.OpponentName,
+		&r.HomeAway,
+		&r.ScoreFor,
+		&r.ScoreAgainst,
+		&periods,
+		&r.Result,
+		&r.DecidedBy,
+		&r.RecordedBy,
+		&r.RecordedAt,
+		&r.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(periods, &r.Periods); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+func (es *EventService) getResult(ctx context.Context, eventID uuid.UUID) (*models.GameResult, error) {
+	result, err := scanResult(es.db.QueryRowContext(ctx, `SELECT `+resultColumns+` FROM game_results r
+	JOIN events e ON e.event_id=r.event_id WHERE r.event_id=$1`, eventID))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	return result, err
+}
+
+// GetSeasonRecord is the team's record over a season, the current one by default. A team without
+// a current season gets the record of all its results
+func (es *EventService) GetSeasonRecord(ctx context.Context, reqUserID uuid.UUID, teamID uuid.UUID, seasonID uuid.UUID) (*models.SeasonRecord, error) {
+	if err := es.requireTeamPermission(ctx, teamID, reqUserID, PermEventsView); err != nil {
+		return nil, err
+	}
+
+	season, seasonName, err := es.resolveSeason(ctx, teamID, seasonID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + resultColumns + ` FROM game_results r JOIN events e ON e.event_id=r.event_id
+	WHERE r.team_id=$1 AND e.status<>$2`
+	args := []any{teamID, models.StatusCancelled}
+	if season.Valid {
+		query += ` AND r.season_id=$3`
+		args = append(args, season.UUID)
+	}
+	query += ` ORDER BY e.start_time, r.event_id`
+
+	rows, err := es.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := []models.GameResult{}
+	for rows.Next() {
+		r, err := scanResult(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, *r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	record := seasonRecord(results)
+	record.TeamID = teamID
+	record.SeasonID = season
+	record.SeasonName = seasonName
+	if seasonName == "" && len(results) > 0 {
+		record.SeasonName = results[0].SeasonName
+	}
+	return record, nil
+}
+
+// resolveSeason is seasonID, or the team's current season when it is nil. The season is not valid
+// when the team has no current one, the name is only known for the current season
+func (es *EventService) resolveSeason(ctx context.Context, teamID uuid.UUID, seasonID uuid.UUID) (uuid.NullUUID, string, error) {
+	if seasonID != uuid.Nil {
+		return uuid.NullUUID{UUID: seasonID, Valid: true}, "", nil
+	}
+
+	current, err := es.teamClient.GetRosterOnDate(ctx, &team_proto.GetRosterOnDateRequest{TeamId: teamID.String()})
+	if err != nil {
+		return uuid.NullUUID{}, "", fmt.Errorf("cause of failure: %v", err)
+	}
+	if current.SeasonId == "" {
+		return uuid.NullUUID{}, "", nil
+	}
+	id, err := uuid.Parse(current.SeasonId)
+	if err != nil {
+		return uuid.NullUUID{}, "", err
+	}
+	return uuid.NullUUID{UUID: id, Valid: true}, current.SeasonName, nil
+}
+
+// seasonRecord totals results, which are in the order they were played
+func seasonRecord(results []models.GameResult) *models.SeasonRecord {
+	record := &models.SeasonRecord{Results: results}
+	var form strings.Builder
+	for i, r := range results {
+		record.Played++
+		switch r.Result {
+		case models.ResultWin:
+			record.Won++
+		case models.ResultDraw:
+			record.Drawn++
+		case models.ResultLoss:
+			record.Lost++
+		}
+		record.PointsFor += r.ScoreFor
+		record.PointsAgainst += r.ScoreAgainst
+		if i >= len(results)-formLength {
+			form.WriteString(r.Result)
+		}
+	}
+	record.PointDifference = record.PointsFor - record.PointsAgainst
+	record.Form = form.String()
+	return record
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/wycliff-ochieng/internal/models"
+)
+
+func intPtr(n int) *int { return &n }
+
+func TestScoreGame(t *testing.T) {
+	soccer := scoreFormats["soccer"]
+	//a level game won on penalties, the shootout is not part of the score
+	score, err := scoreGame(&soccer, &models.GameResultReq{Periods: []models.PeriodScore{
+		{Period: "1h", For: 1, Against: 0},
+		{Period: "2H", For: 0, Against: 1},
+		{Period: "PEN", For: 4, Against: 3},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if score.scoreFor != 1 || score.scoreAgainst != 1 || score.result != models.ResultWin || score.decidedBy != "PEN" {
+		t.Errorf("penalty win = %d-%d %s by %q", score.scoreFor, score.scoreAgainst, score.result, score.decidedBy)
+	}
+
+	volleyball := scoreFormats["volleyball"]
+	score, err = scoreGame(&volleyball, &models.GameResultReq{Periods: []models.PeriodScore{
+		{Period: "S1", For: 25, Against: 20},
+		{Period: "S2", For: 22, Against: 25},
+		{Period: "S3", For: 25, Against: 18},
+		{Period: "S4", For: 25, Against: 23},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if score.scoreFor != 3 || score.scoreAgainst != 1 || score.result != models.ResultWin {
+		t.Errorf("volleyball = %d-%d %s, want 3-1 W", score.scoreFor, score.scoreAgainst, score.result)
+	}
+
+	basketball := scoreFormats["basketball"]
+	if _, err := scoreGame(&basketball, &models.GameResultReq{Periods: []models.PeriodScore{
+		{Period: "Q1", For: 20, Against: 18}, {Period: "Q2", For: 20, Against: 22}, {Period: "Q3", For: 20, Against: 20},
+		{Period: "Q4", For: 20, Against: 20}, {Period: "OT1", For: 10, Against: 8},
+	}}); err != nil {
+		t.Errorf("overtime: %v", err)
+	}
+
+	invalid := map[string]*models.GameResultReq{
+		"no score":         {},
+		"score mismatch":   {ScoreFor: intPtr(3), Periods: []models.PeriodScore{{Period: "Q1", For: 2}}},
+		"unknown period":   {Periods: []models.PeriodScore{{Period: "1H", For: 2}}},
+		"out of order":     {Periods: []models.PeriodScore{{Period: "Q2", For: 2}, {Period: "Q1", For: 2}}},
+		"negative score":   {ScoreFor: intPtr(-1), ScoreAgainst: intPtr(0)},
+		"overtime dropped": {Periods: []models.PeriodScore{{Period: "OT", For: 2}, {Period: "OT1", For: 2}}},
+	}
+	for name, req := range invalid {
+		if _, err := scoreGame(&basketball, req); !errors.Is(err, ErrInvalidEvent) {
+			t.Errorf("%s: got %v, want ErrInvalidEvent", name, err)
+		}
+	}
+	if _, err := scoreGame(&soccer, &models.GameResultReq{Periods: []models.PeriodScore{
+		{Period: "1H", For: 2}, {Period: "PEN", For: 5, Against: 4},
+	}}); !errors.Is(err, ErrInvalidEvent) {
+		t.Errorf("shootout after a win: got %v, want ErrInvalidEvent", err)
+	}
+
+	//sports without a format take any period names
+	score, err = scoreGame(nil, &models.GameResultReq{Periods: []models.PeriodScore{{Period: "Innings 1", For: 180, Against: 181}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if score.result != models.ResultLoss {
+		t.Errorf("cricket result = %s, want L", score.result)
+	}
+}
+
+func TestSeasonRecord(t *testing.T) {
+	results := []models.GameResult{
+		{Result: models.ResultWin, ScoreFor: 2, ScoreAgainst: 0},
+		{Result: models.ResultLoss, ScoreFor: 1, ScoreAgainst: 3},
+		{Result: models.ResultDraw, ScoreFor: 1, ScoreAgainst: 1},
+		{Result: models.ResultWin, ScoreFor: 4, ScoreAgainst: 2},
+		{Result: models.ResultWin, ScoreFor: 1, ScoreAgainst: 0},
+		{Result: models.ResultLoss, ScoreFor: 0, ScoreAgainst: 1},
+	}
+	record := seasonRecord(results)
+	if record.Played != 6 || record.Won != 3 || record.Drawn != 1 || record.Lost != 2 {
+		t.Errorf("record = %d played %d-%d-%d", record.Played, record.Won, record.Drawn, record.Lost)
+	}
+	if record.PointsFor != 9 || record.PointsAgainst != 7 || record.PointDifference != 2 {
+		t.Errorf("points = %d-%d (%d)", record.PointsFor, record.PointsAgainst, record.PointDifference)
+	}
+	if record.Form != "LDWWL" {
+		t.Errorf("form = %q, want LDWWL", record.Form)
+	}
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wycliff-ochieng/common_packages/events"
+	"github.com/wycliff-ochieng/internal/models"
+)
+
+var (
+	ErrConflict      = errors.New("conflicts with the current state")
+	ErrRSVPClosed    = fmt.Errorf("%w: the RSVP deadline has passed", ErrConflict)
+	ErrRSVPLocked    = fmt.Errorf("%w: a coach has set this response", ErrConflict)
+	ErrEventCanceled = fmt.Errorf("%w: the event is cancelled", ErrConflict)
+)
+
+const maxReasonLength = 500
+
+// rsvpStatuses maps what clients send to the stored status
+var rsvpStatuses = map[string]string{
+	"going":     events.RSVPGoing,
+	"not_going": events.RSVPNotGoing,
+	"not going": events.RSVPNotGoing,
+	"maybe":     events.RSVPMaybe,
+}
+
+func utcOrNil(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
+}
+
+// rsvpDeadline is when members stop being able to answer
+func rsvpDeadline(e *models.Event) time.Time {
+	if e.RSVPDeadline != nil {
+		return *e.RSVPDeadline
+	}
+	return e.StartTime
+}
+
+func (es *EventService) attendanceSummary(ctx context.Context, q queryer, eventID uuid.UUID) (*models.AttendanceSummary, error) {
+	var s models.AttendanceSummary
+	err := q.QueryRowContext(ctx, `SELECT
+		COUNT(*) FILTER (WHERE event_status = $2),
+		COUNT(*) FILTER (WHERE event_status = $3),
+		COUNT(*) FILTER (WHERE event_status = $4),
+		COUNT(*) FILTER (WHERE event_status = $5),
+		COUNT(*)
+	FROM attendance WHERE event_id=$1`, eventID, events.RSVPGoing, events.RSVPNotGoing, events.RSVPMaybe, events.RSVPPending).Scan(
+		&s.Going,
+		&s.NotGoing,
+		&s.Maybe,
+		&s.Pending,
+		&s.Total,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// RespondToEvent records a member's RSVP. A member answers for themselves until the deadline and
+// cannot change a response a coach set; a coach with events.manage answers for anyone at any time
+func (es *EventService) RespondToEvent(ctx context.Context, reqUserID uuid.UUID, eventID uuid.UUID, req models.RSVPReq) (*models.RSVPResponse, error) {
+	es.l.Info("RSVP to event", "eventID", eventID)
+
+	status, ok := rsvpStatuses[strings.ToLower(strings.TrimSpace(req.Status))]
+	if !ok {
+		return nil, fmt.Errorf("%w: status must be going, not_going or maybe", ErrInvalidEvent)
+	}
+	reason := strings.TrimSpace(req.Reason)
+	if len([]rune(reason)) > maxReasonLength {
+		return nil, fmt.Errorf("%w: reason is longer than %d characters", ErrInvalidEvent, maxReasonLength)
+	}
+
+	event, err := es.GetEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if event.Status == models.StatusCancelled {
+		return nil, ErrEventCanceled
+	}
+
+	target := reqUserID
+	override := req.UserID != uuid.Nil && req.UserID != reqUserID
+	if override {
+		target = req.UserID
+		if err := es.requireTeamPermission(ctx, event.TeamID, reqUserID, PermEventsManage); err != nil {
+			return nil, err
+		}
+	} else if time.Now().After(rsvpDeadline(event)) {
+		return nil, ErrRSVPClosed
+	}
+
+	tx, err := es.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	//members who joined the team after the event was created have no row yet
+	if err := es.ensureRoster(ctx, tx, event, []uuid.UUID{target}); err != nil {
+		return nil, err
+	}
+
+	var previous string
+	var overridden bool
+	err = tx.QueryRowContext(ctx, `SELECT event_status,overridden FROM attendance WHERE event_id=$1 AND user_id=$2 FOR UPDATE`,
+		eventID, target).Scan(&previous, &overridden)
+	if err != nil {
+		return nil, err
+	}
+	if overridden && !override {
+		return nil, ErrRSVPLocked
+	}
+
+	var attendance models.Attendance
+	err = tx.QueryRowContext(ctx, `UPDATE attendance SET event_status=$1,reason=NULLIF($2,''),responded_at=NOW(),updated_by=$3,
+	overridden=$4,updated_at=NOW() WHERE event_id=$5 AND user_id=$6
+	RETURNING event_id,team_id,user_id,event_status,updated_at,COALESCE(reason,''),responded_at,updated_by,overridden`,
+		status, reason, reqUserID, override, eventID, target).Scan(
+		&attendance.EventID,
+		&attendance.TeamID,
+		&attendance.UserID,
+		&attendance.Status,
+		&attendance.UpdateteAt,
+		&attendance.Reason,
+		&attendance.RespondedAt,
+		&attendance.UpdatedBy,
+		&attendance.Overridden,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("issue updating attendance: %w", err)
+	}
+
+	summary, err := es.attendanceSummary(ctx, tx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	updated := &events.AttendanceUpdated{
+		EventID:        eventID,
+		TeamID:         event.TeamID,
+		UserID:         target,
+		Status:         status,
+		PreviousStatus: previous,
+		Reason:         reason,
+		UpdatedBy:      reqUserID,
+		Override:       override,
+		Going:          summary.Going,
+		NotGoing:       summary.NotGoing,
+		Maybe:          summary.Maybe,
+		Pending:        summary.Pending,
+	}
+	if err := es.prod.PublishEventUpdate(ctx, updated); err != nil {
+		log.Printf("kafka error publishing %s: %s", events.TypeAttendanceUpdated, err)
+	}
+
+	return &models.RSVPResponse{Attendance: attendance, Summary: *summary}, nil
+}
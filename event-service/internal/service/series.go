@@ -0,0 +1,810 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wycliff-ochieng/common_packages/events"
+	"github.com/wycliff-ochieng/common_packages/team_grpc/team_proto"
+	"github.com/wycliff-ochieng/internal/models"
+	"github.com/wycliff-ochieng/internal/recurrence"
+)
+
+// edit scopes of a recurring event
+const (
+	ScopeThis      = "this"
+	ScopeFollowing = "following"
+	ScopeAll       = "all"
+)
+
+var ErrNotRecurring = errors.New("event is not part of a recurring series")
+
+const dateFormat = "2006-01-02"
+
+// eventColumns is what every events query returns, in the order eventFields scans it
+const eventColumns = `event_id,team_id,created_by,COALESCE(event_title,''),COALESCE(event_type,''),COALESCE(location,''),
+	COALESCE(notes,''),start_time,end_time,created_at,updated_at,status,series_id,
+	COALESCE(to_char(occurrence_date,'YYYY-MM-DD'),''),is_exception,sequence,rsvp_deadline,venue_id,resource_id`
+
+func eventFields(e *models.Event) []any {
+	return []any{
+		&e.ID,
+		&e.TeamID,
+		&e.CreatedBy,
+		&e.Title,
+		&e.EventType,
+		&e.Location,
+		&e.Notes,
+		&e.StartTime,
+		&e.EndTime,
+		&e.CreatedAt,
+		&e.UpdatedAt,
+		&e.Status,
+		&e.SeriesID,
+		&e.OccurrenceDate,
+		&e.IsException,
+		&e.Sequence,
+		&e.RSVPDeadline,
+		&e.VenueID,
+		&e.ResourceID,
+	}
+}
+
+const seriesColumns = `series_id,team_id,created_by,COALESCE(event_title,''),COALESCE(event_type,''),COALESCE(location,''),
+	COALESCE(notes,''),rrule,time_zone,dtstart,duration_minutes,materialized_until,created_at,updated_at,venue_id,resource_id`
+
+// queryer is satisfied by both the database and a transaction
+type queryer interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+func validateTimes(start time.Time, end time.Time) error {
+	if start.IsZero() || end.IsZero() {
+		return fmt.Errorf("%w: start and end time are required", ErrInvalidEvent)
+	}
+	if !end.After(start) {
+		return fmt.Errorf("%w: end time must be after the start time", ErrInvalidEvent)
+	}
+	return nil
+}
+
+// wallClock stores the clock reading of t as a zone less TIMESTAMP
+func wallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+}
+
+// inLocation reads a zone less TIMESTAMP back as a clock reading in loc
+func inLocation(w time.Time, loc *time.Location) time.Time {
+	return time.Date(w.Year(), w.Month(), w.Day(), w.Hour(), w.Minute(), w.Second(), 0, loc)
+}
+
+// seriesRule parses the stored rule of a series and returns it with the first start in the
+// series time zone
+func seriesRule(series *models.EventSeries) (*recurrence.Rule, time.Time, error) {
+	loc, err := time.LoadLocation(series.TimeZone)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("series %s has an unknown time zone: %w", series.SeriesID, err)
+	}
+	rule, err := recurrence.Parse(series.RRule, loc)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return rule, series.StartTime.In(loc), nil
+}
+
+// teamMemberIDs fetches the roster an occurrence's attendance list starts from
+func (es *EventService) teamMemberIDs(ctx context.Context, teamID uuid.UUID) ([]uuid.UUID, error) {
+	res, err := es.teamClient.GetTeamSummary(ctx, &team_proto.GetTeamSummaryRequest{TeamId: teamID.String()})
+	if err != nil {
+		es.l.Error("gRPC call to team service failed", "error", err)
+		return nil, fmt.Errorf("cause of failure: %v", err)
+	}
+
+	ids := make([]uuid.UUID, 0, len(res.Members))
+	for _, member := range res.Members {
+		id, err := uuid.Parse(member.UserId)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// CreateRecurringEvent stores the series and the occurrences up to the horizon, each with its own
+// attendance list
+func (es *EventService) CreateRecurringEvent(ctx context.Context, reqUserID uuid.UUID, req models.CreateEventReq) (*models.EventSeries, error) {
+	es.l.Info("Creation of recurring event initiated by user", "teamID", req.TeamID)
+
+	if err := es.requireTeamPermission(ctx, req.TeamID, reqUserID, PermEventsCreate); err != nil {
+		es.l.Warn("user is not allowed to create events for this team", "error", err)
+		return nil, err
+	}
+
+	if err := es.allowForce(ctx, req.TeamID, reqUserID, req.Force); err != nil {
+		return nil, err
+	}
+
+	if err := validateTimes(req.StartTime, req.EndTime); err != nil {
+		return nil, err
+	}
+
+	timeZone := req.TimeZone
+	if timeZone == "" {
+		timeZone = "UTC"
+	}
+	loc, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unknown time zone %q", ErrInvalidEvent, timeZone)
+	}
+
+	rule, err := recurrence.Parse(req.RRule, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	dtstart := req.StartTime.In(loc)
+	if !rule.Until.IsZero() && rule.Until.Before(dtstart) {
+		return nil, fmt.Errorf("%w: UNTIL is before the first occurrence", recurrence.ErrInvalidRule)
+	}
+
+	members, err := es.teamMemberIDs(ctx, req.TeamID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := es.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	venue, err := es.resolveVenue(ctx, tx, req.VenueID, req.ResourceID, req.Location)
+	if err != nil {
+		return nil, err
+	}
+
+	horizon := time.Now().Add(es.horizon)
+
+	query := `INSERT INTO event_series(team_id,created_by,event_title,event_type,location,notes,rrule,time_zone,dtstart,duration_minutes,materialized_until,
+	venue_id,resource_id)
+	VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13) RETURNING ` + seriesColumns
+
+	series, err := scanSeries(tx.QueryRowContext(ctx, query, req.TeamID, reqUserID, req.Name, req.EventType, venue.location, req.Notes,
+		rule.String(), timeZone, wallClock(dtstart), int(req.EndTime.Sub(req.StartTime).Minutes()), horizon.UTC(), venue.venueID, venue.resourceID))
+	if err != nil {
+		return nil, fmt.Errorf("issue inserting event series: %w", err)
+	}
+
+	occurrences, err := es.insertOccurrences(ctx, tx, series, rule.Occurrences(dtstart, dtstart, horizon), members)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := es.checkConflicts(ctx, tx, req.Force, eventIDs(occurrences)); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	es.l.Info("recurring event created", "seriesID", series.SeriesID, "occurrences", len(occurrences))
+
+	//one entry per series in the activity feed, not one per occurrence
+	if len(occurrences) > 0 {
+		es.notifyCreated(ctx, &occurrences[0])
+	}
+
+	series.Occurrences = occurrences
+	return series, nil
+}
+
+func scanSeries(row *sql.Row) (*models.EventSeries, error) {
+	var s models.EventSeries
+	var dtstart time.Time
+	err := row.Scan(
+		&s.SeriesID,
+		&s.TeamID,
+		&s.CreatedBy,
+		&s.Title,
+		&s.EventType,
+		&s.Location,
+		&s.Notes,
+		&s.RRule,
+		&s.TimeZone,
+		&dtstart,
+		&s.DurationMinutes,
+		&s.MaterializedUntil,
+		&s.CreatedAt,
+		&s.UpdatedAt,
+		&s.VenueID,
+		&s.ResourceID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	loc, err := time.LoadLocation(s.TimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("series %s has an unknown time zone: %w", s.SeriesID, err)
+	}
+	s.StartTime = inLocation(dtstart, loc)
+	return &s, nil
+}
+
+func (es *EventService) getSeries(ctx context.Context, q queryer, seriesID uuid.UUID) (*models.EventSeries, error) {
+	series, err := scanSeries(q.QueryRowContext(ctx, `SELECT `+seriesColumns+` FROM event_series WHERE series_id=$1 FOR UPDATE`, seriesID))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	return series, err
+}
+
+// insertOccurrences adds the occurrences starting at starts with the series details and an
+// attendance list of members, dates that already have an occurrence are left alone
+func (es *EventService) insertOccurrences(ctx context.Context, tx *sql.Tx, series *models.EventSeries, starts []time.Time, members []uuid.UUID) ([]models.Event, error) {
+	query := `INSERT INTO events(team_id,created_by,event_title,event_type,location,notes,start_time,end_time,series_id,occurrence_date,venue_id,resource_id)
+	VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12)
+	ON CONFLICT (series_id, occurrence_date) DO NOTHING
+	RETURNING ` + eventColumns
+
+	duration := time.Duration(series.DurationMinutes) * time.Minute
+
+	var created []models.Event
+	for _, start := range starts {
+		var event models.Event
+		err := tx.QueryRowContext(ctx, query, series.TeamID, series.CreatedBy, series.Title, series.EventType, series.Location, series.Notes,
+			start.UTC(), start.Add(duration).UTC(), series.SeriesID, start.Format(dateFormat), series.VenueID, series.ResourceID).Scan(eventFields(&event)...)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("issue inserting occurrence: %w", err)
+		}
+
+		records := make([]models.Attendance, 0, len(members))
+		for _, memberID := range members {
+			records = append(records, models.Attendance{
+				EventID:    event.ID,
+				UserID:     memberID,
+				TeamID:     series.TeamID,
+				Status:     events.RSVPPending,
+				UpdateteAt: time.Now(),
+			})
+		}
+		if err := es.CreateBulkAttendance(ctx, tx, records); err != nil {
+			return nil, err
+		}
+
+		created = append(created, event)
+	}
+	return created, nil
+}
+
+// UpdateEventSeries edits the occurrence's series, either from this occurrence on (the series is
+// split in two) or as a whole. Occurrences that already happened and occurrences edited on their own
+// keep their details; occurrences the new rule no longer produces are cancelled rather than deleted
+// so their attendance survives
+func (es *EventService) UpdateEventSeries(ctx context.Context, reqUserID uuid.UUID, eventID uuid.UUID, scope string, toUpdate models.UpdateEventReq) (*models.EventSeries, error) {
+	es.l.Info("PUT operation on a recurring event", "scope", scope)
+
+	if scope != ScopeFollowing && scope != ScopeAll {
+		return nil, fmt.Errorf("%w: unknown scope %q", ErrInvalidEvent, scope)
+	}
+
+	event, err := es.GetEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if !event.SeriesID.Valid {
+		return nil, ErrNotRecurring
+	}
+
+	if err := es.requireTeamPermission(ctx, event.TeamID, reqUserID, PermEventsManage); err != nil {
+		es.l.Error("User NOT allowed to update Event details")
+		return nil, err
+	}
+
+	if err := es.allowForce(ctx, event.TeamID, reqUserID, toUpdate.Force); err != nil {
+		return nil, err
+	}
+
+	if err := validateTimes(toUpdate.StartTime, toUpdate.EndTime); err != nil {
+		return nil, err
+	}
+
+	members, err := es.teamMemberIDs(ctx, event.TeamID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := es.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	series, err := es.getSeries(ctx, tx, event.SeriesID.UUID)
+	if err != nil {
+		return nil, err
+	}
+
+	rule, dtstart, err := seriesRule(series)
+	if err != nil {
+		return nil, err
+	}
+
+	timeZone := series.TimeZone
+	if toUpdate.TimeZone != "" {
+		timeZone = toUpdate.TimeZone
+	}
+	loc, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unknown time zone %q", ErrInvalidEvent, timeZone)
+	}
+
+	newRule := *rule
+	if toUpdate.RRule != "" {
+		parsed, err := recurrence.Parse(toUpdate.RRule, loc)
+		if err != nil {
+			return nil, err
+		}
+		newRule = *parsed
+	}
+
+	pivot, err := time.ParseInLocation(dateFormat, event.OccurrenceDate, dtstart.Location())
+	if err != nil {
+		return nil, fmt.Errorf("occurrence %s has no date: %w", event.ID, err)
+	}
+
+	//the series keeps its first date, only the clock time and length follow the edit
+	clock := toUpdate.StartTime.In(loc)
+	firstDate := dtstart
+	from := time.Now().In(loc)
+
+	if scope == ScopeFollowing {
+		from = pivot
+		if pivot.After(dtstart) {
+			series, err = es.splitSeries(ctx, tx, series, rule, dtstart, pivot, toUpdate.RRule == "", &newRule)
+			if err != nil {
+				return nil, err
+			}
+			firstDate = pivot
+		}
+	}
+
+	newStart := time.Date(firstDate.Year(), firstDate.Month(), firstDate.Day(), clock.Hour(), clock.Minute(), clock.Second(), 0, loc)
+
+	venue, err := es.resolveVenue(ctx, tx, toUpdate.VenueID, toUpdate.ResourceID, toUpdate.Location)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `UPDATE event_series SET event_title=$1,location=$2,notes=$3,rrule=$4,time_zone=$5,dtstart=$6,duration_minutes=$7,
+	venue_id=$8,resource_id=$9,updated_at=NOW() WHERE series_id=$10 RETURNING ` + seriesColumns
+
+	series, err = scanSeries(tx.QueryRowContext(ctx, query, toUpdate.Title, venue.location, toUpdate.Notes, newRule.String(), timeZone,
+		wallClock(newStart), int(toUpdate.EndTime.Sub(toUpdate.StartTime).Minutes()), venue.venueID, venue.resourceID, series.SeriesID))
+	if err != nil {
+		return nil, fmt.Errorf("issue updating event series: %w", err)
+	}
+
+	occurrences, err := es.resyncOccurrences(ctx, tx, series, from, members)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := es.checkConflicts(ctx, tx, toUpdate.Force, eventIDs(occurrences)); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	series.Occurrences = occurrences
+	return series, nil
+}
+
+// splitSeries ends series the day before pivot and moves the occurrences from pivot on to a new
+// series, which is returned. A COUNT rule hands the occurrences it has left to the new series
+// when keepRule is set
+func (es *EventService) splitSeries(ctx context.Context, tx *sql.Tx, series *models.EventSeries, rule *recurrence.Rule, dtstart time.Time, pivot time.Time, keepRule bool, newRule *recurrence.Rule) (*models.EventSeries, error) {
+	pivotStart := time.Date(pivot.Year(), pivot.Month(), pivot.Day(), dtstart.Hour(), dtstart.Minute(), dtstart.Second(), 0, dtstart.Location())
+
+	if keepRule && rule.Count > 0 {
+		newRule.Count = rule.Count - rule.Before(dtstart, pivotStart)
+		if newRule.Count < 1 {
+			newRule.Count = 1
+		}
+	}
+
+	ended := *rule
+	ended.Count = 0
+	ended.Until = pivotStart.Add(-time.Second)
+
+	if _, err := tx.ExecContext(ctx, `UPDATE event_series SET rrule=$1,updated_at=NOW() WHERE series_id=$2`, ended.String(), series.SeriesID); err != nil {
+		return nil, fmt.Errorf("issue ending event series: %w", err)
+	}
+
+	query := `INSERT INTO event_series(team_id,created_by,event_title,event_type,location,notes,rrule,time_zone,dtstart,duration_minutes,materialized_until,
+	venue_id,resource_id)
+	SELECT team_id,created_by,event_title,event_type,location,notes,rrule,time_zone,dtstart,duration_minutes,materialized_until,venue_id,resource_id
+	FROM event_series WHERE series_id=$1 RETURNING ` + seriesColumns
+
+	following, err := scanSeries(tx.QueryRowContext(ctx, query, series.SeriesID))
+	if err != nil {
+		return nil, fmt.Errorf("issue splitting event series: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE events SET series_id=$1 WHERE series_id=$2 AND occurrence_date >= $3`,
+		following.SeriesID, series.SeriesID, pivot.Format(dateFormat))
+	if err != nil {
+		return nil, fmt.Errorf("issue moving occurrences to the new series: %w", err)
+	}
+
+	return following, nil
+}
+
+type storedOccurrence struct {
+	id          uuid.UUID
+	date        string
+	isException bool
+}
+
+// resyncOccurrences brings the occurrences of series dated from `from` on in line with its rule and
+// details and returns them
+func (es *EventService) resyncOccurrences(ctx context.Context, tx *sql.Tx, series *models.EventSeries, from time.Time, members []uuid.UUID) ([]models.Event, error) {
+	rule, dtstart, err := seriesRule(series)
+	if err != nil {
+		return nil, err
+	}
+
+	fromDate := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, dtstart.Location())
+
+	wanted := map[string]time.Time{}
+	for _, start := range rule.Occurrences(dtstart, fromDate, series.MaterializedUntil) {
+		wanted[start.Format(dateFormat)] = start
+	}
+
+	rows, err := tx.QueryContext(ctx, `SELECT event_id,to_char(occurrence_date,'YYYY-MM-DD'),is_exception FROM events
+	WHERE series_id=$1 AND occurrence_date >= $2`, series.SeriesID, fromDate.Format(dateFormat))
+	if err != nil {
+		return nil, err
+	}
+
+	var stored []storedOccurrence
+	for rows.Next() {
+		var o storedOccurrence
+		if err := rows.Scan(&o.id, &o.date, &o.isException); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		stored = append(stored, o)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	duration := time.Duration(series.DurationMinutes) * time.Minute
+
+	for _, o := range stored {
+		start, ok := wanted[o.date]
+		delete(wanted, o.date)
+
+		if o.isException {
+			continue
+		}
+
+		if !ok {
+			_, err = tx.ExecContext(ctx, `UPDATE events SET sequence=sequence+1,status=$1,updated_at=NOW() WHERE event_id=$2 AND status<>$1`, models.StatusCancelled, o.id)
+		} else {
+			_, err = tx.ExecContext(ctx, `UPDATE events SET sequence=sequence+1,event_title=$1,location=$2,notes=$3,start_time=$4,end_time=$5,status=$6,
+			venue_id=$7,resource_id=$8,updated_at=NOW() WHERE event_id=$9`, series.Title, series.Location, series.Notes, start.UTC(), start.Add(duration).UTC(),
+				models.StatusScheduled, series.VenueID, series.ResourceID, o.id)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("issue updating occurrence %s: %w", o.id, err)
+		}
+	}
+
+	var missing []time.Time
+	for _, start := range wanted {
+		missing = append(missing, start)
+	}
+	if _, err := es.insertOccurrences(ctx, tx, series, missing, members); err != nil {
+		return nil, err
+	}
+
+	return es.listOccurrences(ctx, tx, series.SeriesID, fromDate)
+}
+
+func eventIDs(list []models.Event) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(list))
+	for _, e := range list {
+		ids = append(ids, e.ID)
+	}
+	return ids
+}
+
+func (es *EventService) listOccurrences(ctx context.Context, q queryer, seriesID uuid.UUID, from time.Time) ([]models.Event, error) {
+	rows, err := q.QueryContext(ctx, `SELECT `+eventColumns+` FROM events WHERE series_id=$1 AND occurrence_date >= $2 ORDER BY start_time`,
+		seriesID, from.Format(dateFormat))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var occurrences []models.Event
+	for rows.Next() {
+		var event models.Event
+		if err := rows.Scan(eventFields(&event)...); err != nil {
+			return nil, err
+		}
+		occurrences = append(occurrences, event)
+	}
+	return occurrences, rows.Err()
+}
+
+// CancelOccurrence cancels one event, on a recurring event the rest of the series is untouched.
+// Attendees are told through EventCancelled, cancelling again changes nothing and sends nothing
+func (es *EventService) CancelOccurrence(ctx context.Context, reqUserID uuid.UUID, eventID uuid.UUID, reason string) (*models.Event, error) {
+	reason = strings.TrimSpace(reason)
+	if len([]rune(reason)) > maxReasonLength {
+		return nil, fmt.Errorf("%w: reason is longer than %d characters", ErrInvalidEvent, maxReasonLength)
+	}
+
+	event, err := es.GetEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := es.requireTeamPermission(ctx, event.TeamID, reqUserID, PermEventsManage); err != nil {
+		return nil, err
+	}
+
+	if event.Status == models.StatusCancelled {
+		return event, nil
+	}
+
+	var cancelled models.Event
+	query := `UPDATE events SET sequence=sequence+1,status=$1,is_exception = series_id IS NOT NULL,updated_at=NOW() WHERE event_id=$2 RETURNING ` + eventColumns
+	if err := es.db.QueryRowContext(ctx, query, models.StatusCancelled, eventID).Scan(eventFields(&cancelled)...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	es.notifyCancelled(ctx, &cancelled, reqUserID, reason)
+	return &cancelled, nil
+}
+
+// CancelTeamEvents cancels the team's scheduled events that have not started and ends its recurring
+// series, for a team that was archived or deleted. Attendees are told through EventCancelled unless
+// cancelledBy is empty. Running it again finds nothing left to cancel
+func (es *EventService) CancelTeamEvents(ctx context.Context, teamID uuid.UUID, cancelledBy uuid.UUID, reason string) error {
+	tx, err := es.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `SELECT series_id FROM event_series WHERE team_id=$1 FOR UPDATE`, teamID)
+	if err != nil {
+		return fmt.Errorf("issue loading event series of team: %w", err)
+	}
+	var seriesIDs []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return err
+		}
+		seriesIDs = append(seriesIDs, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	//end every series now, or the materializer keeps adding occurrences for the team
+	now := time.Now()
+	for _, seriesID := range seriesIDs {
+		series, err := es.getSeries(ctx, tx, seriesID)
+		if err != nil {
+			return err
+		}
+		rule, _, err := seriesRule(series)
+		if err != nil {
+			return err
+		}
+		if !rule.Until.IsZero() && rule.Until.Before(now) {
+			continue
+		}
+
+		ended := *rule
+		ended.Count = 0
+		ended.Until = now
+		if _, err := tx.ExecContext(ctx, `UPDATE event_series SET rrule=$1,updated_at=NOW() WHERE series_id=$2`, ended.String(), seriesID); err != nil {
+			return fmt.Errorf("issue ending event series: %w", err)
+		}
+	}
+
+	query := `UPDATE events SET sequence=sequence+1,status=$1,is_exception = series_id IS NOT NULL,updated_at=NOW()
+	WHERE team_id=$2 AND status=$3 AND start_time > NOW() RETURNING ` + eventColumns
+	rows, err = tx.QueryContext(ctx, query, models.StatusCancelled, teamID, models.StatusScheduled)
+	if err != nil {
+		return fmt.Errorf("issue cancelling events of team: %w", err)
+	}
+	var cancelled []models.Event
+	for rows.Next() {
+		var event models.Event
+		if err := rows.Scan(eventFields(&event)...); err != nil {
+			rows.Close()
+			return err
+		}
+		cancelled = append(cancelled, event)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	if cancelledBy == uuid.Nil {
+		return nil
+	}
+	for i := range cancelled {
+		es.notifyCancelled(ctx, &cancelled[i], cancelledBy, reason)
+	}
+	return nil
+}
+
+// RemoveTeamAttendee drops a member who left the team from the attendance of its scheduled events
+// that have not started, past events keep their attendance and check-ins
+func (es *EventService) RemoveTeamAttendee(ctx context.Context, teamID uuid.UUID, userID uuid.UUID) error {
+	query := `DELETE FROM attendance a USING events e
+	WHERE e.event_id = a.event_id AND e.team_id=$1 AND a.user_id=$2 AND e.status=$3 AND e.start_time > NOW()`
+	res, err := es.db.ExecContext(ctx, query, teamID, userID, models.StatusScheduled)
+	if err != nil {
+		return fmt.Errorf("issue removing attendance of former member: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n > 0 {
+		es.l.Info("removed former member from upcoming events", "teamID", teamID, "userID", userID, "events", n)
+	}
+	return nil
+}
+
+// notifyCancelled publishes EventCancelled to everyone who had not declined. Like every publish it
+// is best effort, the event stays cancelled when Kafka is down
+func (es *EventService) notifyCancelled(ctx context.Context, event *models.Event, cancelledBy uuid.UUID, reason string) {
+	rows, err := es.db.QueryContext(ctx, `SELECT user_id FROM attendance WHERE event_id=$1 AND event_status <> $2`, event.ID, events.RSVPNotGoing)
+	if err != nil {
+		es.l.Error("failed to load attendees of cancelled event", "eventID", event.ID, "error", err)
+		return
+	}
+	defer rows.Close()
+
+	attendees := []uuid.UUID{}
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			es.l.Error("failed to load attendees of cancelled event", "eventID", event.ID, "error", err)
+			return
+		}
+		attendees = append(attendees, id)
+	}
+	if err := rows.Err(); err != nil {
+		es.l.Error("failed to load attendees of cancelled event", "eventID", event.ID, "error", err)
+		return
+	}
+
+	cancelled := &events.EventCancelled{
+		EventID:     event.ID,
+		TeamID:      event.TeamID,
+		Title:       event.Title,
+		StartTime:   event.StartTime,
+		CancelledBy: cancelledBy,
+		Reason:      reason,
+		Attendees:   attendees,
+	}
+	if err := es.prod.PublishEventUpdate(ctx, cancelled); err != nil {
+		log.Printf("kafka error publishing %s: %s", events.TypeEventCancelled, err)
+	}
+}
+
+// MaterializeSeries extends every series whose occurrences stop short of the horizon
+func (es *EventService) MaterializeSeries(ctx context.Context) error {
+	horizon := time.Now().Add(es.horizon)
+
+	rows, err := es.db.QueryContext(ctx, `SELECT series_id FROM event_series WHERE materialized_until < $1`, horizon.UTC())
+	if err != nil {
+		return err
+	}
+	var due []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return err
+		}
+		due = append(due, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, seriesID := range due {
+		if err := es.materialize(ctx, seriesID, horizon); err != nil {
+			es.l.Error("failed to materialize event series", "seriesID", seriesID, "error", err)
+		}
+	}
+	return nil
+}
+
+func (es *EventService) materialize(ctx context.Context, seriesID uuid.UUID, horizon time.Time) error {
+	tx, err := es.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	series, err := es.getSeries(ctx, tx, seriesID)
+	if err != nil {
+		return err
+	}
+
+	rule, dtstart, err := seriesRule(series)
+	if err != nil {
+		return err
+	}
+
+	starts := rule.Occurrences(dtstart, series.MaterializedUntil, horizon)
+	if len(starts) > 0 {
+		members, err := es.teamMemberIDs(ctx, series.TeamID)
+		if err != nil {
+			return err
+		}
+		if _, err := es.insertOccurrences(ctx, tx, series, starts, members); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE event_series SET materialized_until=$1 WHERE series_id=$2`, horizon.UTC(), seriesID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// RunSeriesMaterializer keeps the occurrences of every series materialized up to the horizon
+// until ctx is cancelled
+func (es *EventService) RunSeriesMaterializer(ctx context.Context, every time.Duration) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+
+	for {
+		if err := es.MaterializeSeries(ctx); err != nil {
+			es.l.Error("event series materializer run failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,595 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/wycliff-ochieng/common_packages/team_grpc/team_proto"
+	"github.com/wycliff-ochieng/internal/models"
+	"github.com/wycliff-ochieng/sports-common-package/user_grpc/user_proto"
+)
+
+const (
+	maxStatNameLength   = 60
+	maxStatPlayers      = 100
+	defaultLeadersLimit = 10
+	maxLeadersLimit     = 100
+)
+
+var statKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,29}$`)
+
+// statRow is a player's value of a stat in one game
+type statRow struct {
+	eventID uuid.UUID
+	userID  uuid.UUID
+	key     string
+	value   int
+}
+
+// validateStatDefinition normalises the key and name of a team's own stat
+func validateStatDefinition(key string, req *models.StatDefinitionReq) (string, error) {
+	key = strings.ToLower(strings.TrimSpace(key))
+	if !statKeyPattern.MatchString(key) {
+		return "", fmt.Errorf("%w: stat key must be up to 30 lowercase letters, digits or underscores starting with a letter", ErrInvalidEvent)
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return "", fmt.Errorf("%w: stat name is required", ErrInvalidEvent)
+	}
+	if len([]rune(req.Name)) > maxStatNameLength {
+		return "", fmt.Errorf("%w: stat name is longer than %d characters", ErrInvalidEvent, maxStatNameLength)
+	}
+	if req.MaxValue != nil && *req.MaxValue < req.MinValue {
+		return "", fmt.Errorf("%w: MaxValue is below MinValue", ErrInvalidEvent)
+	}
+	return key, nil
+}
+
+// validateGameStats checks every player is listed once and every value is a defined stat within its
+// bounds. Players with no stats are kept, their stats for the game are removed
+func validateGameStats(defs []models.StatDefinition, req *models.RecordGameStatsReq) error {
+	if len(req.Players) == 0 {
+		return fmt.Errorf("%w: at least one player is required", ErrInvalidEvent)
+	}
+	if len(req.Players) > maxStatPlayers {
+		return fmt.Errorf("%w: at most %d players per request", ErrInvalidEvent, maxStatPlayers)
+	}
+
+	byKey := make(map[string]models.StatDefinition, len(defs))
+	for _, d := range defs {
+		byKey[d.Key] = d
+	}
+
+	seen := make(map[uuid.UUID]bool, len(req.Players))
+	for _, p := range req.Players {
+		if p.UserID == uuid.Nil {
+			return fmt.Errorf("%w: UserID is required", ErrInvalidEvent)
+		}
+		if seen[p.UserID] {
+			return fmt.Errorf("%w: user %s is listed more than once", ErrInvalidEvent, p.UserID)
+		}
+		seen[p.UserID] = true
+
+		for key, value := range p.Stats {
+			def, ok := byKey[key]
+			if !ok {
+				return fmt.Errorf("%w: %q is not a stat of this team", ErrInvalidEvent, key)
+			}
+			if value < def.MinValue || (def.MaxValue != nil && value > *def.MaxValue) {
+				return fmt.Errorf("%w: %s of user %s is out of range", ErrInvalidEvent, def.Name, p.UserID)
+			}
+		}
+	}
+	return nil
+}
+
+// teamSport is the sport whose default stats apply to the team
+func (es *EventService) teamSport(ctx context.Context, teamID uuid.UUID) (string, error) {
+	team, err := es.teamClient.GetTeamSummary(ctx, &team_proto.GetTeamSummaryRequest{TeamId: teamID.String()})
+	if err != nil {
+		return "", fmt.Errorf("cause of failure: %v", err)
+	}
+	return strings.ToLower(strings.TrimSpace(team.Sport)), nil
+}
+
+// statDefinitions are the defaults of the team's sport and its own stats, a team stat replaces the
+// default with the same key
+func (es *EventService) statDefinitions(ctx context.Context, teamID uuid.UUID) ([]models.StatDefinition, error) {
+	sport, err := es.teamSport(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := es.db.QueryContext(ctx, `SELECT stat_key,name,min_value,max_value,sort_order,team_id FROM (
+		SELECT DISTINCT ON (stat_key) stat_key,name,min_value,max_value,sort_order,team_id FROM stat_definitions
+		WHERE (team_id IS NULL AND sport=$1) OR team_id=$2
+		ORDER BY stat_key, team_id NULLS LAST
+	) d ORDER BY sort_order, stat_key`, sport, teamID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	defs := []models.StatDefinition{}
+	for rows.Next() {
+		var d models.StatDefinition
+		var maxValue sql.NullInt64
+		if err := rows.Scan(&d.Key, &d.Name, &d.MinValue, &maxValue, &d.SortOrder, &d.TeamID); err != nil {
+			return nil, err
+		}
+		if maxValue.Valid {
+			v := int(maxValue.Int64)
+			d.MaxValue = &v
+		}
+		defs = append(defs, d)
+	}
+	return defs, rows.Err()
+}
+
+// GetStatDefinitions is what the team tracks per player and game
+func (es *EventService) GetStatDefinitions(ctx context.Context, reqUserID uuid.UUID, teamID uuid.UUID) ([]models.StatDefinition, error) {
+	if err := es.requireTeamPermission(ctx, teamID, reqUserID, PermEventsView); err != nil {
+		return nil, err
+	}
+	return es.statDefinitions(ctx, teamID)
+}
+
+// SetStatDefinition adds a stat to the team or replaces the sport default with the same key
+func (es *EventService) SetStatDefinition(ctx context.Context, reqUserID uuid.UUID, teamID uuid.UUID, key string, req models.StatDefinitionReq) ([]models.StatDefinition, error) {
+	es.l.Info("setting stat definition", "teamID", teamID, "key", key)
+
+	if err := es.requireTeamPermission(ctx, teamID, reqUserID, PermEventsManage); err != nil {
+		return nil, err
+	}
+
+	key, err := validateStatDefinition(key, &req)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = es.db.ExecContext(ctx, `INSERT INTO stat_definitions(team_id,stat_key,name,min_value,max_value,sort_order)
+	VALUES($1,$2,$3,$4,$5,$6)
+	ON CONFLICT (team_id, stat_key) WHERE team_id IS NOT NULL DO UPDATE SET name=EXCLUDED.name,
+	min_value=EXCLUDED.min_value,max_value=EXCLUDED.max_value,sort_order=EXCLUDED.sort_order`,
+		teamID, key, req.Name, req.MinValue, req.MaxValue, req.SortOrder)
+	if err != nil {
+		return nil, fmt.Errorf("issue saving stat definition: %w", err)
+	}
+
+	return es.statDefinitions(ctx, teamID)
+}
+
+// DeleteStatDefinition removes one of the team's own stats, a sport default it replaced applies
+// again. Stats already recorded under the key are kept
+func (es *EventService) DeleteStatDefinition(ctx context.Context, reqUserID uuid.UUID, teamID uuid.UUID, key string) error {
+	es.l.Info("deleting stat definition", "teamID", teamID, "key", key)
+
+	if err := es.requireTeamPermission(ctx, teamID, reqUserID, PermEventsManage); err != nil {
+		return err
+	}
+
+	res, err := es.db.ExecContext(ctx, `DELETE FROM stat_definitions WHERE team_id=$1 AND stat_key=$2`,
+		teamID, strings.ToLower(strings.TrimSpace(key)))
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// RecordGameStats replaces the stats of the listed players for a game that has started. Players
+// must be on the team's roster for the season the game falls in, or on the team when there is none
+func (es *EventService) RecordGameStats(ctx context.Context, reqUserID uuid.UUID, eventID uuid.UUID, req models.RecordGameStatsReq) (*models.GameStats, error) {
+	es.l.Info("recording game stats", "eventID", eventID, "players", len(req.Players))
+
+	event, err := es.GetEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := es.requireTeamPermission(ctx, event.TeamID, reqUserID, PermEventsManage); err != nil {
+		return nil, err
+	}
+
+	if !strings.EqualFold(event.EventType, models.EventTypeGame) {
+		return nil, fmt.Errorf("%w: stats are only recorded for games", ErrInvalidEvent)
+	}
+	if event.Status == models.StatusCancelled {
+		return nil, ErrEventCanceled
+	}
+	if event.StartTime.After(time.Now()) {
+		return nil, ErrGameNotStarted
+	}
+
+	defs, err := es.statDefinitions(ctx, event.TeamID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateGameStats(defs, &req); err != nil {
+		return nil, err
+	}
+
+	season, err := es.teamClient.GetRosterOnDate(ctx, &team_proto.GetRosterOnDateRequest{
+		TeamId: event.TeamID.String(),
+		Date:   event.StartTime.UTC().Format(dateFormat),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cause of failure: %v", err)
+	}
+	var seasonID uuid.NullUUID
+	if season.SeasonId != "" {
+		id, err := uuid.Parse(season.SeasonId)
+		if err != nil {
+			return nil, err
+		}
+		seasonID = uuid.NullUUID{UUID: id, Valid: true}
+	}
+
+	if err := es.checkStatsRoster(ctx, event.TeamID, season, req.Players); err != nil {
+		return nil, err
+	}
+
+	tx, err := es.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	for _, p := range req.Players {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM player_game_stats WHERE event_id=$1 AND user_id=$2`, event.ID, p.UserID); err != nil {
+			return nil, err
+		}
+		for key, value := range p.Stats {
+			_, err := tx.ExecContext(ctx, `INSERT INTO player_game_stats(event_id,user_id,team_id,season_id,season_name,stat_key,value,recorded_by)
+			VALUES($1,$2,$3,$4,$5,$6,$7,$8)`,
+				event.ID, p.UserID, event.TeamID, seasonID, season.SeasonName, key, value, reqUserID)
+			if err != nil {
+				return nil, fmt.Errorf("issue recording game stats: %w", err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return es.gameStats(ctx, event, defs)
+}
+
+// checkStatsRoster rejects players missing from the season roster, or from the team when the game
+// is outside any season
+func (es *EventService) checkStatsRoster(ctx context.Context, teamID uuid.UUID, season *team_proto.GetRosterOnDateResponse, players []models.PlayerStatsReq) error {
+	onRoster := make(map[string]bool)
+	if season.SeasonId != "" {
+		for _, m := range season.Members {
+			onRoster[m.UserId] = true
+		}
+	} else {
+		ids := make([]string, 0, len(players))
+		for _, p := range players {
+			ids = append(ids, p.UserID.String())
+		}
+		res, err := es.teamClient.CheckTeamMembership(ctx, &team_proto.GetTeamMembershipRequest{
+			TeamId: teamID.String(),
+			UserId: ids,
+		})
+		if err != nil {
+			es.l.Error("gRPC membership check to team service failed", "error", err)
+			return err
+		}
+		for id := range res.Members {
+			onRoster[id] = true
+		}
+	}
+
+	for _, p := range players {
+		if !onRoster[p.UserID.String()] {
+			if season.SeasonId != "" {
+				return fmt.Errorf("%w: user %s is not on the %s roster", ErrInvalidEvent, p.UserID, season.SeasonName)
+			}
+			return fmt.Errorf("%w: user %s is not on the team", ErrInvalidEvent, p.UserID)
+		}
+	}
+	return nil
+}
+
+// GetGameStats is the stats recorded for each player in a game
+func (es *EventService) GetGameStats(ctx context.Context, reqUserID uuid.UUID, eventID uuid.UUID) (*models.GameStats, error) {
+	event, err := es.GetEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := es.requireTeamPermission(ctx, event.TeamID, reqUserID, PermEventsView); err != nil {
+		return nil, err
+	}
+
+	defs, err := es.statDefinitions(ctx, event.TeamID)
+	if err != nil {
+		return nil, err
+	}
+	return es.gameStats(ctx, event, defs)
+}
+
+func (es *EventService) gameStats(ctx context.Context, event *models.Event, defs []models.StatDefinition) (*models.GameStats, error) {
+	rows, err := es.db.QueryContext(ctx, `SELECT user_id,stat_key,value,season_id,season_name FROM player_game_stats
+	WHERE event_id=$1 ORDER BY user_id, stat_key`, event.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := &models.GameStats{
+		EventID:     event.ID,
+		TeamID:      event.TeamID,
+		Definitions: defs,
+		Players:     []models.PlayerGameStats{},
+	}
+	for rows.Next() {
+		var userID uuid.UUID
+		var key string
+		var value int
+		if err := rows.Scan(&userID, &key, &value, &stats.SeasonID, &stats.SeasonName); err != nil {
+			return nil, err
+		}
+		n := len(stats.Players)
+		if n == 0 || stats.Players[n-1].UserID != userID {
+			stats.Players = append(stats.Players, models.PlayerGameStats{UserID: userID, Stats: map[string]int{}})
+			n++
+		}
+		stats.Players[n-1].Stats[key] = value
+	}
+	return stats, rows.Err()
+}
+
+// GetSeasonStats is SeasonStats for members of the team
+func (es *EventService) GetSeasonStats(ctx context.Context, reqUserID uuid.UUID, teamID uuid.UUID, seasonID uuid.UUID) (*models.SeasonStats, error) {
+	if err := es.requireTeamPermission(ctx, teamID, reqUserID, PermEventsView); err != nil {
+		return nil, err
+	}
+
+	stats, err := es.SeasonStats(ctx, teamID, seasonID, nil)
+	if err != nil {
+		return nil, err
+	}
+	es.addStatPlayerNames(ctx, stats.Players)
+	return stats, nil
+}
+
+// SeasonStats totals the stats of each player over a season, the current one by default, limited
+// to userIDs when given. A team without a current season gets the totals of all its games
+func (es *EventService) SeasonStats(ctx context.Context, teamID uuid.UUID, seasonID uuid.UUID, userIDs []uuid.UUID) (*models.SeasonStats, error) {
+	season, seasonName, err := es.resolveSeason(ctx, teamID, seasonID)
+	if err != nil {
+		return nil, err
+	}
+
+	defs, err := es.statDefinitions(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT s.event_id,s.user_id,s.stat_key,s.value,s.season_name FROM player_game_stats s
+	JOIN events e ON e.event_id=s.event_id
+	WHERE s.team_id=$1 AND e.status<>$2`
+	args := []any{teamID, models.StatusCancelled}
+	if season.Valid {
+		args = append(args, season.UUID)
+		query += fmt.Sprintf(` AND s.season_id=$%d`, len(args))
+	}
+	if len(userIDs) > 0 {
+		ids := make([]string, 0, len(userIDs))
+		for _, id := range userIDs {
+			ids = append(ids, id.String())
+		}
+		args = append(args, pq.Array(ids))
+		query += fmt.Sprintf(` AND s.user_id=ANY($%d::uuid[])`, len(args))
+	}
+
+	rows, err := es.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stats []statRow
+	for rows.Next() {
+		var r statRow
+		var name string
+		if err := rows.Scan(&r.eventID, &r.userID, &r.key, &r.value, &name); err != nil {
+			return nil, err
+		}
+		if seasonName == "" {
+			seasonName = name
+		}
+		stats = append(stats, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &models.SeasonStats{
+		TeamID:      teamID,
+		SeasonID:    season,
+		SeasonName:  seasonName,
+		Definitions: defs,
+		Players:     aggregateStats(stats),
+	}, nil
+}
+
+// aggregateStats totals rows per player. A player has played the games they have any stat in,
+// averages are per game played and rounded to two decimals
+func aggregateStats(rows []statRow) []models.PlayerSeasonStats {
+	index := make(map[uuid.UUID]int)
+	games := make(map[uuid.UUID]map[uuid.UUID]bool)
+	players := []models.PlayerSeasonStats{}
+	for _, r := range rows {
+		i, ok := index[r.userID]
+		if !ok {
+			i = len(players)
+			index[r.userID] = i
+			games[r.userID] = make(map[uuid.UUID]bool)
+			players = append(players, models.PlayerSeasonStats{UserID: r.userID, Totals: map[string]int{}})
+		}
+		games[r.userID][r.eventID] = true
+		players[i].Totals[r.key] += r.value
+	}
+
+	for i := range players {
+		p := &players[i]
+		p.GamesPlayed = len(games[p.UserID])
+		p.Averages = make(map[string]float64, len(p.Totals))
+		for key, total := range p.Totals {
+			p.Averages[key] = math.Round(float64(total)/float64(p.GamesPlayed)*100) / 100
+		}
+	}
+
+	sort.Slice(players, func(i, j int) bool {
+		return players[i].UserID.String() < players[j].UserID.String()
+	})
+	return players
+}
+
+// GetStatLeaders is StatLeaders for members of the team
+func (es *EventService) GetStatLeaders(ctx context.Context, reqUserID uuid.UUID, teamID uuid.UUID, seasonID uuid.UUID, stat string, perGame bool, limit int) (*models.StatLeaderboard, error) {
+	if err := es.requireTeamPermission(ctx, teamID, reqUserID, PermEventsView); err != nil {
+		return nil, err
+	}
+
+	board, err := es.StatLeaders(ctx, teamID, seasonID, stat, perGame, limit)
+	if err != nil {
+		return nil, err
+	}
+	es.addLeaderNames(ctx, board.Leaders)
+	return board, nil
+}
+
+// StatLeaders ranks the players of a season, the current one by default, by their total of a stat
+// or its per-game average
+func (es *EventService) StatLeaders(ctx context.Context, teamID uuid.UUID, seasonID uuid.UUID, stat string, perGame bool, limit int) (*models.StatLeaderboard, error) {
+	stat = strings.ToLower(strings.TrimSpace(stat))
+	if limit <= 0 {
+		limit = defaultLeadersLimit
+	}
+	if limit > maxLeadersLimit {
+		return nil, fmt.Errorf("%w: limit is above %d", ErrInvalidEvent, maxLeadersLimit)
+	}
+
+	stats, err := es.SeasonStats(ctx, teamID, seasonID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	known := false
+	for _, d := range stats.Definitions {
+		if d.Key == stat {
+			known = true
+			break
+		}
+	}
+	if !known {
+		return nil, fmt.Errorf("%w: %q is not a stat of this team", ErrInvalidEvent, stat)
+	}
+
+	return &models.StatLeaderboard{
+		TeamID:     teamID,
+		SeasonID:   stats.SeasonID,
+		SeasonName: stats.SeasonName,
+		Stat:       stat,
+		PerGame:    perGame,
+		Leaders:    rankLeaders(stats.Players, stat, perGame, limit),
+	}, nil
+}
+
+// rankLeaders orders the players with the stat recorded from the highest value down. Equal values
+// share a rank and the next rank skips past them, the limit cuts by position
+func rankLeaders(players []models.PlayerSeasonStats, stat string, perGame bool, limit int) []models.StatLeader {
+	leaders := []models.StatLeader{}
+	for _, p := range players {
+		total, ok := p.Totals[stat]
+		if !ok {
+			continue
+		}
+		value := float64(total)
+		if perGame {
+			value = p.Averages[stat]
+		}
+		leaders = append(leaders, models.StatLeader{UserID: p.UserID, GamesPlayed: p.GamesPlayed, Value: value})
+	}
+
+	sort.SliceStable(leaders, func(i, j int) bool {
+		if leaders[i].Value != leaders[j].Value {
+			return leaders[i].Value > leaders[j].Value
+		}
+		return leaders[i].UserID.String() < leaders[j].UserID.String()
+	})
+
+	for i := range leaders {
+		if i > 0 && leaders[i].Value == leaders[i-1].Value {
+			leaders[i].Rank = leaders[i-1].Rank
+		} else {
+			leaders[i].Rank = i + 1
+		}
+	}
+
+	if len(leaders) > limit {
+		leaders = leaders[:limit]
+	}
+	return leaders
+}
+
+// playerNames looks up first and last names, best effort like addPlayerNames
+func (es *EventService) playerNames(ctx context.Context, ids []string) map[string]*user_proto.UserProfile {
+	if len(ids) == 0 {
+		return nil
+	}
+	res, err := es.userClient.GetUserProfiles(ctx, &user_proto.GetUserRequest{Userid: ids})
+	if err != nil {
+		es.l.Warn("player stats without names, user service lookup failed", "error", err)
+		return nil
+	}
+	return res.Profiles
+}
+
+func (es *EventService) addStatPlayerNames(ctx context.Context, players []models.PlayerSeasonStats) {
+	ids := make([]string, 0, len(players))
+	for _, p := range players {
+		ids = append(ids, p.UserID.String())
+	}
+	profiles := es.playerNames(ctx, ids)
+	for i := range players {
+		if profile, ok := profiles[players[i].UserID.String()]; ok {
+			players[i].FirstName = profile.Firstname
+			players[i].LastName = profile.Lastname
+		}
+	}
+}
+
+func (es *EventService) addLeaderNames(ctx context.Context, leaders []models.StatLeader) {
+	ids := make([]string, 0, len(leaders))
+	for _, l := range leaders {
+		ids = append(ids, l.UserID.String())
+	}
+	profiles := es.playerNames(ctx, ids)
+	for i := range leaders {
+		if profile, ok := profiles[leaders[i].UserID.String()]; ok {
+			leaders[i].FirstName = profile.Firstname
+			leaders[i].LastName = profile.Lastname
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/wycliff-ochieng/internal/models"
+)
+
+func TestValidateGameStats(t *testing.T) {
+	defs := []models.StatDefinition{
+		{Key: "goals", Name: "Goals"},
+		{Key: "yellow_cards", Name: "Yellow cards", MaxValue: intPtr(2)},
+	}
+	player := uuid.New()
+
+	cases := []struct {
+		name string
+		req  models.RecordGameStatsReq
+		ok   bool
+	}{
+		{"valid", models.RecordGameStatsReq{Players: []models.PlayerStatsReq{{UserID: player, Stats: map[string]int{"goals": 2, "yellow_cards": 1}}}}, true},
+		{"removal", models.RecordGameStatsReq{Players: []models.PlayerStatsReq{{UserID: player}}}, true},
+		{"no players", models.RecordGameStatsReq{}, false},
+		{"unknown stat", models.RecordGameStatsReq{Players: []models.PlayerStatsReq{{UserID: player, Stats: map[string]int{"rebounds": 1}}}}, false},
+		{"above max", models.RecordGameStatsReq{Players: []models.PlayerStatsReq{{UserID: player, Stats: map[string]int{"yellow_cards": 3}}}}, false},
+		{"negative", models.RecordGameStatsReq{Players: []models.PlayerStatsReq{{UserID: player, Stats: map[string]int{"goals": -1}}}}, false},
+		{"duplicate", models.RecordGameStatsReq{Players: []models.PlayerStatsReq{{UserID: player}, {UserID: player}}}, false},
+	}
+	for _, c := range cases {
+		err := validateGameStats(defs, &c.req)
+		if c.ok && err != nil {
+			t.Errorf("%s: unexpected error %v", c.name, err)
+		}
+		if !c.ok && !errors.Is(err, ErrInvalidEvent) {
+			t.Errorf("%s: got %v, want ErrInvalidEvent", c.name, err)
+		}
+	}
+}
+
+func TestAggregateAndRankStats(t *testing.T) {
+	a, b, c := uuid.New(), uuid.New(), uuid.New()
+	g1, g2 := uuid.New(), uuid.New()
+
+	players := aggregateStats([]statRow{
+		{eventID: g1, userID: a, key: "goals", value: 2},
+		{eventID: g1, userID: a, key: "assists", value: 1},
+		{eventID: g2, userID: a, key: "goals", value: 1},
+		{eventID: g1, userID: b, key: "goals", value: 3},
+		{eventID: g2, userID: c, key: "assists", value: 2},
+	})
+	if len(players) != 3 {
+		t.Fatalf("got %d players, want 3", len(players))
+	}
+	byID := map[uuid.UUID]models.PlayerSeasonStats{}
+	for _, p := range players {
+		byID[p.UserID] = p
+	}
+	if p := byID[a]; p.GamesPlayed != 2 || p.Totals["goals"] != 3 || p.Averages["goals"] != 1.5 || p.Averages["assists"] != 0.5 {
+		t.Errorf("player a = %+v", p)
+	}
+
+	//a and b share first place on goals, c has none recorded
+	leaders := rankLeaders(players, "goals", false, 10)
+	if len(leaders) != 2 || leaders[0].Rank != 1 || leaders[1].Rank != 1 || leaders[0].Value != 3 {
+		t.Errorf("goal leaders = %+v", leaders)
+	}
+
+	leaders = rankLeaders(players, "goals", true, 10)
+	if len(leaders) != 2 || leaders[0].UserID != b || leaders[1].Rank != 2 || leaders[1].Value != 1.5 {
+		t.Errorf("goals per game leaders = %+v", leaders)
+	}
+
+	if leaders = rankLeaders(players, "goals", true, 1); len(leaders) != 1 {
+		t.Errorf("limit 1 returned %d leaders", len(leaders))
+	}
+}
@@ -0,0 +1,532 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/wycliff-ochieng/internal/models"
+)
+
+var ErrResourceExists = fmt.Errorf("%w: the venue already has a resource with this name", ErrConflict)
+
+const (
+	maxVenueNameLength    = 150
+	maxResourceNameLength = 100
+	maxVenueResults       = 100
+	defaultCalendarSpan   = 7 * 24 * time.Hour
+	clockLayout           = "15:04"
+)
+
+// likePattern escapes the ILIKE wildcards of user input
+func likePattern(search string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return "%" + replacer.Replace(search) + "%"
+}
+
+func validateVenue(req *models.VenueReq) error {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return fmt.Errorf("%w: venue name is required", ErrInvalidEvent)
+	}
+	if len([]rune(req.Name)) > maxVenueNameLength {
+		return fmt.Errorf("%w: venue name is longer than %d characters", ErrInvalidEvent, maxVenueNameLength)
+	}
+	if (req.Latitude == nil) != (req.Longitude == nil) {
+		return fmt.Errorf("%w: latitude and longitude go together", ErrInvalidEvent)
+	}
+	if req.Latitude != nil && (*req.Latitude < -90 || *req.Latitude > 90 || *req.Longitude < -180 || *req.Longitude > 180) {
+		return fmt.Errorf("%w: coordinates are out of range", ErrInvalidEvent)
+	}
+	if req.Capacity < 0 {
+		return fmt.Errorf("%w: capacity cannot be negative", ErrInvalidEvent)
+	}
+	if req.TimeZone == "" {
+		req.TimeZone = "UTC"
+	}
+	if _, err := time.LoadLocation(req.TimeZone); err != nil {
+		return fmt.Errorf("%w: unknown time zone %q", ErrInvalidEvent, req.TimeZone)
+	}
+
+	for _, h := range req.OpeningHours {
+		if h.Weekday < 0 || h.Weekday > 6 {
+			return fmt.Errorf("%w: weekday must be 0 (Sunday) to 6", ErrInvalidEvent)
+		}
+		opens, err := time.Parse(clockLayout, h.Opens)
+		if err != nil {
+			return fmt.Errorf("%w: opening hours are HH:MM", ErrInvalidEvent)
+		}
+		closes, err := time.Parse(clockLayout, h.Closes)
+		if err != nil {
+			return fmt.Errorf("%w: opening hours are HH:MM", ErrInvalidEvent)
+		}
+		if !closes.After(opens) {
+			return fmt.Errorf("%w: a venue must close after it opens", ErrInvalidEvent)
+		}
+	}
+
+	for i := range req.Resources {
+		if err := validateResource(&req.Resources[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateResource(req *models.VenueResourceReq) error {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return fmt.Errorf("%w: resource name is required", ErrInvalidEvent)
+	}
+	if len([]rune(req.Name)) > maxResourceNameLength {
+		return fmt.Errorf("%w: resource name is longer than %d characters", ErrInvalidEvent, maxResourceNameLength)
+	}
+	if req.Capacity < 0 {
+		return fmt.Errorf("%w: capacity cannot be negative", ErrInvalidEvent)
+	}
+	return nil
+}
+
+// CreateVenue adds a venue with its resources and opening hours, whoever creates it becomes its admin
+func (es *EventService) CreateVenue(ctx context.Context, reqUserID uuid.UUID, req models.VenueReq) (*models.Venue, error) {
+	es.l.Info("creating venue", "userID", reqUserID)
+
+	if err := validateVenue(&req); err != nil {
+		return nil, err
+	}
+
+	tx, err := es.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var venueID uuid.UUID
+	err = tx.QueryRowContext(ctx, `INSERT INTO venues(name,address,latitude,longitude,capacity,time_zone,created_by)
+	VALUES($1,$2,$3,$4,NULLIF($5,0),$6,$7) RETURNING venue_id`,
+		req.Name, strings.TrimSpace(req.Address), req.Latitude, req.Longitude, req.Capacity, req.TimeZone, reqUserID).Scan(&venueID)
+	if err != nil {
+		return nil, fmt.Errorf("issue inserting venue: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `INSERT INTO venue_admins(venue_id,user_id) VALUES($1,$2)`, venueID, reqUserID); err != nil {
+		return nil, err
+	}
+
+	for _, r := range req.Resources {
+		if _, err := es.insertResource(ctx, tx, venueID, r); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := replaceOpeningHours(ctx, tx, venueID, req.OpeningHours); err != nil {
+		return nil, err
+	}
+
+	venue, err := es.getVenue(ctx, tx, venueID)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return venue, nil
+}
+
+// UpdateVenue replaces the details and opening hours of a venue, resources are added on their own
+func (es *EventService) UpdateVenue(ctx context.Context, reqUserID uuid.UUID, venueID uuid.UUID, req models.VenueReq) (*models.Venue, error) {
+	if err := validateVenue(&req); err != nil {
+		return nil, err
+	}
+
+	tx, err := es.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := es.isVenueAdmin(ctx, tx, venueID, reqUserID, true); err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE venues SET name=$1,address=$2,latitude=$3,longitude=$4,capacity=NULLIF($5,0),time_zone=$6,updated_at=NOW()
+	WHERE venue_id=$7`, req.Name, strings.TrimSpace(req.Address), req.Latitude, req.Longitude, req.Capacity, req.TimeZone, venueID)
+	if err != nil {
+		return nil, fmt.Errorf("issue updating venue: %w", err)
+	}
+
+	if err := replaceOpeningHours(ctx, tx, venueID, req.OpeningHours); err != nil {
+		return nil, err
+	}
+
+	venue, err := es.getVenue(ctx, tx, venueID)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return venue, nil
+}
+
+func replaceOpeningHours(ctx context.Context, tx *sql.Tx, venueID uuid.UUID, hours []models.OpeningHours) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM venue_hours WHERE venue_id=$1`, venueID); err != nil {
+		return err
+	}
+	for _, h := range hours {
+		_, err := tx.ExecContext(ctx, `INSERT INTO venue_hours(venue_id,weekday,opens_at,closes_at) VALUES($1,$2,$3,$4)`,
+			venueID, h.Weekday, h.Opens, h.Closes)
+		if err != nil {
+			var pqErr *pq.Error
+			if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+				return fmt.Errorf("%w: two openings start at the same time", ErrInvalidEvent)
+			}
+			return fmt.Errorf("issue inserting opening hours: %w", err)
+		}
+	}
+	return nil
+}
+
+// AddVenueResource adds a court, pitch or other bookable part of a venue
+func (es *EventService) AddVenueResource(ctx context.Context, reqUserID uuid.UUID, venueID uuid.UUID, req models.VenueResourceReq) (*models.VenueResource, error) {
+	if err := validateResource(&req); err != nil {
+		return nil, err
+	}
+	if _, err := es.isVenueAdmin(ctx, es.db, venueID, reqUserID, true); err != nil {
+		return nil, err
+	}
+	return es.insertResource(ctx, es.db, venueID, req)
+}
+
+func (es *EventService) insertResource(ctx context.Context, q queryer, venueID uuid.UUID, req models.VenueResourceReq) (*models.VenueResource, error) {
+	var r models.VenueResource
+	err := q.QueryRowContext(ctx, `INSERT INTO venue_resources(venue_id,name,surface,capacity) VALUES($1,$2,$3,NULLIF($4,0))
+	RETURNING resource_id,venue_id,name,surface,COALESCE(capacity,0)`, venueID, req.Name, strings.TrimSpace(req.Surface), req.Capacity).Scan(
+		&r.ResourceID,
+		&r.VenueID,
+		&r.Name,
+		&r.Surface,
+		&r.Capacity,
+	)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return nil, ErrResourceExists
+		}
+		return nil, fmt.Errorf("issue inserting venue resource: %w", err)
+	}
+	return &r, nil
+}
+
+// AddVenueAdmin lets another user manage the venue, adding an admin twice changes nothing
+func (es *EventService) AddVenueAdmin(ctx context.Context, reqUserID uuid.UUID, venueID uuid.UUID, userID uuid.UUID) error {
+	if userID == uuid.Nil {
+		return fmt.Errorf("%w: user id is required", ErrInvalidEvent)
+	}
+	if _, err := es.isVenueAdmin(ctx, es.db, venueID, reqUserID, true); err != nil {
+		return err
+	}
+	_, err := es.db.ExecContext(ctx, `INSERT INTO venue_admins(venue_id,user_id) VALUES($1,$2) ON CONFLICT DO NOTHING`, venueID, userID)
+	return err
+}
+
+// isVenueAdmin reports whether the user manages the venue, with required a non admin gets ErrForbidden
+func (es *EventService) isVenueAdmin(ctx context.Context, q queryer, venueID uuid.UUID, userID uuid.UUID, required bool) (bool, error) {
+	var admin bool
+	err := q.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM venue_admins a WHERE a.venue_id=v.venue_id AND a.user_id=$2)
+	FROM venues v WHERE v.venue_id=$1`, venueID, userID).Scan(&admin)
+	if err == sql.ErrNoRows {
+		return false, ErrNotFound
+	}
+	if err != nil {
+		return false, err
+	}
+	if required && !admin {
+		return false, ErrForbidden
+	}
+	return admin, nil
+}
+
+const venueColumns = `venue_id,name,address,latitude,longitude,COALESCE(capacity,0),time_zone,created_by,created_at,updated_at`
+
+func venueFields(v *models.Venue) []any {
+	return []any{
+		&v.VenueID,
+		&v.Name,
+		&v.Address,
+		&v.Latitude,
+		&v.Longitude,
+		&v.Capacity,
+		&v.TimeZone,
+		&v.CreatedBy,
+		&v.CreatedAt,
+		&v.UpdatedAt,
+	}
+}
+
+// ListVenues finds venues by name or address for booking, without their resources and hours
+func (es *EventService) ListVenues(ctx context.Context, search string) ([]models.Venue, error) {
+	query := `SELECT ` + venueColumns + ` FROM venues`
+	args := []any{}
+	if search = strings.TrimSpace(search); search != "" {
+		query += ` WHERE name ILIKE $1 OR address ILIKE $1`
+		args = append(args, likePattern(search))
+	}
+	query += fmt.Sprintf(" ORDER BY name, venue_id LIMIT %d", maxVenueResults)
+
+	rows, err := es.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	venues := []models.Venue{}
+	for rows.Next() {
+		var v models.Venue
+		if err := rows.Scan(venueFields(&v)...); err != nil {
+			return nil, err
+		}
+		venues = append(venues, v)
+	}
+	return venues, rows.Err()
+}
+
+func (es *EventService) GetVenue(ctx context.Context, venueID uuid.UUID) (*models.Venue, error) {
+	return es.getVenue(ctx, es.db, venueID)
+}
+
+func (es *EventService) getVenue(ctx context.Context, q queryer, venueID uuid.UUID) (*models.Venue, error) {
+	var v models.Venue
+	err := q.QueryRowContext(ctx, `SELECT `+venueColumns+` FROM venues WHERE venue_id=$1`, venueID).Scan(venueFields(&v)...)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := q.QueryContext(ctx, `SELECT resource_id,venue_id,name,surface,COALESCE(capacity,0) FROM venue_resources
+	WHERE venue_id=$1 ORDER BY name`, venueID)
+	if err != nil {
+		return nil, err
+	}
+	v.Resources = []models.VenueResource{}
+	for rows.Next() {
+		var r models.VenueResource
+		if err := rows.Scan(&r.ResourceID, &r.VenueID, &r.Name, &r.Surface, &r.Capacity); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		v.Resources = append(v.Resources, r)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = q.QueryContext(ctx, `SELECT weekday,to_char(opens_at,'HH24:MI'),to_char(closes_at,'HH24:MI') FROM venue_hours
+	WHERE venue_id=$1 ORDER BY weekday, opens_at`, venueID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	v.OpeningHours = []models.OpeningHours{}
+	for rows.Next() {
+		var h models.OpeningHours
+		if err := rows.Scan(&h.Weekday, &h.Opens, &h.Closes); err != nil {
+			return nil, err
+		}
+		v.OpeningHours = append(v.OpeningHours, h)
+	}
+	return &v, rows.Err()
+}
+
+// venueBooking is where an event is booked, location is what calendars show when the event has none
+type venueBooking struct {
+	venueID    uuid.NullUUID
+	resourceID uuid.NullUUID
+	location   string
+}
+
+// resolveVenue checks the venue and resource an event is booked at, a resource alone is enough to
+// find its venue. Without a location the event is labelled "Venue, Court 2"
+func (es *EventService) resolveVenue(ctx context.Context, q queryer, venueID uuid.UUID, resourceID uuid.UUID, location string) (*venueBooking, error) {
+	b := &venueBooking{location: strings.TrimSpace(location)}
+
+	var label string
+	switch {
+	case resourceID != uuid.Nil:
+		var resourceVenue uuid.UUID
+		var venueName, resourceName string
+		err := q.QueryRowContext(ctx, `SELECT r.venue_id,v.name,r.name FROM venue_resources r JOIN venues v ON v.venue_id=r.venue_id
+		WHERE r.resource_id=$1`, resourceID).Scan(&resourceVenue, &venueName, &resourceName)
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: unknown venue resource", ErrInvalidEvent)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if venueID != uuid.Nil && venueID != resourceVenue {
+			return nil, fmt.Errorf("%w: the resource is not at this venue", ErrInvalidEvent)
+		}
+		b.venueID = uuid.NullUUID{UUID: resourceVenue, Valid: true}
+		b.resourceID = uuid.NullUUID{UUID: resourceID, Valid: true}
+		label = venueName + ", " + resourceName
+	case venueID != uuid.Nil:
+		err := q.QueryRowContext(ctx, `SELECT name FROM venues WHERE venue_id=$1`, venueID).Scan(&label)
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: unknown venue", ErrInvalidEvent)
+		}
+		if err != nil {
+			return nil, err
+		}
+		b.venueID = uuid.NullUUID{UUID: venueID, Valid: true}
+	}
+
+	if b.location == "" {
+		b.location = label
+	}
+	return b, nil
+}
+
+// BlockOutVenue keeps the venue, or one of its resources, free for the range. Events already booked
+// there stay scheduled and are returned so the admin can follow up with their teams
+func (es *EventService) BlockOutVenue(ctx context.Context, reqUserID uuid.UUID, venueID uuid.UUID, req models.BlockOutReq) (*models.BlockOutResult, error) {
+	es.l.Info("blocking out venue time", "venueID", venueID)
+
+	if err := validateTimes(req.StartTime, req.EndTime); err != nil {
+		return nil, err
+	}
+	req.Reason = strings.TrimSpace(req.Reason)
+	if len([]rune(req.Reason)) > maxReasonLength {
+		return nil, fmt.Errorf("%w: reason is longer than %d characters", ErrInvalidEvent, maxReasonLength)
+	}
+
+	tx, err := es.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := es.isVenueAdmin(ctx, tx, venueID, reqUserID, true); err != nil {
+		return nil, err
+	}
+
+	resourceID := uuid.NullUUID{UUID: req.ResourceID, Valid: req.ResourceID != uuid.Nil}
+	if resourceID.Valid {
+		b, err := es.resolveVenue(ctx, tx, venueID, req.ResourceID, "")
+		if err != nil {
+			return nil, err
+		}
+		resourceID = b.resourceID
+	}
+
+	var b models.BlockOut
+	err = tx.QueryRowContext(ctx, `INSERT INTO venue_blockouts(venue_id,resource_id,start_time,end_time,reason,created_by)
+	VALUES($1,$2,$3,$4,$5,$6) RETURNING blockout_id,venue_id,resource_id,start_time,end_time,reason,created_by,created_at`,
+		venueID, resourceID, req.StartTime.UTC(), req.EndTime.UTC(), req.Reason, reqUserID).Scan(
+		&b.BlockOutID,
+		&b.VenueID,
+		&b.ResourceID,
+		&b.StartTime,
+		&b.EndTime,
+		&b.Reason,
+		&b.CreatedBy,
+		&b.CreatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("issue inserting block-out: %w", err)
+	}
+
+	affected, err := es.conflictingEvents(ctx, tx, `SELECT e.event_id,e.team_id,COALESCE(e.event_title,''),COALESCE(e.location,''),
+	e.start_time,e.end_time,b.blockout_id FROM venue_blockouts b
+	JOIN events e ON e.venue_id=b.venue_id AND (b.resource_id IS NULL OR e.resource_id IS NULL OR e.resource_id=b.resource_id)
+		AND e.start_time < b.end_time AND e.end_time > b.start_time AND e.status=$2
+	WHERE b.blockout_id=$1 ORDER BY e.start_time, e.event_id`, b.BlockOutID, models.StatusScheduled)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &models.BlockOutResult{BlockOut: b, Affected: affected}, nil
+}
+
+func (es *EventService) DeleteBlockOut(ctx context.Context, reqUserID uuid.UUID, venueID uuid.UUID, blockOutID uuid.UUID) error {
+	if _, err := es.isVenueAdmin(ctx, es.db, venueID, reqUserID, true); err != nil {
+		return err
+	}
+
+	res, err := es.db.ExecContext(ctx, `DELETE FROM venue_blockouts WHERE blockout_id=$1 AND venue_id=$2`, blockOutID, venueID)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// GetVenueCalendar lists the scheduled events and block-outs of a venue starting in the range, the
+// next 7 days by default. Venue admins see what each booking is, anyone else only when it is busy
+func (es *EventService) GetVenueCalendar(ctx context.Context, reqUserID uuid.UUID, venueID uuid.UUID, from time.Time, to time.Time) (*models.VenueCalendar, error) {
+	if from.IsZero() {
+		now := time.Now().UTC()
+		from = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	if to.IsZero() {
+		to = from.Add(defaultCalendarSpan)
+	}
+	if !from.Before(to) {
+		return nil, fmt.Errorf("%w: from must be before to", ErrInvalidRange)
+	}
+	if to.Sub(from) > maxStatsRange {
+		return nil, fmt.Errorf("%w: at most 366 days", ErrInvalidRange)
+	}
+
+	admin, err := es.isVenueAdmin(ctx, es.db, venueID, reqUserID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	//bookings that started before from but are still running are included
+	rows, err := es.db.QueryContext(ctx, `SELECT $4::text,e.event_id,e.team_id,COALESCE(e.event_title,''),e.resource_id,COALESCE(r.name,''),e.start_time,e.end_time
+	FROM events e LEFT JOIN venue_resources r ON r.resource_id=e.resource_id
+	WHERE e.venue_id=$1 AND e.start_time < $3 AND e.end_time > $2 AND e.status=$6
+	UNION ALL
+	SELECT $5::text,b.blockout_id,NULL,b.reason,b.resource_id,COALESCE(r.name,''),b.start_time,b.end_time
+	FROM venue_blockouts b LEFT JOIN venue_resources r ON r.resource_id=b.resource_id
+	WHERE b.venue_id=$1 AND b.start_time < $3 AND b.end_time > $2
+	ORDER BY 7, 6, 2`,
+		venueID, from.UTC(), to.UTC(), models.BookingEvent, models.BookingBlockOut, models.StatusScheduled)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	bookings := []models.VenueBooking{}
+	for rows.Next() {
+		var b models.VenueBooking
+		var teamID uuid.NullUUID
+		if err := rows.Scan(&b.Kind, &b.ID, &teamID, &b.Title, &b.ResourceID, &b.ResourceName, &b.StartTime, &b.EndTime); err != nil {
+			return nil, err
+		}
+		if admin {
+			b.TeamID = teamID.UUID
+		} else {
+			b.ID = uuid.Nil
+			b.Title = ""
+		}
+		bookings = append(bookings, b)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return &models.VenueCalendar{VenueID: venueID, From: from, To: to, Bookings: bookings}, nil
+}
@@ -0,0 +1,40 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/wycliff-ochieng/internal/models"
+)
+
+func TestValidateVenue(t *testing.T) {
+	lat, lng := -1.2921, 36.8219
+	req := models.VenueReq{
+		Name:         "  Kasarani Indoor Arena ",
+		Latitude:     &lat,
+		Longitude:    &lng,
+		Resources:    []models.VenueResourceReq{{Name: " Court 2 ", Surface: "hardwood"}},
+		OpeningHours: []models.OpeningHours{{Weekday: 1, Opens: "08:00", Closes: "22:00"}},
+	}
+	if err := validateVenue(&req); err != nil {
+		t.Fatal(err)
+	}
+	if req.Name != "Kasarani Indoor Arena" || req.Resources[0].Name != "Court 2" || req.TimeZone != "UTC" {
+		t.Errorf("validateVenue did not normalize the request: %+v", req)
+	}
+
+	invalid := map[string]models.VenueReq{
+		"no name":          {Name: " "},
+		"only latitude":    {Name: "Arena", Latitude: &lat},
+		"closes too early": {Name: "Arena", OpeningHours: []models.OpeningHours{{Weekday: 1, Opens: "22:00", Closes: "08:00"}}},
+		"bad weekday":      {Name: "Arena", OpeningHours: []models.OpeningHours{{Weekday: 7, Opens: "08:00", Closes: "22:00"}}},
+		"bad clock":        {Name: "Arena", OpeningHours: []models.OpeningHours{{Weekday: 1, Opens: "8am", Closes: "22:00"}}},
+		"unknown zone":     {Name: "Arena", TimeZone: "Mars/Olympus"},
+		"unnamed court":    {Name: "Arena", Resources: []models.VenueResourceReq{{Surface: "clay"}}},
+	}
+	for name, req := range invalid {
+		if err := validateVenue(&req); !errors.Is(err, ErrInvalidEvent) {
+			t.Errorf("%s: got %v, want ErrInvalidEvent", name, err)
+		}
+	}
+}
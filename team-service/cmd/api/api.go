@@ -1,11 +1,17 @@
 package api
 
 import (
+	"context"
+	"errors"
+	"github/wycliff-ochieng/internal/chat"
 	"github/wycliff-ochieng/internal/config"
+	"github/wycliff-ochieng/internal/consumer"
 	"github/wycliff-ochieng/internal/database"
+	"github/wycliff-ochieng/internal/filestore"
 	"github/wycliff-ochieng/internal/handlers"
 	internal "github/wycliff-ochieng/internal/producer"
 	"github/wycliff-ochieng/internal/service"
+	"github/wycliff-ochieng/middleware"
 	"net"
 
 	rpc "github/wycliff-ochieng/grpc"
@@ -16,9 +22,12 @@ import (
 	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"github.com/wycliff-ochieng/common_packages/team_grpc/team_proto"
 	auth "github.com/wycliff-ochieng/sports-common-package/middleware"
-	"github.com/wycliff-ochieng/sports-common-package/team_grpc/team_proto"
 	"github.com/wycliff-ochieng/sports-common-package/user_grpc/user_proto"
 
 	"github.com/gorilla/mux"
@@ -78,9 +87,45 @@ func (s *APIServer) Run() {
 
 	userClient := user_proto.NewUserServiceRPCClient(conn)
 
-	ts := service.NewTeamService(db, userClient, ep, s.cfg)
+	//team logos live in the same minio bucket as workout media
+	fs, err := filestore.NewFileStore(s.cfg.MinIOEndpoint, s.cfg.MinIOAccessKey, s.cfg.MinIOSecretKey, s.cfg.MinIOBucket, false)
+	if err != nil {
+		log.Printf("issue setting up minio due to : %s", err)
+	}
+
+	ts := service.NewTeamService(db, userClient, ep, fs, s.cfg)
+
+	//gracefully shutdown
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	//hard delete teams whose deletion grace period ran out
+	go ts.RunDeletionPurge(ctx, time.Hour)
+
+	//react to deleted, suspended and updated users and build the team activity feed
+	ec, err := consumer.NewEventConsumer(l, ts, s.cfg.KafkaBroker, s.cfg.UserEventsGroupID)
+	if err != nil {
+		log.Fatalf("error setting up event consumer: %v", err)
+	}
+
+	consumerDone := make(chan struct{})
+	go func() {
+		ec.StartEventConsumer(ctx, append([]string{s.cfg.UserEventsTopic}, s.cfg.ActivityTopics...))
+		close(consumerDone)
+	}()
+
+	//team chat, every replica reads the frames of all the others from CHAT_TOPIC
+	chatBus := chat.NewKafkaBus(l, p, s.cfg.ChatTopic)
+	hub := chat.NewHub(l, ts, chatBus, s.cfg.ChatReplicaID)
+	go hub.Run(ctx)
+	go func() {
+		if err := chatBus.Listen(ctx, hub, s.cfg.KafkaBroker, "team-service-chat-"+s.cfg.ChatReplicaID); err != nil {
+			log.Printf("team chat fan-out stopped: %v", err)
+		}
+	}()
 
 	th := handlers.NewTeamHandler(l, ts)
+	ch := handlers.NewChatHandler(l, ts, hub, s.cfg.CORSAllowedOrigins)
 
 	//instatiate middleware
 	authMiddleware := auth.AuthMiddleware(s.cfg.JWTSecret, logger) //.TeamMiddlware(s.cfg.JWTSecret)
@@ -126,7 +171,6 @@ func (s *APIServer) Run() {
 	createTeam := router.Methods("POST").Subrouter()
 	createTeam.HandleFunc("/api/teams", th.CreateTeam)
 	createTeam.Use(authMiddleware)
-	createTeam.Use(auth.RequireRole("coach", "manager", "player"))
 
 	getTeams := router.Methods("GET").Subrouter()
 	getTeams.HandleFunc("/api/get/teams", th.GetTeams)
@@ -139,24 +183,181 @@ func (s *APIServer) Run() {
 	updateTeam := router.Methods("PUT").Subrouter()
 	updateTeam.HandleFunc("/api/team/{team_id}/update", th.UpdateTeam)
 	updateTeam.Use(authMiddleware)
-	updateTeam.Use(auth.RequireRole("coach", "manager", "player"))
 	//updateTeam.Use(middleware.UserMiddlware(s.cfg.JWTSecret))
 
 	addMember := router.Methods("POST").Subrouter()
 	addMember.HandleFunc("/api/team/{team_id}/add", th.AddTeamMember)
 	addMember.Use(authMiddleware)
-	addMember.Use(auth.RequireRole("coach", "manager", "player"))
 
 	getTeamList := router.Methods("GET").Subrouter()
 	getTeamList.HandleFunc("/api/team/{team_id}/members", th.GetTeamRoster)
+	getTeamList.HandleFunc("/api/sports/{sport}/positions", th.GetSportPositions)
+	getTeamList.Use(authMiddleware)
 
+	//roster mutations are authorized against the team role (team_members), not the global jwt roles
 	updateTeamMember := router.Methods("PUT").Subrouter()
 	updateTeamMember.HandleFunc("/api/team/{teamid}/members/{user_id}/update", th.UpdateTeamMember)
-	updateTeamMember.Use(auth.RequireRole("coach", "manager"))
+	updateTeamMember.Use(authMiddleware)
 
 	deleteTeamMember := router.Methods("DELETE").Subrouter()
 	deleteTeamMember.HandleFunc("/api/team/{teamid}/member/{user_id}/delete", th.RemoveTeamMember)
-	deleteTeamMember.Use(auth.RequireRole("coach", "manager"))
+	deleteTeamMember.HandleFunc("/api/team/{team_id}/leave", th.LeaveTeam)
+	deleteTeamMember.Use(authMiddleware)
+
+	//invitations
+	inviteActions := router.Methods("POST").Subrouter()
+	inviteActions.HandleFunc("/api/team/{team_id}/invites", th.CreateInvite)
+	inviteActions.HandleFunc("/api/invites/{invite_id}/accept", th.AcceptInvite)
+	inviteActions.HandleFunc("/api/invites/{invite_id}/decline", th.DeclineInvite)
+	inviteActions.HandleFunc("/api/invites/join/{code}", th.JoinWithCode)
+	inviteActions.Use(authMiddleware)
+
+	getInvites := router.Methods("GET").Subrouter()
+	getInvites.HandleFunc("/api/team/{team_id}/invites", th.GetTeamInvites)
+	getInvites.HandleFunc("/api/invites/me", th.GetMyInvites)
+	getInvites.Use(authMiddleware)
+
+	//team roles and permissions, authorization happens per team in the service layer
+	getRoles := router.Methods("GET").Subrouter()
+	getRoles.HandleFunc("/api/team/{team_id}/roles", th.GetTeamRoles)
+	getRoles.Use(authMiddleware)
+
+	createRole := router.Methods("POST").Subrouter()
+	createRole.HandleFunc("/api/team/{team_id}/roles", th.CreateTeamRole)
+	createRole.Use(authMiddleware)
+
+	updateRole := router.Methods("PUT").Subrouter()
+	updateRole.HandleFunc("/api/team/{team_id}/roles/{role}", th.UpdateTeamRole)
+	updateRole.Use(authMiddleware)
+
+	deleteRole := router.Methods("DELETE").Subrouter()
+	deleteRole.HandleFunc("/api/team/{team_id}/roles/{role}", th.DeleteTeamRole)
+	deleteRole.Use(authMiddleware)
+
+	//branding, logos are uploaded straight to minio through a presigned url
+	logoUpload := router.Methods("POST").Subrouter()
+	logoUpload.HandleFunc("/api/team/{team_id}/logo/presigned-url", th.LogoPresignedURL)
+	logoUpload.Use(authMiddleware)
+
+	updateBranding := router.Methods("PUT").Subrouter()
+	updateBranding.HandleFunc("/api/team/{team_id}/logo/complete", th.LogoUploadComplete)
+	updateBranding.HandleFunc("/api/team/{team_id}/branding", th.UpdateTeamBranding)
+	updateBranding.Use(authMiddleware)
+
+	//organizations, /me is registered before /{org_id} so it is not parsed as an id
+	getOrgs := router.Methods("GET").Subrouter()
+	getOrgs.HandleFunc("/api/organizations/me", th.GetMyOrganizations)
+	getOrgs.HandleFunc("/api/organizations/{org_id}", th.GetOrganization)
+	getOrgs.HandleFunc("/api/organizations/{org_id}/teams", th.GetOrganizationTeams)
+	getOrgs.HandleFunc("/api/organizations/{org_id}/members", th.GetOrganizationMembers)
+	getOrgs.Use(authMiddleware)
+
+	orgActions := router.Methods("POST").Subrouter()
+	orgActions.HandleFunc("/api/organizations", th.CreateOrganization)
+	orgActions.HandleFunc("/api/organizations/{org_id}/admins", th.AddOrganizationAdmin)
+	orgActions.Use(authMiddleware)
+
+	updateOrg := router.Methods("PUT").Subrouter()
+	updateOrg.HandleFunc("/api/organizations/{org_id}", th.UpdateOrganization)
+	updateOrg.HandleFunc("/api/team/{team_id}/organization", th.MoveTeamToOrganization)
+	updateOrg.Use(authMiddleware)
+
+	deleteOrgAdmin := router.Methods("DELETE").Subrouter()
+	deleteOrgAdmin.HandleFunc("/api/organizations/{org_id}/admins/{user_id}", th.RemoveOrganizationAdmin)
+	deleteOrgAdmin.Use(authMiddleware)
+
+	//squad numbers, positions and depth chart
+	updatePositions := router.Methods("PUT").Subrouter()
+	updatePositions.HandleFunc("/api/team/{team_id}/members/{user_id}/position", th.UpdateMemberPosition)
+	updatePositions.HandleFunc("/api/team/{team_id}/depth-chart", th.SetDepthChart)
+	updatePositions.Use(authMiddleware)
+
+	//seasons and season rosters
+	getSeasons := router.Methods("GET").Subrouter()
+	getSeasons.HandleFunc("/api/team/{team_id}/seasons", th.GetSeasons)
+	getSeasons.HandleFunc("/api/team/{team_id}/seasons/{season_id}/roster", th.GetSeasonRoster)
+	getSeasons.HandleFunc("/api/team/{team_id}/roster", th.GetRosterOnDate)
+	getSeasons.Use(authMiddleware)
+
+	seasonActions := router.Methods("POST").Subrouter()
+	seasonActions.HandleFunc("/api/team/{team_id}/seasons", th.CreateSeason)
+	seasonActions.HandleFunc("/api/team/{team_id}/seasons/{season_id}/rollover", th.RolloverSeason)
+	seasonActions.Use(authMiddleware)
+
+	updateSeason := router.Methods("PUT").Subrouter()
+	updateSeason.HandleFunc("/api/team/{team_id}/seasons/{season_id}", th.UpdateSeason)
+	updateSeason.Use(authMiddleware)
+
+	//bulk roster import/export
+	importRoster := router.Methods("POST").Subrouter()
+	importRoster.HandleFunc("/api/team/{team_id}/roster/import", th.ImportRoster)
+	importRoster.Use(authMiddleware)
+
+	exportRoster := router.Methods("GET").Subrouter()
+	exportRoster.HandleFunc("/api/team/{team_id}/roster/export", th.ExportRoster)
+	exportRoster.Use(authMiddleware)
+
+	//team discovery and join requests
+	discoverTeams := router.Methods("GET").Subrouter()
+	discoverTeams.HandleFunc("/api/teams", th.ListTeams)
+	discoverTeams.HandleFunc("/api/team/{team_id}/profile", th.GetTeamProfile)
+	discoverTeams.HandleFunc("/api/team/{team_id}/join-requests", th.GetJoinRequests)
+	discoverTeams.HandleFunc("/api/join-requests/me", th.GetMyJoinRequests)
+	discoverTeams.Use(authMiddleware)
+
+	joinRequests := router.Methods("POST").Subrouter()
+	joinRequests.HandleFunc("/api/team/{team_id}/join-requests", th.RequestToJoin)
+	joinRequests.HandleFunc("/api/team/{team_id}/join-requests/{request_id}/approve", th.ApproveJoinRequest)
+	joinRequests.HandleFunc("/api/team/{team_id}/join-requests/{request_id}/reject", th.RejectJoinRequest)
+	joinRequests.Use(authMiddleware)
+
+	updateVisibility := router.Methods("PUT").Subrouter()
+	updateVisibility.HandleFunc("/api/team/{team_id}/visibility", th.UpdateTeamVisibility)
+	updateVisibility.Use(authMiddleware)
+
+	cancelJoinRequest := router.Methods("DELETE").Subrouter()
+	cancelJoinRequest.HandleFunc("/api/team/{team_id}/join-requests/{request_id}", th.CancelJoinRequest)
+	cancelJoinRequest.Use(authMiddleware)
+
+	//team lifecycle
+	teamLifecycle := router.Methods("POST").Subrouter()
+	teamLifecycle.HandleFunc("/api/team/{team_id}/archive", th.ArchiveTeam)
+	teamLifecycle.HandleFunc("/api/team/{team_id}/restore", th.RestoreTeam)
+	teamLifecycle.Use(authMiddleware)
+
+	deleteTeam := router.Methods("DELETE").Subrouter()
+	deleteTeam.HandleFunc("/api/team/{team_id}", th.DeleteTeam)
+	deleteTeam.Use(authMiddleware)
+
+	//team activity feed
+	teamActivity := router.Methods("GET").Subrouter()
+	teamActivity.HandleFunc("/api/team/{team_id}/activity", th.GetTeamActivity)
+	teamActivity.Use(authMiddleware)
+
+	//team announcements
+	postAnnouncements := router.Methods("POST").Subrouter()
+	postAnnouncements.HandleFunc("/api/team/{team_id}/announcements", th.PostAnnouncement)
+	postAnnouncements.HandleFunc("/api/team/{team_id}/announcements/{announcement_id}/ack", th.AcknowledgeAnnouncement)
+	postAnnouncements.Use(authMiddleware)
+
+	getAnnouncements := router.Methods("GET").Subrouter()
+	getAnnouncements.HandleFunc("/api/team/{team_id}/announcements", th.GetAnnouncements)
+	getAnnouncements.HandleFunc("/api/team/{team_id}/announcements/{announcement_id}/acks", th.GetAnnouncementAcks)
+	getAnnouncements.Use(authMiddleware)
+
+	pinAnnouncement := router.Methods("PUT").Subrouter()
+	pinAnnouncement.HandleFunc("/api/team/{team_id}/announcements/{announcement_id}/pin", th.PinAnnouncement)
+	pinAnnouncement.Use(authMiddleware)
+
+	deleteAnnouncement := router.Methods("DELETE").Subrouter()
+	deleteAnnouncement.HandleFunc("/api/team/{team_id}/announcements/{announcement_id}", th.DeleteAnnouncement)
+	deleteAnnouncement.Use(authMiddleware)
+
+	//team chat, browsers pass the JWT as ?access_token= on the WebSocket handshake
+	teamChat := router.Methods("GET").Subrouter()
+	teamChat.HandleFunc("/api/team/{team_id}/chat/ws", ch.ServeChat)
+	teamChat.HandleFunc("/api/team/{team_id}/chat/messages", th.GetChatMessages)
+	teamChat.Use(middleware.WebSocketToken, authMiddleware)
 
 	origins := s.cfg.CORSAllowedOrigins
 
@@ -167,8 +368,23 @@ func (s *APIServer) Run() {
 
 	cm := corshandlers.CORS(allowedOrigins, allowCredentials, allowedMethods, allowedHeaders)(router)
 
-	if err := http.ListenAndServe(s.addrr, cm); err != nil {
+	srv := &http.Server{Addr: s.addrr, Handler: cm}
+
+	go func() {
+		<-ctx.Done()
+		grpcServ.GracefulStop()
+		shutdownCtx, done := context.WithTimeout(context.Background(), 10*time.Second)
+		defer done()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			log.Printf("error shutting down http server: %v", err)
+		}
+	}()
+
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		log.Fatalf("Error setting up router: %v", err)
 	}
 
+	//let the consumer finish the message in hand before exiting
+	<-consumerDone
+
 }
@@ -2,11 +2,18 @@ package grpc
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"github/wycliff-ochieng/internal/models"
+	"github/wycliff-ochieng/internal/permissions"
 	"github/wycliff-ochieng/internal/service"
 	"log"
+	"time"
 
 	"github.com/google/uuid"
-	"github.com/wycliff-ochieng/sports-common-package/team_grpc/team_proto"
+	"github.com/wycliff-ochieng/common_packages/team_grpc/team_proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type Server struct {
@@ -22,6 +29,24 @@ func NewServer(service *service.TeamService, l *log.Logger) *Server {
 	}
 }
 
+// toProtoMember maps a roster entry, unset numbers and depth slots are sent as 0
+func toProtoMember(m *models.TeamMembers) *team_proto.TeamMember {
+	member := &team_proto.TeamMember{
+		UserId:            m.UserID.String(),
+		TeamId:            m.TeamID.String(),
+		Role:              m.Role,
+		PrimaryPosition:   m.PrimaryPosition,
+		SecondaryPosition: m.SecondaryPosition,
+	}
+	if m.JerseyNumber != nil {
+		member.JerseyNumber = int32(*m.JerseyNumber)
+	}
+	if m.DepthOrder != nil {
+		member.DepthOrder = int32(*m.DepthOrder)
+	}
+	return member
+}
+
 func (s *Server) CheckTeamMembership(ctx context.Context, req *team_proto.GetTeamMembershipRequest) (*team_proto.GetTeamMembershipResponse, error) {
 
 	teamID, err := uuid.Parse(req.TeamId)
@@ -37,14 +62,14 @@ func (s *Server) CheckTeamMembership(ctx context.Context, req *team_proto.GetTea
 		return nil, err
 	}
 
+	//suspended and inactive members are not on the roster for event-service's checks
 	grpcTeamMembers := make(map[string]*team_proto.TeamMember)
 
 	for _, m := range members {
-		grpcTeamMembers[m.UserID.String()] = &team_proto.TeamMember{
-			UserId: m.UserID.String(),
-			TeamId: m.TeamID.String(),
-			Role:   m.Role,
+		if m.Status != models.MemberStatusActive {
+			continue
 		}
+		grpcTeamMembers[m.UserID.String()] = toProtoMember(m)
 
 	}
 
@@ -59,20 +84,166 @@ func (s *Server) GetTeamSummary(ctx context.Context, req *team_proto.GetTeamSumm
 		return nil, err
 	}
 
+	team, err := s.Service.GetTeamByID(ctx, teamID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "team %s not found", teamID)
+		}
+		return nil, err
+	}
+
 	members, err := s.Service.GetTeamsMembers(ctx, teamID)
 	if err != nil {
 		return nil, err
 	}
 
-	//grpcTeamMembers := make(map[string]*team_proto.TeamMember)
+	//event-service seeds attendance from these, suspended and inactive members are left out
 	var grpcTeamMembers []*team_proto.TeamMember
 	for _, m := range members {
-		grpcTeamMembers = append(grpcTeamMembers, &team_proto.TeamMember{
+		if m.Status != models.MemberStatusActive {
+			continue
+		}
+		grpcTeamMembers = append(grpcTeamMembers, toProtoMember(m))
+	}
+	return &team_proto.GetTeamSummaryResponse{
+		Members:        grpcTeamMembers,
+		Name:           team.Name,
+		Sport:          team.Sport,
+		Description:    team.Description,
+		LogoUrl:        team.LogoURL,
+		PrimaryColor:   team.PrimaryColor,
+		SecondaryColor: team.SecondaryColor,
+		HomeVenue:      team.HomeVenue,
+		FoundedYear:    int32(team.FoundedYear),
+		SocialLinks:    team.SocialLinks,
+		OrganizationId: team.OrganizationID.String(),
+	}, nil
+
+}
+
+// CheckPermission answers whether a user holds a named permission on a team,
+// other services call this instead of comparing role strings
+func (s *Server) CheckPermission(ctx context.Context, req *team_proto.CheckPermissionRequest) (*team_proto.CheckPermissionResponse, error) {
+
+	teamID, err := uuid.Parse(req.TeamId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid team id: %v", err)
+	}
+
+	userID, err := uuid.Parse(req.UserId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid user id: %v", err)
+	}
+
+	if !permissions.IsValid(req.Permission) {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown permission %q", req.Permission)
+	}
+
+	allowed, role, err := s.Service.HasPermission(ctx, teamID, userID, req.Permission)
+	if err != nil {
+		s.Logger.Printf("permission check failed: %v", err)
+		return nil, status.Error(codes.Internal, "permission check failed")
+	}
+
+	return &team_proto.CheckPermissionResponse{Allowed: allowed, Role: role}, nil
+}
+
+// GetOrganizationTeams lists the teams of an organization so other services can filter by club
+func (s *Server) GetOrganizationTeams(ctx context.Context, req *team_proto.GetOrganizationTeamsRequest) (*team_proto.GetOrganizationTeamsResponse, error) {
+
+	orgID, err := uuid.Parse(req.OrganizationId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid organization id: %v", err)
+	}
+
+	org, err := s.Service.GetOrganizationSummary(ctx, orgID)
+	if err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			return nil, status.Errorf(codes.NotFound, "organization %s not found", orgID)
+		}
+		s.Logger.Printf("organization teams lookup failed: %v", err)
+		return nil, status.Error(codes.Internal, "organization teams lookup failed")
+	}
+
+	teams := make([]*team_proto.OrganizationTeam, 0, len(org.Teams))
+	for _, t := range org.Teams {
+		teams = append(teams, &team_proto.OrganizationTeam{
+			TeamId: t.TeamID.String(),
+			Name:   t.Name,
+			Sport:  t.Sport,
+		})
+	}
+
+	return &team_proto.GetOrganizationTeamsResponse{
+		OrganizationId: org.OrgID.String(),
+		Name:           org.Name,
+		Teams:          teams,
+	}, nil
+}
+
+// GetRosterOnDate answers who was on a team's roster on a day (YYYY-MM-DD, defaults to today)
+func (s *Server) GetRosterOnDate(ctx context.Context, req *team_proto.GetRosterOnDateRequest) (*team_proto.GetRosterOnDateResponse, error) {
+
+	teamID, err := uuid.Parse(req.TeamId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid team id: %v", err)
+	}
+
+	day := time.Now().UTC()
+	if req.Date != "" {
+		day, err = time.Parse(models.SeasonDateLayout, req.Date)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid date %q, expected YYYY-MM-DD", req.Date)
+		}
+	}
+
+	roster, err := s.Service.RosterOnDate(ctx, teamID, day)
+	if err != nil {
+		s.Logger.Printf("roster on date lookup failed: %v", err)
+		return nil, status.Error(codes.Internal, "roster lookup failed")
+	}
+
+	res := &team_proto.GetRosterOnDateResponse{TeamId: teamID.String()}
+	if roster.Season != nil {
+		res.SeasonId = roster.Season.SeasonID.String()
+		res.SeasonName = roster.Season.Name
+	}
+	for _, m := range roster.Members {
+		member := &team_proto.TeamMember{
 			UserId: m.UserID.String(),
-			TeamId: m.TeamID.String(),
+			TeamId: teamID.String(),
 			Role:   m.Role,
-		})
+		}
+		if m.JerseyNumber != nil {
+			member.JerseyNumber = int32(*m.JerseyNumber)
+		}
+		res.Members = append(res.Members, member)
 	}
-	return &team_proto.GetTeamSummaryResponse{Members: grpcTeamMembers}, nil
+	return res, nil
+}
+
+// GetUserTeams lists the active teams of a user, for calendars and feeds spanning all of them
+func (s *Server) GetUserTeams(ctx context.Context, req *team_proto.GetUserTeamsRequest) (*team_proto.GetUserTeamsResponse, error) {
 
+	userID, err := uuid.Parse(req.UserId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid user id: %v", err)
+	}
+
+	teams, err := s.Service.UserTeams(ctx, userID)
+	if err != nil {
+		s.Logger.Printf("user teams lookup failed: %v", err)
+		return nil, status.Error(codes.Internal, "user teams lookup failed")
+	}
+
+	res := &team_proto.GetUserTeamsResponse{UserId: userID.String()}
+	for _, t := range teams {
+		res.Teams = append(res.Teams, &team_proto.UserTeam{
+			TeamId: t.TeamID.String(),
+			Name:   t.Name,
+			Sport:  t.Sport,
+			Role:   t.Role,
+		})
+	}
+	return res, nil
 }
@@ -0,0 +1,140 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	writeWait  = 10 * time.Second
+	pongWait   = 60 * time.Second
+	pingPeriod = (pongWait * 9) / 10
+	// largest frame a client may send, a 4000 character message plus the envelope
+	maxFrameSize = 32 * 1024
+	sendBuffer   = 64
+)
+
+// Client is one WebSocket connection of a member to a team channel
+type Client struct {
+	hub    *Hub
+	conn   *websocket.Conn
+	teamID uuid.UUID
+	userID uuid.UUID
+
+	// only touched by the read loop
+	lastTyping time.Time
+
+	mu     sync.Mutex
+	closed bool
+	send   chan []byte
+}
+
+func newClient(h *Hub, conn *websocket.Conn, teamID uuid.UUID, userID uuid.UUID) *Client {
+	return &Client{
+		hub:    h,
+		conn:   conn,
+		teamID: teamID,
+		userID: userID,
+		send:   make(chan []byte, sendBuffer),
+	}
+}
+
+// trySend queues data without blocking, false when the client's buffer is full
+func (c *Client) trySend(data []byte) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return true
+	}
+	select {
+	case c.send <- data:
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *Client) sendFrame(f Frame) {
+	data, err := json.Marshal(f)
+	if err != nil {
+		return
+	}
+	if !c.trySend(data) {
+		c.close()
+	}
+}
+
+// close stops the write loop, which closes the connection, safe to call more than once
+func (c *Client) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.closed {
+		c.closed = true
+		close(c.send)
+	}
+}
+
+// Serve runs an upgraded connection of userID to the team channel until either side closes it
+func (h *Hub) Serve(ctx context.Context, conn *websocket.Conn, teamID uuid.UUID, userID uuid.UUID) {
+	c := newClient(h, conn, teamID, userID)
+
+	go c.writeLoop()
+
+	h.register(ctx, c)
+	defer h.unregister(ctx, c)
+
+	c.readLoop(ctx)
+}
+
+func (c *Client) readLoop(ctx context.Context) {
+	c.conn.SetReadLimit(maxFrameSize)
+	c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure) {
+				c.hub.l.Printf("chat connection of %s to team %s closed: %v", c.userID, c.teamID, err)
+			}
+			return
+		}
+		c.hub.handleInbound(ctx, c, data)
+	}
+}
+
+// writeLoop is the only writer of the connection, it pings the client and closes the connection
+// once the send channel is closed
+func (c *Client) writeLoop() {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.conn.Close()
+	}()
+
+	for {
+		select {
+		case data, ok := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if !ok {
+				c.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+				return
+			}
+			if err := c.conn.WriteMessage(websocket.TextMessage, data); err != nil {
+				return
+			}
+		case <-ticker.C:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
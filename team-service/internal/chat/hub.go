@@ -0,0 +1,413 @@
+// Package chat runs the team channels: WebSocket clients connected to this replica, and the
+// frames every replica shares through a Bus so members see each other across replicas
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"github/wycliff-ochieng/internal/models"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// frame types, heartbeat only travels between replicas
+const (
+	FrameMessage   = "message"
+	FrameTyping    = "typing"
+	FramePresence  = "presence"
+	FrameOnline    = "online"
+	FrameError     = "error"
+	FrameHeartbeat = "heartbeat"
+)
+
+// presence statuses
+const (
+	PresenceOnline  = "online"
+	PresenceOffline = "offline"
+)
+
+const (
+	// every replica re-announces its connected users this often
+	heartbeatInterval = 30 * time.Second
+	// users of a replica that stopped announcing them are dropped after this
+	presenceTTL = 3 * heartbeatInterval
+	// typing frames of one connection are forwarded at most this often
+	typingInterval = 2 * time.Second
+)
+
+// Frame is what the hub sends to clients and shares between replicas. Online is the snapshot of
+// online members sent on connect, Replica the replica that published the frame
+type Frame struct {
+	Type    string              `json:"type"`
+	TeamID  uuid.UUID           `json:"teamid"`
+	UserID  uuid.UUID           `json:"userid"`
+	Status  string              `json:"status,omitempty"`
+	Online  []uuid.UUID         `json:"online,omitempty"`
+	Message *models.ChatMessage `json:"message,omitempty"`
+	Error   string              `json:"error,omitempty"`
+	Replica string              `json:"replica,omitempty"`
+}
+
+// inbound is what clients send, a message or a typing notice
+type inbound struct {
+	Type     string `json:"type"`
+	Body     string `json:"body"`
+	ClientID string `json:"clientid"`
+}
+
+// Store persists messages and checks membership, implemented by service.TeamService
+type Store interface {
+	IsTeamMember(ctx context.Context, userID uuid.UUID, teamID uuid.UUID) (bool, error)
+	SaveChatMessage(ctx context.Context, teamID uuid.UUID, userID uuid.UUID, body string, clientID string) (*models.ChatMessage, error)
+}
+
+// Bus carries frames to every replica, including the one that published them
+type Bus interface {
+	Publish(ctx context.Context, teamID uuid.UUID, frame []byte) error
+}
+
+type Hub struct {
+	l       *log.Logger
+	store   Store
+	bus     Bus
+	replica string
+
+	mu    sync.Mutex
+	rooms map[uuid.UUID]map[*Client]struct{}
+	// team -> user -> replica -> last heartbeat, for users connected to other replicas
+	remote map[uuid.UUID]map[uuid.UUID]map[string]time.Time
+}
+
+func NewHub(l *log.Logger, store Store, bus Bus, replica string) *Hub {
+	return &Hub{
+		l:       l,
+		store:   store,
+		bus:     bus,
+		replica: replica,
+		rooms:   map[uuid.UUID]map[*Client]struct{}{},
+		remote:  map[uuid.UUID]map[uuid.UUID]map[string]time.Time{},
+	}
+}
+
+// Run sends the presence heartbeats and drops members that left the team until ctx is cancelled,
+// then disconnects every client
+func (h *Hub) Run(ctx context.Context) {
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			h.closeAll()
+			return
+		case <-ticker.C:
+			h.heartbeat(ctx)
+		}
+	}
+}
+
+// register adds c to its team room, sends it the online members and announces the user when
+// this is their first connection to the team on this replica
+func (h *Hub) register(ctx context.Context, c *Client) {
+	h.mu.Lock()
+	room, ok := h.rooms[c.teamID]
+	if !ok {
+		room = map[*Client]struct{}{}
+		h.rooms[c.teamID] = room
+	}
+	first := !h.hasLocalLocked(c.teamID, c.userID)
+	room[c] = struct{}{}
+	online := h.onlineLocked(c.teamID, time.Now())
+	h.mu.Unlock()
+
+	c.sendFrame(Frame{Type: FrameOnline, TeamID: c.teamID, UserID: c.userID, Online: online})
+
+	if first {
+		h.publish(ctx, Frame{Type: FramePresence, TeamID: c.teamID, UserID: c.userID, Status: PresenceOnline})
+	}
+}
+
+// unregister removes c, the user is announced offline once their last connection here is gone
+func (h *Hub) unregister(ctx context.Context, c *Client) {
+	h.mu.Lock()
+	room := h.rooms[c.teamID]
+	if _, ok := room[c]; !ok {
+		h.mu.Unlock()
+		return
+	}
+	delete(room, c)
+	if len(room) == 0 {
+		delete(h.rooms, c.teamID)
+	}
+	last := !h.hasLocalLocked(c.teamID, c.userID)
+	h.mu.Unlock()
+
+	c.close()
+
+	if last {
+		h.publish(ctx, Frame{Type: FramePresence, TeamID: c.teamID, UserID: c.userID, Status: PresenceOffline})
+	}
+}
+
+// handleInbound acts on one client frame, problems are reported to that client only
+func (h *Hub) handleInbound(ctx context.Context, c *Client, data []byte) {
+	var in inbound
+	if err := json.Unmarshal(data, &in); err != nil {
+		c.sendFrame(Frame{Type: FrameError, TeamID: c.teamID, UserID: c.userID, Error: "invalid frame"})
+		return
+	}
+
+	switch in.Type {
+	case FrameMessage:
+		opCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		message, err := h.store.SaveChatMessage(opCtx, c.teamID, c.userID, in.Body, in.ClientID)
+		cancel()
+		if err != nil {
+			h.l.Printf("chat message of %s to team %s rejected: %v", c.userID, c.teamID, err)
+			c.sendFrame(Frame{Type: FrameError, TeamID: c.teamID, UserID: c.userID, Error: "message not sent"})
+			return
+		}
+		h.publish(ctx, Frame{Type: FrameMessage, TeamID: c.teamID, UserID: c.userID, Message: message})
+
+	case FrameTyping:
+		now := time.Now()
+		if now.Sub(c.lastTyping) < typingInterval {
+			return
+		}
+		c.lastTyping = now
+		h.publish(ctx, Frame{Type: FrameTyping, TeamID: c.teamID, UserID: c.userID})
+
+	default:
+		c.sendFrame(Frame{Type: FrameError, TeamID: c.teamID, UserID: c.userID, Error: "unknown frame type"})
+	}
+}
+
+// publish puts f on the bus, when the bus is down the frame still reaches this replica's clients
+func (h *Hub) publish(ctx context.Context, f Frame) {
+	f.Replica = h.replica
+	data, err := json.Marshal(f)
+	if err != nil {
+		h.l.Printf("error encoding chat frame: %v", err)
+		return
+	}
+	if err := h.bus.Publish(ctx, f.TeamID, data); err != nil {
+		h.l.Printf("error publishing chat %s frame, delivering locally: %v", f.Type, err)
+		h.Deliver(data)
+	}
+}
+
+// Deliver hands a frame read from the bus to the clients of its team on this replica
+func (h *Hub) Deliver(data []byte) {
+	var f Frame
+	if err := json.Unmarshal(data, &f); err != nil {
+		h.l.Printf("skipping invalid chat frame: %v", err)
+		return
+	}
+
+	now := time.Now()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	switch f.Type {
+	case FramePresence, FrameHeartbeat:
+		before := h.isOnlineLocked(f.TeamID, f.UserID, now)
+		if f.Replica != h.replica {
+			h.setRemoteLocked(f.TeamID, f.UserID, f.Replica, f.Status != PresenceOffline, now)
+		}
+		after := h.isOnlineLocked(f.TeamID, f.UserID, now)
+
+		//clients see the user's status over all replicas, heartbeats only when it changed
+		if f.Type == FrameHeartbeat && before == after {
+			return
+		}
+		status := PresenceOffline
+		if after {
+			status = PresenceOnline
+		}
+		h.broadcastLocked(Frame{Type: FramePresence, TeamID: f.TeamID, UserID: f.UserID, Status: status}, uuid.Nil)
+
+	case FrameTyping:
+		h.broadcastLocked(Frame{Type: FrameTyping, TeamID: f.TeamID, UserID: f.UserID}, f.UserID)
+
+	case FrameMessage:
+		f.Replica = ""
+		h.broadcastLocked(f, uuid.Nil)
+	}
+}
+
+// broadcastLocked sends f to the team's clients except those of skipUser. A client too slow to
+// keep up is closed rather than holding up the room, its connection then unregisters it
+func (h *Hub) broadcastLocked(f Frame, skipUser uuid.UUID) {
+	room := h.rooms[f.TeamID]
+	if len(room) == 0 {
+		return
+	}
+
+	data, err := json.Marshal(f)
+	if err != nil {
+		h.l.Printf("error encoding chat frame: %v", err)
+		return
+	}
+
+	for c := range room {
+		if c.userID == skipUser {
+			continue
+		}
+		if !c.trySend(data) {
+			h.l.Printf("chat client %s of team %s is too slow, disconnecting", c.userID, c.teamID)
+			c.close()
+		}
+	}
+}
+
+// heartbeat re-announces the local users, expires users of silent replicas and disconnects
+// clients whose user is no longer on the team
+func (h *Hub) heartbeat(ctx context.Context) {
+	type member struct{ teamID, userID uuid.UUID }
+
+	h.mu.Lock()
+	local := map[member]struct{}{}
+	for teamID, room := range h.rooms {
+		for c := range room {
+			local[member{teamID, c.userID}] = struct{}{}
+		}
+	}
+
+	now := time.Now()
+	for teamID, users := range h.remote {
+		for userID, replicas := range users {
+			for replica, seen := range replicas {
+				if now.Sub(seen) > presenceTTL {
+					delete(replicas, replica)
+				}
+			}
+			if len(replicas) == 0 {
+				delete(users, userID)
+				if !h.hasLocalLocked(teamID, userID) {
+					h.broadcastLocked(Frame{Type: FramePresence, TeamID: teamID, UserID: userID, Status: PresenceOffline}, uuid.Nil)
+				}
+			}
+		}
+		if len(users) == 0 {
+			delete(h.remote, teamID)
+		}
+	}
+	h.mu.Unlock()
+
+	for m := range local {
+		opCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		isMember, err := h.store.IsTeamMember(opCtx, m.userID, m.teamID)
+		cancel()
+		if err != nil {
+			h.l.Printf("error checking chat membership of %s on team %s: %v", m.userID, m.teamID, err)
+			continue
+		}
+		if !isMember {
+			h.disconnect(ctx, m.teamID, m.userID)
+			continue
+		}
+		h.publish(ctx, Frame{Type: FrameHeartbeat, TeamID: m.teamID, UserID: m.userID, Status: PresenceOnline})
+	}
+}
+
+// disconnect closes every local connection of the user to the team
+func (h *Hub) disconnect(ctx context.Context, teamID uuid.UUID, userID uuid.UUID) {
+	h.mu.Lock()
+	var clients []*Client
+	for c := range h.rooms[teamID] {
+		if c.userID == userID {
+			clients = append(clients, c)
+		}
+	}
+	h.mu.Unlock()
+
+	for _, c := range clients {
+		h.unregister(ctx, c)
+	}
+}
+
+func (h *Hub) closeAll() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for teamID, room := range h.rooms {
+		for c := range room {
+			c.close()
+		}
+		delete(h.rooms, teamID)
+	}
+}
+
+func (h *Hub) hasLocalLocked(teamID uuid.UUID, userID uuid.UUID) bool {
+	for c := range h.rooms[teamID] {
+		if c.userID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *Hub) isOnlineLocked(teamID uuid.UUID, userID uuid.UUID, now time.Time) bool {
+	if h.hasLocalLocked(teamID, userID) {
+		return true
+	}
+	for _, seen := range h.remote[teamID][userID] {
+		if now.Sub(seen) <= presenceTTL {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *Hub) setRemoteLocked(teamID uuid.UUID, userID uuid.UUID, replica string, online bool, now time.Time) {
+	users, ok := h.remote[teamID]
+	if !ok {
+		if !online {
+			return
+		}
+		users = map[uuid.UUID]map[string]time.Time{}
+		h.remote[teamID] = users
+	}
+	replicas, ok := users[userID]
+	if !ok {
+		if !online {
+			return
+		}
+		replicas = map[string]time.Time{}
+		users[userID] = replicas
+	}
+
+	if online {
+		replicas[replica] = now
+		return
+	}
+	delete(replicas, replica)
+	if len(replicas) == 0 {
+		delete(users, userID)
+	}
+	if len(users) == 0 {
+		delete(h.remote, teamID)
+	}
+}
+
+// onlineLocked lists the team's online members over all replicas
+func (h *Hub) onlineLocked(teamID uuid.UUID, now time.Time) []uuid.UUID {
+	seen := map[uuid.UUID]bool{}
+	online := make([]uuid.UUID, 0)
+	for c := range h.rooms[teamID] {
+		if !seen[c.userID] {
+			seen[c.userID] = true
+			online = append(online, c.userID)
+		}
+	}
+	for userID := range h.remote[teamID] {
+		if !seen[userID] && h.isOnlineLocked(teamID, userID, now) {
+			seen[userID] = true
+			online = append(online, userID)
+		}
+	}
+	return online
+}
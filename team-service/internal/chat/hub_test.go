@@ -0,0 +1,165 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github/wycliff-ochieng/internal/models"
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type fakeStore struct {
+	members map[uuid.UUID]bool
+}
+
+func (s *fakeStore) IsTeamMember(ctx context.Context, userID uuid.UUID, teamID uuid.UUID) (bool, error) {
+	return s.members[userID], nil
+}
+
+func (s *fakeStore) SaveChatMessage(ctx context.Context, teamID uuid.UUID, userID uuid.UUID, body string, clientID string) (*models.ChatMessage, error) {
+	if !s.members[userID] {
+		return nil, errors.New("not a member")
+	}
+	return &models.ChatMessage{MessageID: uuid.New(), TeamID: teamID, UserID: userID, Body: body, ClientID: clientID, Createdat: time.Now()}, nil
+}
+
+// sharedBus delivers every frame to every hub, like the chat topic does for the replicas
+type sharedBus struct {
+	hubs []*Hub
+}
+
+func (b *sharedBus) Publish(ctx context.Context, teamID uuid.UUID, frame []byte) error {
+	for _, h := range b.hubs {
+		h.Deliver(frame)
+	}
+	return nil
+}
+
+func replicas(store Store) (*Hub, *Hub) {
+	bus := &sharedBus{}
+	l := log.New(io.Discard, "", 0)
+	a := NewHub(l, store, bus, "replica-a")
+	b := NewHub(l, store, bus, "replica-b")
+	bus.hubs = []*Hub{a, b}
+	return a, b
+}
+
+// frames drains what the client was sent so far
+func frames(t *testing.T, c *Client) []Frame {
+	t.Helper()
+	var out []Frame
+	for {
+		select {
+		case data := <-c.send:
+			var f Frame
+			if err := json.Unmarshal(data, &f); err != nil {
+				t.Fatal(err)
+			}
+			out = append(out, f)
+		default:
+			return out
+		}
+	}
+}
+
+func TestMessagesAndPresenceReachOtherReplicas(t *testing.T) {
+	ctx := context.Background()
+	teamID := uuid.New()
+	coach, player := uuid.New(), uuid.New()
+	a, b := replicas(&fakeStore{members: map[uuid.UUID]bool{coach: true, player: true}})
+
+	coachConn := newClient(a, nil, teamID, coach)
+	a.register(ctx, coachConn)
+	frames(t, coachConn)
+
+	playerConn := newClient(b, nil, teamID, player)
+	b.register(ctx, playerConn)
+
+	//the newcomer sees who is already online on the other replica
+	snapshot := frames(t, playerConn)
+	if len(snapshot) == 0 || snapshot[0].Type != FrameOnline || len(snapshot[0].Online) != 2 {
+		t.Fatalf("snapshot = %+v, want both members online", snapshot)
+	}
+	got := frames(t, coachConn)
+	if len(got) != 1 || got[0].Type != FramePresence || got[0].UserID != player || got[0].Status != PresenceOnline {
+		t.Fatalf("coach got %+v, want the player online", got)
+	}
+
+	a.handleInbound(ctx, coachConn, []byte(`{"type":"message","body":"training moved to 6pm","clientid":"c-1"}`))
+	for _, c := range []*Client{coachConn, playerConn} {
+		got := frames(t, c)
+		if len(got) != 1 || got[0].Type != FrameMessage || got[0].Message.Body != "training moved to 6pm" {
+			t.Fatalf("client %s got %+v, want the message", c.userID, got)
+		}
+		if got[0].Replica != "" {
+			t.Errorf("replica leaked to the client: %q", got[0].Replica)
+		}
+	}
+
+	b.unregister(ctx, playerConn)
+	got = frames(t, coachConn)
+	if len(got) != 1 || got[0].Type != FramePresence || got[0].Status != PresenceOffline {
+		t.Fatalf("coach got %+v, want the player offline", got)
+	}
+}
+
+func TestTypingIsThrottledAndNotEchoed(t *testing.T) {
+	ctx := context.Background()
+	teamID := uuid.New()
+	coach, player := uuid.New(), uuid.New()
+	a, b := replicas(&fakeStore{members: map[uuid.UUID]bool{coach: true, player: true}})
+
+	coachConn := newClient(a, nil, teamID, coach)
+	playerConn := newClient(b, nil, teamID, player)
+	a.register(ctx, coachConn)
+	b.register(ctx, playerConn)
+	frames(t, coachConn)
+	frames(t, playerConn)
+
+	b.handleInbound(ctx, playerConn, []byte(`{"type":"typing"}`))
+	b.handleInbound(ctx, playerConn, []byte(`{"type":"typing"}`))
+
+	if got := frames(t, coachConn); len(got) != 1 || got[0].Type != FrameTyping || got[0].UserID != player {
+		t.Errorf("coach got %+v, want one typing notice", got)
+	}
+	if got := frames(t, playerConn); len(got) != 0 {
+		t.Errorf("typing echoed to its sender: %+v", got)
+	}
+}
+
+func TestRejectedMessageOnlyAnswersTheSender(t *testing.T) {
+	ctx := context.Background()
+	teamID := uuid.New()
+	coach, removed := uuid.New(), uuid.New()
+	store := &fakeStore{members: map[uuid.UUID]bool{coach: true, removed: true}}
+	a, _ := replicas(store)
+
+	coachConn := newClient(a, nil, teamID, coach)
+	removedConn := newClient(a, nil, teamID, removed)
+	a.register(ctx, coachConn)
+	a.register(ctx, removedConn)
+	frames(t, coachConn)
+	frames(t, removedConn)
+
+	//removed from the team while connected
+	store.members[removed] = false
+	a.handleInbound(ctx, removedConn, []byte(`{"type":"message","body":"still here?"}`))
+
+	if got := frames(t, removedConn); len(got) != 1 || got[0].Type != FrameError {
+		t.Errorf("sender got %+v, want an error", got)
+	}
+	if got := frames(t, coachConn); len(got) != 0 {
+		t.Errorf("rejected message reached the team: %+v", got)
+	}
+
+	//the next heartbeat disconnects them
+	a.heartbeat(ctx)
+	if _, open := <-removedConn.send; open {
+		t.Error("removed member is still connected")
+	}
+}
@@ -0,0 +1,99 @@
+package chat
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/google/uuid"
+)
+
+// KafkaBus fans chat frames out to every replica over one topic, keyed by team so the frames of a
+// team keep their order
+type KafkaBus struct {
+	l          *log.Logger
+	producer   *kafka.Producer
+	topic      string
+	deliverych chan kafka.Event
+}
+
+func NewKafkaBus(l *log.Logger, p *kafka.Producer, topic string) *KafkaBus {
+	b := &KafkaBus{
+		l:          l,
+		producer:   p,
+		topic:      topic,
+		deliverych: make(chan kafka.Event, 1000),
+	}
+	//chat is chatty, delivery reports are drained so they never back up the producer
+	go b.drainDeliveries()
+	return b
+}
+
+func (b *KafkaBus) Publish(ctx context.Context, teamID uuid.UUID, frame []byte) error {
+	return b.producer.Produce(&kafka.Message{
+		TopicPartition: kafka.TopicPartition{
+			Topic:     &b.topic,
+			Partition: kafka.PartitionAny,
+		},
+		Key:   []byte(teamID.String()),
+		Value: frame,
+	}, b.deliverych)
+}
+
+func (b *KafkaBus) drainDeliveries() {
+	for e := range b.deliverych {
+		if m, ok := e.(*kafka.Message); ok && m.TopicPartition.Error != nil {
+			b.l.Printf("chat frame not delivered: %v", m.TopicPartition.Error)
+		}
+	}
+}
+
+// Listen hands every frame on the topic to the hub until ctx is cancelled. Each replica reads
+// the whole topic in a group of its own, starting from the newest frame: history comes from
+// Postgres, the topic only carries what happens while a replica is up
+func (b *KafkaBus) Listen(ctx context.Context, h *Hub, bootstrapServers string, groupID string) error {
+	consumer, err := kafka.NewConsumer(&kafka.ConfigMap{
+		"bootstrap.servers":  bootstrapServers,
+		"group.id":           groupID,
+		"auto.offset.reset":  "latest",
+		"enable.auto.commit": true,
+	})
+	if err != nil {
+		return fmt.Errorf("setting up chat consumer: %w", err)
+	}
+
+	defer func() {
+		if err := consumer.Close(); err != nil {
+			b.l.Printf("error closing chat consumer: %v", err)
+		}
+	}()
+
+	if err := consumer.Subscribe(b.topic, nil); err != nil {
+		return fmt.Errorf("subscribing to topic %s: %w", b.topic, err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			b.l.Println("chat consumer shutting down")
+			return nil
+
+		default:
+			ev := consumer.Poll(100)
+			if ev == nil {
+				continue
+			}
+
+			switch e := ev.(type) {
+			case *kafka.Message:
+				h.Deliver(e.Value)
+			case *kafka.Error:
+				b.l.Printf("Kafka Error: %v(code:%d)", e, e.Code())
+				if e.IsFatal() {
+					return e
+				}
+			}
+		}
+	}
+}
@@ -19,10 +19,23 @@ type Config struct {
 
 	AuthDBName string
 
+	TeamDeleteGraceDays int
+
+	KafkaBroker       string
+	UserEventsTopic   string
+	UserEventsGroupID string
+	ActivityTopics    []string
+	ChatTopic         string
+	ChatReplicaID     string
+
 	JWTSecret          string
 	JWTExpiry          string
 	RefreshSecret      string
 	RefreshExpiry      string
+	MinIOEndpoint      string
+	MinIOAccessKey     string
+	MinIOSecretKey     string
+	MinIOBucket        string
 	CORSAllowedOrigins []string
 }
 
@@ -40,8 +53,19 @@ func Load() (*Config, error) {
 	config.DBUser = getEnv("DB_USER", "admin")
 	config.DBsslmode = getEnv("DB_SSLMODE", "disable")
 	config.AuthDBName = getEnv("AUTH_DB_NAME", "Authentication")
+	config.TeamDeleteGraceDays = getEnvAsInt("TEAM_DELETE_GRACE_DAYS", 30)
+	config.KafkaBroker = getEnv("KAFKA_BROKER", "localhost:9092")
+	config.UserEventsTopic = getEnv("USER_EVENTS_TOPIC", "profile")
+	config.UserEventsGroupID = getEnv("USER_EVENTS_GROUP_ID", "team-service")
+	config.ChatTopic = getEnv("CHAT_TOPIC", "team_chat")
+	config.ChatReplicaID = getEnv("CHAT_REPLICA_ID", hostname())
+	config.ActivityTopics = getEnvAsSlice("ACTIVITY_TOPICS", []string{"team_events", "event_events"}, ",")
 	config.JWTSecret = getEnv("JWT_SECRET", "mydogsnameisrufus")
 	config.RefreshSecret = getEnv("REFRESH_SECRET", "myotherdogiscalledseedolf")
+	config.MinIOEndpoint = getEnv("MINIO_ENDPOINT", "localhost:9000")
+	config.MinIOAccessKey = getEnv("MINIO_ACCESS_KEY", "")
+	config.MinIOSecretKey = getEnv("MINIO_SECRET_KEY", "")
+	config.MinIOBucket = getEnv("MINIO_BUCKET", "sportspro")
 	config.CORSAllowedOrigins = getEnvAsSlice("CORS_ALLOWED_ORIGINS", []string{"http://localhost:5173"}, ",")
 
 	return config, nil
@@ -69,3 +93,12 @@ func getEnvAsSlice(key string, defaultValue []string, separator string) []string
 	}
 	return strings.Split(valueStr, separator)
 }
+
+// hostname tells replicas apart by default, every pod or container has its own
+func hostname() string {
+	name, err := os.Hostname()
+	if err != nil || name == "" {
+		return "team-service"
+	}
+	return name
+}
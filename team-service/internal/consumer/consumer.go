@@ -1 +1,72 @@
 package consumer
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/google/uuid"
+	"github.com/wycliff-ochieng/common_packages/events"
+	"github.com/wycliff-ochieng/common_packages/kafkaconsumer"
+)
+
+var ErrInvalidEvent = kafkaconsumer.ErrInvalidEvent
+
+// EventHandler applies user events to the team data and records team activity, implemented by
+// service.TeamService. The event key (the envelope id) makes every apply idempotent
+type EventHandler interface {
+	ApplyUserProfileUpdated(ctx context.Context, eventKey string, eventType string, userID uuid.UUID, email string) error
+	ApplyUserDeleted(ctx context.Context, eventKey string, eventType string, userID uuid.UUID) error
+	ApplyUserSuspended(ctx context.Context, eventKey string, eventType string, userID uuid.UUID, suspended bool) error
+	RecordActivity(ctx context.Context, env *events.Envelope, payload events.Payload) error
+}
+
+type EventConsumer struct {
+	l        *log.Logger
+	h        EventHandler
+	consumer *kafka.Consumer
+}
+
+func NewEventConsumer(l *log.Logger, h EventHandler, bootstrapServers string, groupID string) (*EventConsumer, error) {
+
+	consumer, err := kafkaconsumer.New(bootstrapServers, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EventConsumer{
+		l:        l,
+		h:        h,
+		consumer: consumer,
+	}, nil
+}
+
+// handle validates and applies one message, errors wrapping ErrInvalidEvent never succeed on retry
+func (c *EventConsumer) handle(ctx context.Context, value []byte) error {
+	env, payload, err := events.Unmarshal(value)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidEvent, err)
+	}
+
+	eventKey := env.ID.String()
+
+	switch e := payload.(type) {
+	case *events.UserProfileUpdated:
+		return c.h.ApplyUserProfileUpdated(ctx, eventKey, env.Type, e.UserID, e.Email)
+	case *events.UserDeleted:
+		return c.h.ApplyUserDeleted(ctx, eventKey, env.Type, e.UserID)
+	case *events.UserSuspended:
+		return c.h.ApplyUserSuspended(ctx, eventKey, env.Type, e.UserID, true)
+	case *events.UserReactivated:
+		return c.h.ApplyUserSuspended(ctx, eventKey, env.Type, e.UserID, false)
+	default:
+		//team and event events feed the activity stream, the rest is not ours to apply
+		return c.h.RecordActivity(ctx, env, payload)
+	}
+}
+
+// StartEventConsumer applies the messages of topics until ctx is cancelled, see kafkaconsumer.Run
+func (c *EventConsumer) StartEventConsumer(ctx context.Context, topics []string) {
+	kafkaconsumer.Run(ctx, c.l, c.consumer, topics, c.handle)
+}
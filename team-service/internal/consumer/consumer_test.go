@@ -0,0 +1,103 @@
+package consumer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/wycliff-ochieng/common_packages/events"
+)
+
+type recordingHandler struct {
+	calls []string
+	keys  []string
+}
+
+func (h *recordingHandler) ApplyUserProfileUpdated(ctx context.Context, eventKey string, eventType string, userID uuid.UUID, email string) error {
+	h.calls = append(h.calls, "profile:"+email)
+	h.keys = append(h.keys, eventKey)
+	return nil
+}
+
+func (h *recordingHandler) ApplyUserDeleted(ctx context.Context, eventKey string, eventType string, userID uuid.UUID) error {
+	h.calls = append(h.calls, "deleted")
+	h.keys = append(h.keys, eventKey)
+	return nil
+}
+
+func (h *recordingHandler) ApplyUserSuspended(ctx context.Context, eventKey string, eventType string, userID uuid.UUID, suspended bool) error {
+	if suspended {
+		h.calls = append(h.calls, "suspended")
+	} else {
+		h.calls = append(h.calls, "reactivated")
+	}
+	h.keys = append(h.keys, eventKey)
+	return nil
+}
+
+func (h *recordingHandler) RecordActivity(ctx context.Context, env *events.Envelope, payload events.Payload) error {
+	h.calls = append(h.calls, "activity:"+env.Type)
+	h.keys = append(h.keys, env.ID.String())
+	return nil
+}
+
+func message(t *testing.T, p events.Payload) []byte {
+	t.Helper()
+	data, err := events.Marshal("user-service", p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestHandleDispatchesByEventType(t *testing.T) {
+	h := &recordingHandler{}
+	c := &EventConsumer{l: log.New(io.Discard, "", 0), h: h}
+	userID := uuid.New()
+
+	messages := [][]byte{
+		message(t, events.UserProfileUpdated{UserID: userID, Email: "a@b.com"}),
+		message(t, events.UserDeleted{UserID: userID}),
+		message(t, events.UserSuspended{UserID: userID}),
+		message(t, events.UserReactivated{UserID: userID}),
+		//everything else is offered to the activity feed
+		message(t, events.TeamRosterChanged{ChangeType: events.RosterMemberAdded, TeamID: uuid.New(), UserID: userID}),
+		message(t, events.UserCreated{UserID: userID, Email: "a@b.com"}),
+	}
+	for _, m := range messages {
+		if err := c.handle(context.Background(), m); err != nil {
+			t.Fatalf("handle(%s): %v", m, err)
+		}
+	}
+
+	wantCalls := []string{"profile:a@b.com", "deleted", "suspended", "reactivated", "activity:TeamRosterChanged", "activity:UserCreated"}
+	if len(h.calls) != len(wantCalls) {
+		t.Fatalf("calls = %v, want %v", h.calls, wantCalls)
+	}
+	for i := range wantCalls {
+		if h.calls[i] != wantCalls[i] {
+			t.Errorf("call %d = %s, want %s", i, h.calls[i], wantCalls[i])
+		}
+		if _, err := uuid.Parse(h.keys[i]); err != nil {
+			t.Errorf("call %d key %q is not the envelope id", i, h.keys[i])
+		}
+	}
+}
+
+func TestHandleRejectsInvalidEvents(t *testing.T) {
+	c := &EventConsumer{l: log.New(io.Discard, "", 0), h: &recordingHandler{}}
+
+	invalid := []string{
+		//what user-service published before the shared envelope
+		`"` + uuid.New().String() + `"`,
+		`{"eventType":"UserDeleted","userid":"` + uuid.New().String() + `"}`,
+	}
+	for _, m := range invalid {
+		if err := c.handle(context.Background(), []byte(m)); !errors.Is(err, ErrInvalidEvent) {
+			t.Errorf("handle(%s) = %v, want ErrInvalidEvent", m, err)
+		}
+	}
+}
@@ -13,6 +13,6 @@ CREATE TABLE teams(
 
 -- +goose Down
 -- +goose StatementBegin
-SELECT 'down SQL query';
+DROP TABLE IF EXISTS teams;
 -- +goose StatementEnd
 
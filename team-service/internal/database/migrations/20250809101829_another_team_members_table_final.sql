@@ -8,4 +8,5 @@ CREATE TABLE team_members(
     REFERENCES teams(id)
 );
 
--- +goose Down
\ No newline at end of file
+-- +goose Down
+DROP TABLE IF EXISTS team_members;
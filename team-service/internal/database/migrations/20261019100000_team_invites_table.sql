@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- a user can belong to more than one team, membership is unique per (team, user)
+ALTER TABLE team_members DROP CONSTRAINT team_members_pkey;
+ALTER TABLE team_members ADD PRIMARY KEY (team_id, user_id);
+
+CREATE TABLE team_invites(
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    team_id UUID NOT NULL,
+    invited_by UUID NOT NULL,
+    invitee_user_id UUID NULL, -- targeted invite by uuid
+    invitee_email VARCHAR(255) NULL, -- targeted invite by email
+    code VARCHAR(32) UNIQUE NULL, -- reusable join code/link
+    role VARCHAR(100) NOT NULL DEFAULT 'player',
+    max_uses INT NULL,
+    uses INT NOT NULL DEFAULT 0,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+    expires_at TIMESTAMP NULL,
+    responded_at TIMESTAMP NULL,
+    createdat TIMESTAMP DEFAULT NOW(),
+    updatedat TIMESTAMP DEFAULT NOW(),
+    CONSTRAINT team_invites_team_fk FOREIGN KEY(team_id) REFERENCES teams(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_team_invites_team_id ON team_invites(team_id);
+CREATE INDEX idx_team_invites_invitee_user_id ON team_invites(invitee_user_id);
+CREATE INDEX idx_team_invites_invitee_email ON team_invites(LOWER(invitee_email));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS team_invites;
+ALTER TABLE team_members DROP CONSTRAINT team_members_pkey;
+-- the old key allows one team per user, only the most recently joined membership of each user is
+-- kept, the others are lost
+DELETE FROM team_members WHERE (team_id, user_id) NOT IN (
+    SELECT DISTINCT ON (user_id) team_id, user_id FROM team_members ORDER BY user_id, joinedat DESC NULLS LAST, team_id
+);
+ALTER TABLE team_members ADD PRIMARY KEY (user_id);
+-- +goose StatementEnd
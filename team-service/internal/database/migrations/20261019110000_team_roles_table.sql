@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+-- custom roles per team, the built in coach/manager/player roles live in code
+CREATE TABLE team_roles(
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    team_id UUID NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    permissions TEXT[] NOT NULL DEFAULT '{}',
+    createdat TIMESTAMP DEFAULT NOW(),
+    updatedat TIMESTAMP DEFAULT NOW(),
+    CONSTRAINT team_roles_team_fk FOREIGN KEY(team_id) REFERENCES teams(id) ON DELETE CASCADE,
+    CONSTRAINT team_roles_unique_name UNIQUE(team_id, name)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS team_roles;
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+-- logo_object_key points into the minio bucket, the public url is presigned on read
+ALTER TABLE teams
+    ADD COLUMN logo_object_key TEXT NULL,
+    ADD COLUMN primary_color VARCHAR(7) NULL,
+    ADD COLUMN secondary_color VARCHAR(7) NULL,
+    ADD COLUMN home_venue VARCHAR(200) NULL,
+    ADD COLUMN founded_year INT NULL,
+    ADD COLUMN social_links JSONB NOT NULL DEFAULT '{}';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE teams
+    DROP COLUMN IF EXISTS social_links,
+    DROP COLUMN IF EXISTS founded_year,
+    DROP COLUMN IF EXISTS home_venue,
+    DROP COLUMN IF EXISTS secondary_color,
+    DROP COLUMN IF EXISTS primary_color,
+    DROP COLUMN IF EXISTS logo_object_key;
+-- +goose StatementEnd
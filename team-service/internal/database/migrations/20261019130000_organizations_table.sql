@@ -0,0 +1,40 @@
+-- +goose Up
+-- +goose StatementBegin
+-- clubs own several teams, org admins manage every team under the organization
+CREATE TABLE organizations(
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(200) NOT NULL,
+    description TEXT NULL,
+    created_by UUID NULL,
+    createdat TIMESTAMP DEFAULT NOW(),
+    updatedat TIMESTAMP DEFAULT NOW()
+);
+
+CREATE TABLE organization_admins(
+    organization_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    addedat TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY(organization_id, user_id),
+    CONSTRAINT organization_admins_org_fk FOREIGN KEY(organization_id) REFERENCES organizations(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_organization_admins_user ON organization_admins(user_id);
+
+-- every existing team moves into the default organization
+INSERT INTO organizations(id, name, description)
+VALUES ('00000000-0000-0000-0000-000000000001', 'Default Organization', 'Teams created before organizations existed');
+
+ALTER TABLE teams ADD COLUMN organization_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001';
+ALTER TABLE teams ADD CONSTRAINT teams_organization_fk FOREIGN KEY(organization_id) REFERENCES organizations(id) ON DELETE RESTRICT;
+
+CREATE INDEX idx_teams_organization ON teams(organization_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE teams DROP CONSTRAINT IF EXISTS teams_organization_fk;
+DROP INDEX IF EXISTS idx_teams_organization;
+ALTER TABLE teams DROP COLUMN IF EXISTS organization_id;
+DROP TABLE IF EXISTS organization_admins;
+DROP TABLE IF EXISTS organizations;
+-- +goose StatementEnd
@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE team_seasons(
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    team_id UUID NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'PLANNED',
+    createdat TIMESTAMP DEFAULT NOW(),
+    updatedat TIMESTAMP DEFAULT NOW(),
+    CONSTRAINT team_seasons_team_fk FOREIGN KEY(team_id) REFERENCES teams(id) ON DELETE CASCADE,
+    CONSTRAINT team_seasons_unique_name UNIQUE(team_id, name),
+    CONSTRAINT team_seasons_dates CHECK(end_date >= start_date)
+);
+
+-- at most one running season per team
+CREATE UNIQUE INDEX idx_team_seasons_one_active ON team_seasons(team_id) WHERE status = 'ACTIVE';
+
+-- season_roster keeps who was on the team during a season, leftat closes an entry instead of deleting it
+CREATE TABLE season_roster(
+    season_id UUID NOT NULL,
+    team_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    role VARCHAR(100) NOT NULL,
+    joinedat TIMESTAMP NOT NULL DEFAULT NOW(),
+    leftat TIMESTAMP NULL,
+    PRIMARY KEY(season_id, user_id),
+    CONSTRAINT season_roster_season_fk FOREIGN KEY(season_id) REFERENCES team_seasons(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_season_roster_team ON season_roster(team_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS season_roster;
+DROP TABLE IF EXISTS team_seasons;
+-- +goose StatementEnd
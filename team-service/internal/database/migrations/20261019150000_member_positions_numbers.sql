@@ -0,0 +1,81 @@
+-- +goose Up
+-- +goose StatementBegin
+-- positions offered per sport, teams whose sport has no rows here may use free text positions
+CREATE TABLE sport_positions(
+    sport VARCHAR(100) NOT NULL,
+    code VARCHAR(20) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    sort_order INT NOT NULL DEFAULT 0,
+    PRIMARY KEY(sport, code)
+);
+
+INSERT INTO sport_positions(sport, code, name, sort_order) VALUES
+    ('football', 'GK', 'Goalkeeper', 1),
+    ('football', 'RB', 'Right Back', 2),
+    ('football', 'CB', 'Centre Back', 3),
+    ('football', 'LB', 'Left Back', 4),
+    ('football', 'DM', 'Defensive Midfielder', 5),
+    ('football', 'CM', 'Central Midfielder', 6),
+    ('football', 'AM', 'Attacking Midfielder', 7),
+    ('football', 'RW', 'Right Winger', 8),
+    ('football', 'LW', 'Left Winger', 9),
+    ('football', 'ST', 'Striker', 10),
+    ('soccer', 'GK', 'Goalkeeper', 1),
+    ('soccer', 'RB', 'Right Back', 2),
+    ('soccer', 'CB', 'Centre Back', 3),
+    ('soccer', 'LB', 'Left Back', 4),
+    ('soccer', 'DM', 'Defensive Midfielder', 5),
+    ('soccer', 'CM', 'Central Midfielder', 6),
+    ('soccer', 'AM', 'Attacking Midfielder', 7),
+    ('soccer', 'RW', 'Right Winger', 8),
+    ('soccer', 'LW', 'Left Winger', 9),
+    ('soccer', 'ST', 'Striker', 10),
+    ('basketball', 'PG', 'Point Guard', 1),
+    ('basketball', 'SG', 'Shooting Guard', 2),
+    ('basketball', 'SF', 'Small Forward', 3),
+    ('basketball', 'PF', 'Power Forward', 4),
+    ('basketball', 'C', 'Center', 5),
+    ('rugby', 'LP', 'Loosehead Prop', 1),
+    ('rugby', 'HK', 'Hooker', 2),
+    ('rugby', 'TP', 'Tighthead Prop', 3),
+    ('rugby', 'LK', 'Lock', 4),
+    ('rugby', 'FL', 'Flanker', 5),
+    ('rugby', 'N8', 'Number Eight', 6),
+    ('rugby', 'SH', 'Scrum Half', 7),
+    ('rugby', 'FH', 'Fly Half', 8),
+    ('rugby', 'CE', 'Centre', 9),
+    ('rugby', 'WG', 'Wing', 10),
+    ('rugby', 'FB', 'Full Back', 11),
+    ('volleyball', 'S', 'Setter', 1),
+    ('volleyball', 'OH', 'Outside Hitter', 2),
+    ('volleyball', 'MB', 'Middle Blocker', 3),
+    ('volleyball', 'OPP', 'Opposite', 4),
+    ('volleyball', 'L', 'Libero', 5),
+    ('volleyball', 'DS', 'Defensive Specialist', 6);
+
+ALTER TABLE team_members
+    ADD COLUMN jersey_number INT NULL CHECK (jersey_number BETWEEN 0 AND 999),
+    ADD COLUMN primary_position VARCHAR(20) NULL,
+    ADD COLUMN secondary_position VARCHAR(20) NULL,
+    ADD COLUMN depth_order INT NULL;
+
+-- squad numbers are unique per team and, among players still on it, per season
+CREATE UNIQUE INDEX idx_team_members_jersey ON team_members(team_id, jersey_number) WHERE jersey_number IS NOT NULL;
+
+ALTER TABLE season_roster ADD COLUMN jersey_number INT NULL;
+
+CREATE UNIQUE INDEX idx_season_roster_jersey ON season_roster(season_id, jersey_number) WHERE jersey_number IS NOT NULL AND leftat IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_season_roster_jersey;
+ALTER TABLE season_roster DROP COLUMN IF EXISTS jersey_number;
+DROP INDEX IF EXISTS idx_team_members_jersey;
+ALTER TABLE team_members
+    DROP COLUMN IF EXISTS depth_order,
+    DROP COLUMN IF EXISTS secondary_position,
+    DROP COLUMN IF EXISTS primary_position,
+    DROP COLUMN IF EXISTS jersey_number;
+DROP TABLE IF EXISTS sport_positions;
+-- +goose StatementEnd
@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE teams ADD COLUMN visibility VARCHAR(10) NOT NULL DEFAULT 'private';
+ALTER TABLE teams ADD CONSTRAINT teams_visibility_check CHECK (visibility IN ('public', 'private'));
+
+-- team listing pages on (createdat, id), old rows without a timestamp would break the cursor
+UPDATE teams SET createdat = NOW() WHERE createdat IS NULL;
+ALTER TABLE teams ALTER COLUMN createdat SET NOT NULL;
+
+CREATE INDEX idx_teams_createdat_id ON teams(createdat DESC, id DESC);
+CREATE INDEX idx_teams_sports ON teams(LOWER(sports));
+
+-- users asking to join a public team, coaches approve or reject
+CREATE TABLE team_join_requests(
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    team_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    message VARCHAR(500) NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+    decided_by UUID NULL,
+    decided_at TIMESTAMP NULL,
+    createdat TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT team_join_requests_team_fk FOREIGN KEY(team_id) REFERENCES teams(id) ON DELETE CASCADE
+);
+
+-- one open request per user and team
+CREATE UNIQUE INDEX idx_team_join_requests_pending ON team_join_requests(team_id, user_id) WHERE status = 'PENDING';
+CREATE INDEX idx_team_join_requests_user ON team_join_requests(user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS team_join_requests;
+DROP INDEX IF EXISTS idx_teams_sports;
+DROP INDEX IF EXISTS idx_teams_createdat_id;
+ALTER TABLE teams ALTER COLUMN createdat DROP NOT NULL;
+ALTER TABLE teams DROP CONSTRAINT IF EXISTS teams_visibility_check;
+ALTER TABLE teams DROP COLUMN IF EXISTS visibility;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE teams ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'ACTIVE';
+ALTER TABLE teams ADD CONSTRAINT teams_status_check CHECK (status IN ('ACTIVE', 'ARCHIVED', 'PENDING_DELETION'));
+ALTER TABLE teams ADD COLUMN archived_at TIMESTAMP NULL;
+ALTER TABLE teams ADD COLUMN archived_by UUID NULL;
+-- hard delete runs once delete_after has passed, restoring clears it
+ALTER TABLE teams ADD COLUMN delete_after TIMESTAMP NULL;
+
+CREATE INDEX idx_teams_pending_deletion ON teams(delete_after) WHERE status = 'PENDING_DELETION';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_teams_pending_deletion;
+ALTER TABLE teams DROP COLUMN IF EXISTS delete_after;
+ALTER TABLE teams DROP COLUMN IF EXISTS archived_by;
+ALTER TABLE teams DROP COLUMN IF EXISTS archived_at;
+ALTER TABLE teams DROP CONSTRAINT IF EXISTS teams_status_check;
+ALTER TABLE teams DROP COLUMN IF EXISTS status;
+-- +goose StatementEnd
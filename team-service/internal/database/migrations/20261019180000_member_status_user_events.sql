@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+-- suspended users stay on the roster but lose their permissions until reactivated
+ALTER TABLE team_members ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'ACTIVE';
+ALTER TABLE team_members ADD CONSTRAINT team_members_status_check CHECK (status IN ('ACTIVE', 'INACTIVE'));
+ALTER TABLE team_members ADD COLUMN inactive_since TIMESTAMP NULL;
+
+-- user lifecycle events already applied, redelivered messages are skipped
+CREATE TABLE processed_user_events(
+    event_key VARCHAR(255) PRIMARY KEY,
+    event_type VARCHAR(50) NOT NULL,
+    user_id UUID NOT NULL,
+    processed_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_team_members_user_id ON team_members(user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_team_members_user_id;
+DROP TABLE IF EXISTS processed_user_events;
+ALTER TABLE team_members DROP COLUMN IF EXISTS inactive_since;
+ALTER TABLE team_members DROP CONSTRAINT IF EXISTS team_members_status_check;
+ALTER TABLE team_members DROP COLUMN IF EXISTS status;
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+-- the team feed, one row per domain event about the team. event_id is the envelope id so
+-- redelivered events are recorded once
+CREATE TABLE team_activity(
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    team_id UUID NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    event_id UUID NOT NULL UNIQUE,
+    type VARCHAR(50) NOT NULL,
+    actor_id UUID NULL,
+    subject_id UUID NULL,
+    source VARCHAR(50) NOT NULL,
+    data JSONB NOT NULL DEFAULT '{}'::jsonb,
+    createdat TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_team_activity_team_created ON team_activity(team_id, createdat DESC, id DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_team_activity_team_created;
+DROP TABLE IF EXISTS team_activity;
+-- +goose StatementEnd
@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE team_announcements(
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    team_id UUID NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    author_id UUID NOT NULL,
+    title VARCHAR(200) NOT NULL,
+    body TEXT NOT NULL DEFAULT '',
+    pinned BOOLEAN NOT NULL DEFAULT FALSE,
+    requires_ack BOOLEAN NOT NULL DEFAULT FALSE,
+    expires_at TIMESTAMP NULL,
+    createdat TIMESTAMP NOT NULL DEFAULT NOW(),
+    updatedat TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- one row per member who confirmed reading an announcement
+CREATE TABLE announcement_acknowledgements(
+    announcement_id UUID NOT NULL REFERENCES team_announcements(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    acknowledged_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (announcement_id, user_id)
+);
+
+CREATE INDEX idx_team_announcements_team ON team_announcements(team_id, pinned DESC, createdat DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_team_announcements_team;
+DROP TABLE IF EXISTS announcement_acknowledgements;
+DROP TABLE IF EXISTS team_announcements;
+-- +goose StatementEnd
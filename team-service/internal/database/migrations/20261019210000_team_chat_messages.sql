@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+-- team channel history, client_id lets a reconnecting client resend without duplicating the message
+CREATE TABLE team_chat_messages(
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    team_id UUID NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    body TEXT NOT NULL,
+    client_id VARCHAR(64) NULL,
+    createdat TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT team_chat_messages_client_id_key UNIQUE (team_id, user_id, client_id)
+);
+
+CREATE INDEX idx_team_chat_messages_team_created ON team_chat_messages(team_id, createdat DESC, id DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_team_chat_messages_team_created;
+DROP TABLE IF EXISTS team_chat_messages;
+-- +goose StatementEnd
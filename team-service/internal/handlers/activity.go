@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"encoding/json"
+	"github/wycliff-ochieng/internal/models"
+	"net/http"
+	"strconv"
+	"strings"
+
+	auth "github.com/wycliff-ochieng/sports-common-package/middleware"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// GET :: api/team/{team_id}/activity?type=&limit=&cursor=
+// type can be repeated or comma separated, e.g. type=MEMBER_ADDED,ROLE_CHANGED
+func (h *TeamHandler) GetTeamActivity(w http.ResponseWriter, r *http.Request) {
+	h.l.Println("Fetching team activity")
+
+	ctx := r.Context()
+
+	teamID, err := uuid.Parse(mux.Vars(r)["team_id"])
+	if err != nil {
+		http.Error(w, "invalid team id", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	query := r.URL.Query()
+
+	minLimit := 1
+	maxLimit := 100
+	defaultLimit := 25
+
+	limit := defaultLimit
+	if raw := query.Get("limit"); raw != "" {
+		limit, err = strconv.Atoi(raw)
+		if err != nil || limit < minLimit || limit > maxLimit {
+			http.Error(w, "limit must be between 1 and 100", http.StatusBadRequest)
+			return
+		}
+	}
+
+	params := models.ListActivityParams{
+		Limit:  limit,
+		Cursor: query.Get("cursor"),
+	}
+	for _, raw := range query["type"] {
+		for _, t := range strings.Split(raw, ",") {
+			if t = strings.ToUpper(strings.TrimSpace(t)); t != "" {
+				params.Types = append(params.Types, t)
+			}
+		}
+	}
+
+	activity, err := h.t.ListActivity(ctx, teamID, userID, params)
+	if err != nil {
+		h.l.Printf("list team activity failed due to: %v", err)
+		http.Error(w, "failed to get team activity", serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(&activity)
+}
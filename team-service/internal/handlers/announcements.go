@@ -0,0 +1,227 @@
+package handlers
+
+import (
+	"encoding/json"
+	"github/wycliff-ochieng/internal/models"
+	"net/http"
+
+	auth "github.com/wycliff-ochieng/sports-common-package/middleware"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// POST :: api/team/{team_id}/announcements
+func (h *TeamHandler) PostAnnouncement(w http.ResponseWriter, r *http.Request) {
+	h.l.Println("Posting team announcement")
+
+	ctx := r.Context()
+
+	teamID, err := uuid.Parse(mux.Vars(r)["team_id"])
+	if err != nil {
+		http.Error(w, "invalid team id", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.AnnouncementReq
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "failed to decode announcement request", http.StatusBadRequest)
+		return
+	}
+
+	announcement, err := h.t.PostAnnouncement(ctx, teamID, userID, req)
+	if err != nil {
+		h.l.Printf("post announcement failed due to: %v", err)
+		http.Error(w, "failed to post announcement", serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(&announcement)
+}
+
+// GET :: api/team/{team_id}/announcements?include_expired=true
+func (h *TeamHandler) GetAnnouncements(w http.ResponseWriter, r *http.Request) {
+	h.l.Println("Fetching team announcements")
+
+	ctx := r.Context()
+
+	teamID, err := uuid.Parse(mux.Vars(r)["team_id"])
+	if err != nil {
+		http.Error(w, "invalid team id", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	announcements, err := h.t.ListAnnouncements(ctx, teamID, userID, r.URL.Query().Get("include_expired") == "true")
+	if err != nil {
+		h.l.Printf("list announcements failed due to: %v", err)
+		http.Error(w, "failed to get announcements", serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(&announcements)
+}
+
+// PUT :: api/team/{team_id}/announcements/{announcement_id}/pin
+func (h *TeamHandler) PinAnnouncement(w http.ResponseWriter, r *http.Request) {
+	h.l.Println("Pinning team announcement")
+
+	ctx := r.Context()
+
+	teamID, err := uuid.Parse(mux.Vars(r)["team_id"])
+	if err != nil {
+		http.Error(w, "invalid team id", http.StatusBadRequest)
+		return
+	}
+
+	announcementID, err := uuid.Parse(mux.Vars(r)["announcement_id"])
+	if err != nil {
+		http.Error(w, "invalid announcement id", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.PinAnnouncementReq
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "failed to decode pin request", http.StatusBadRequest)
+		return
+	}
+
+	announcement, err := h.t.PinAnnouncement(ctx, teamID, announcementID, userID, req.Pinned)
+	if err != nil {
+		h.l.Printf("pin announcement failed due to: %v", err)
+		http.Error(w, "failed to pin announcement", serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(&announcement)
+}
+
+// DELETE :: api/team/{team_id}/announcements/{announcement_id}
+func (h *TeamHandler) DeleteAnnouncement(w http.ResponseWriter, r *http.Request) {
+	h.l.Println("Deleting team announcement")
+
+	ctx := r.Context()
+
+	teamID, err := uuid.Parse(mux.Vars(r)["team_id"])
+	if err != nil {
+		http.Error(w, "invalid team id", http.StatusBadRequest)
+		return
+	}
+
+	announcementID, err := uuid.Parse(mux.Vars(r)["announcement_id"])
+	if err != nil {
+		http.Error(w, "invalid announcement id", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.t.DeleteAnnouncement(ctx, teamID, announcementID, userID); err != nil {
+		h.l.Printf("delete announcement failed due to: %v", err)
+		http.Error(w, "failed to delete announcement", serviceErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// POST :: api/team/{team_id}/announcements/{announcement_id}/ack
+func (h *TeamHandler) AcknowledgeAnnouncement(w http.ResponseWriter, r *http.Request) {
+	h.l.Println("Acknowledging team announcement")
+
+	ctx := r.Context()
+
+	teamID, err := uuid.Parse(mux.Vars(r)["team_id"])
+	if err != nil {
+		http.Error(w, "invalid team id", http.StatusBadRequest)
+		return
+	}
+
+	announcementID, err := uuid.Parse(mux.Vars(r)["announcement_id"])
+	if err != nil {
+		http.Error(w, "invalid announcement id", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	announcement, err := h.t.AcknowledgeAnnouncement(ctx, teamID, announcementID, userID)
+	if err != nil {
+		h.l.Printf("acknowledge announcement failed due to: %v", err)
+		http.Error(w, "failed to acknowledge announcement", serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(&announcement)
+}
+
+// GET :: api/team/{team_id}/announcements/{announcement_id}/acks
+func (h *TeamHandler) GetAnnouncementAcks(w http.ResponseWriter, r *http.Request) {
+	h.l.Println("Fetching announcement acknowledgements")
+
+	ctx := r.Context()
+
+	teamID, err := uuid.Parse(mux.Vars(r)["team_id"])
+	if err != nil {
+		http.Error(w, "invalid team id", http.StatusBadRequest)
+		return
+	}
+
+	announcementID, err := uuid.Parse(mux.Vars(r)["announcement_id"])
+	if err != nil {
+		http.Error(w, "invalid announcement id", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	acks, err := h.t.GetAnnouncementAcks(ctx, teamID, announcementID, userID)
+	if err != nil {
+		h.l.Printf("get announcement acks failed due to: %v", err)
+		http.Error(w, "failed to get acknowledgements", serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(&acks)
+}
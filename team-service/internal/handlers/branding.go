@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"encoding/json"
+	"github/wycliff-ochieng/internal/models"
+	"net/http"
+
+	auth "github.com/wycliff-ochieng/sports-common-package/middleware"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// POST :: api/team/{team_id}/logo/presigned-url -> temporary upload url for the team logo
+func (h *TeamHandler) LogoPresignedURL(w http.ResponseWriter, r *http.Request) {
+	h.l.Println("Team logo upload temporary URL")
+
+	ctx := r.Context()
+
+	teamID, err := uuid.Parse(mux.Vars(r)["team_id"])
+	if err != nil {
+		http.Error(w, "invalid team id", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	//send file metadata not file
+	var req models.LogoUploadReq
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "failed to decode logo metadata", http.StatusBadRequest)
+		return
+	}
+
+	presignedURL, err := h.t.GenerateLogoUploadURL(ctx, teamID, userID, req)
+	if err != nil {
+		h.l.Printf("logo presigned url failed due to: %v", err)
+		http.Error(w, "failed to generate logo upload url", serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(&presignedURL)
+}
+
+// PUT :: api/team/{team_id}/logo/complete -> attach the uploaded object to the team
+func (h *TeamHandler) LogoUploadComplete(w http.ResponseWriter, r *http.Request) {
+	h.l.Println("Team logo upload complete")
+
+	ctx := r.Context()
+
+	teamID, err := uuid.Parse(mux.Vars(r)["team_id"])
+	if err != nil {
+		http.Error(w, "invalid team id", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.LogoUploadCompleteReq
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "failed to decode request body", http.StatusBadRequest)
+		return
+	}
+
+	team, err := h.t.CompleteLogoUpload(ctx, teamID, userID, req)
+	if err != nil {
+		h.l.Printf("logo upload complete failed due to: %v", err)
+		http.Error(w, "failed to save team logo", serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(&team)
+}
+
+// PUT :: api/team/{team_id}/branding -> colours, home venue, founding year, social links
+func (h *TeamHandler) UpdateTeamBranding(w http.ResponseWriter, r *http.Request) {
+	h.l.Println("Updating team branding")
+
+	ctx := r.Context()
+
+	teamID, err := uuid.Parse(mux.Vars(r)["team_id"])
+	if err != nil {
+		http.Error(w, "invalid team id", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.UpdateBrandingReq
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "failed to decode branding request", http.StatusBadRequest)
+		return
+	}
+
+	team, err := h.t.UpdateTeamBranding(ctx, teamID, userID, req)
+	if err != nil {
+		h.l.Printf("update branding failed due to: %v", err)
+		http.Error(w, "failed to update team branding", serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(&team)
+}
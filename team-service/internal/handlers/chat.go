@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"encoding/json"
+	"github/wycliff-ochieng/internal/chat"
+	"github/wycliff-ochieng/internal/service"
+	"log"
+	"net/http"
+	"strconv"
+
+	auth "github.com/wycliff-ochieng/sports-common-package/middleware"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+)
+
+type ChatHandler struct {
+	l        *log.Logger
+	t        *service.TeamService
+	hub      *chat.Hub
+	upgrader websocket.Upgrader
+}
+
+// NewChatHandler only accepts handshakes from the CORS allowed origins, the browser does not
+// apply CORS to WebSockets
+func NewChatHandler(l *log.Logger, t *service.TeamService, hub *chat.Hub, allowedOrigins []string) *ChatHandler {
+	origins := map[string]bool{}
+	for _, origin := range allowedOrigins {
+		origins[origin] = true
+	}
+
+	return &ChatHandler{
+		l:   l,
+		t:   t,
+		hub: hub,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+			CheckOrigin: func(r *http.Request) bool {
+				origin := r.Header.Get("Origin")
+				//non browser clients send no origin
+				return origin == "" || origins[origin]
+			},
+		},
+	}
+}
+
+// GET :: api/team/{team_id}/chat/ws?access_token= -> upgrade to the team channel
+func (h *ChatHandler) ServeChat(w http.ResponseWriter, r *http.Request) {
+	h.l.Println("Opening team chat connection")
+
+	ctx := r.Context()
+
+	teamID, err := uuid.Parse(mux.Vars(r)["team_id"])
+	if err != nil {
+		http.Error(w, "invalid team id", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	isMember, err := h.t.IsTeamMember(ctx, userID, teamID)
+	if err != nil {
+		h.l.Printf("chat membership check failed due to: %v", err)
+		http.Error(w, "failed to join team chat", http.StatusInternalServerError)
+		return
+	}
+	if !isMember {
+		http.Error(w, "only team members can join the team chat", http.StatusForbidden)
+		return
+	}
+
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		//the upgrader already answered the client
+		h.l.Printf("chat upgrade failed due to: %v", err)
+		return
+	}
+
+	h.hub.Serve(ctx, conn, teamID, userID)
+}
+
+// GET :: api/team/{team_id}/chat/messages?limit=&cursor= -> history, newest first
+func (h *TeamHandler) GetChatMessages(w http.ResponseWriter, r *http.Request) {
+	h.l.Println("Fetching team chat history")
+
+	ctx := r.Context()
+
+	teamID, err := uuid.Parse(mux.Vars(r)["team_id"])
+	if err != nil {
+		http.Error(w, "invalid team id", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	query := r.URL.Query()
+
+	minLimit := 1
+	maxLimit := 100
+	defaultLimit := 50
+
+	limit := defaultLimit
+	if raw := query.Get("limit"); raw != "" {
+		limit, err = strconv.Atoi(raw)
+		if err != nil || limit < minLimit || limit > maxLimit {
+			http.Error(w, "limit must be between 1 and 100", http.StatusBadRequest)
+			return
+		}
+	}
+
+	messages, err := h.t.ListChatMessages(ctx, teamID, userID, limit, query.Get("cursor"))
+	if err != nil {
+		h.l.Printf("list chat messages failed due to: %v", err)
+		http.Error(w, "failed to get chat messages", serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(&messages)
+}
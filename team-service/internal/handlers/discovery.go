@@ -0,0 +1,311 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"github/wycliff-ochieng/internal/models"
+	"io"
+	"net/http"
+	"strconv"
+
+	auth "github.com/wycliff-ochieng/sports-common-package/middleware"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// GET :: api/teams?sport=&q=&organization_id=&visibility=&archived=&limit=&cursor=
+func (h *TeamHandler) ListTeams(w http.ResponseWriter, r *http.Request) {
+	h.l.Println("Listing teams")
+
+	ctx := r.Context()
+
+	userID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	query := r.URL.Query()
+
+	minLimit := 1
+	maxLimit := 100
+	defaultLimit := 25
+
+	limit := defaultLimit
+	if raw := query.Get("limit"); raw != "" {
+		limit, err = strconv.Atoi(raw)
+		if err != nil || limit < minLimit || limit > maxLimit {
+			http.Error(w, "limit must be between 1 and 100", http.StatusBadRequest)
+			return
+		}
+	}
+
+	params := models.ListTeamsParams{
+		Sport:      query.Get("sport"),
+		Search:     query.Get("q"),
+		Visibility: query.Get("visibility"),
+		Limit:      limit,
+		Cursor:     query.Get("cursor"),
+		Archived:   query.Get("archived") == "true",
+	}
+
+	if raw := query.Get("organization_id"); raw != "" {
+		orgID, err := uuid.Parse(raw)
+		if err != nil {
+			http.Error(w, "invalid organization id", http.StatusBadRequest)
+			return
+		}
+		params.OrganizationID = &orgID
+	}
+
+	teams, err := h.t.ListTeams(ctx, userID, params)
+	if err != nil {
+		h.l.Printf("list teams failed due to: %v", err)
+		http.Error(w, "failed to list teams", serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(&teams)
+}
+
+// GET :: api/team/{team_id}/profile
+func (h *TeamHandler) GetTeamProfile(w http.ResponseWriter, r *http.Request) {
+	h.l.Println("Fetching team profile")
+
+	ctx := r.Context()
+
+	teamID, err := uuid.Parse(mux.Vars(r)["team_id"])
+	if err != nil {
+		http.Error(w, "invalid team id", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	profile, err := h.t.GetTeamProfile(ctx, teamID, userID)
+	if err != nil {
+		h.l.Printf("team profile failed due to: %v", err)
+		http.Error(w, "failed to fetch team profile", serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(&profile)
+}
+
+// PUT :: api/team/{team_id}/visibility
+func (h *TeamHandler) UpdateTeamVisibility(w http.ResponseWriter, r *http.Request) {
+	h.l.Println("Updating team visibility")
+
+	ctx := r.Context()
+
+	teamID, err := uuid.Parse(mux.Vars(r)["team_id"])
+	if err != nil {
+		http.Error(w, "invalid team id", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.TeamVisibilityReq
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "failed to decode visibility request", http.StatusBadRequest)
+		return
+	}
+
+	team, err := h.t.UpdateTeamVisibility(ctx, teamID, userID, req)
+	if err != nil {
+		h.l.Printf("update visibility failed due to: %v", err)
+		http.Error(w, "failed to update team visibility", serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(&team)
+}
+
+// POST :: api/team/{team_id}/join-requests
+func (h *TeamHandler) RequestToJoin(w http.ResponseWriter, r *http.Request) {
+	h.l.Println("Requesting to join team")
+
+	ctx := r.Context()
+
+	teamID, err := uuid.Parse(mux.Vars(r)["team_id"])
+	if err != nil {
+		http.Error(w, "invalid team id", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	//the message is optional
+	var req models.JoinRequestReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "failed to decode join request", http.StatusBadRequest)
+		return
+	}
+
+	joinReq, err := h.t.RequestToJoin(ctx, teamID, userID, req)
+	if err != nil {
+		h.l.Printf("join request failed due to: %v", err)
+		http.Error(w, "failed to request to join team", serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(&joinReq)
+}
+
+// GET :: api/team/{team_id}/join-requests?status=
+func (h *TeamHandler) GetJoinRequests(w http.ResponseWriter, r *http.Request) {
+	h.l.Println("Fetching team join requests")
+
+	ctx := r.Context()
+
+	teamID, err := uuid.Parse(mux.Vars(r)["team_id"])
+	if err != nil {
+		http.Error(w, "invalid team id", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	requests, err := h.t.ListJoinRequests(ctx, teamID, userID, r.URL.Query().Get("status"))
+	if err != nil {
+		h.l.Printf("list join requests failed due to: %v", err)
+		http.Error(w, "failed to fetch join requests", serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(&requests)
+}
+
+// GET :: api/join-requests/me
+func (h *TeamHandler) GetMyJoinRequests(w http.ResponseWriter, r *http.Request) {
+	h.l.Println("Fetching my join requests")
+
+	ctx := r.Context()
+
+	userID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	requests, err := h.t.GetMyJoinRequests(ctx, userID)
+	if err != nil {
+		h.l.Printf("my join requests failed due to: %v", err)
+		http.Error(w, "failed to fetch join requests", serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(&requests)
+}
+
+// POST :: api/team/{team_id}/join-requests/{request_id}/approve
+func (h *TeamHandler) ApproveJoinRequest(w http.ResponseWriter, r *http.Request) {
+	h.decideJoinRequest(w, r, true)
+}
+
+// POST :: api/team/{team_id}/join-requests/{request_id}/reject
+func (h *TeamHandler) RejectJoinRequest(w http.ResponseWriter, r *http.Request) {
+	h.decideJoinRequest(w, r, false)
+}
+
+func (h *TeamHandler) decideJoinRequest(w http.ResponseWriter, r *http.Request, approve bool) {
+	h.l.Printf("Deciding join request (approve=%v)", approve)
+
+	ctx := r.Context()
+
+	teamID, err := uuid.Parse(mux.Vars(r)["team_id"])
+	if err != nil {
+		http.Error(w, "invalid team id", http.StatusBadRequest)
+		return
+	}
+
+	requestID, err := uuid.Parse(mux.Vars(r)["request_id"])
+	if err != nil {
+		http.Error(w, "invalid request id", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	joinReq, err := h.t.DecideJoinRequest(ctx, teamID, requestID, userID, approve)
+	if err != nil {
+		h.l.Printf("join request decision failed due to: %v", err)
+		http.Error(w, "failed to decide join request", serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(&joinReq)
+}
+
+// DELETE :: api/team/{team_id}/join-requests/{request_id}
+func (h *TeamHandler) CancelJoinRequest(w http.ResponseWriter, r *http.Request) {
+	h.l.Println("Cancelling join request")
+
+	ctx := r.Context()
+
+	teamID, err := uuid.Parse(mux.Vars(r)["team_id"])
+	if err != nil {
+		http.Error(w, "invalid team id", http.StatusBadRequest)
+		return
+	}
+
+	requestID, err := uuid.Parse(mux.Vars(r)["request_id"])
+	if err != nil {
+		http.Error(w, "invalid request id", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	joinReq, err := h.t.CancelJoinRequest(ctx, teamID, requestID, userID)
+	if err != nil {
+		h.l.Printf("cancel join request failed due to: %v", err)
+		http.Error(w, "failed to cancel join request", serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(&joinReq)
+}
@@ -6,7 +6,6 @@ import (
 	"errors"
 	"github/wycliff-ochieng/internal/models"
 	"github/wycliff-ochieng/internal/service"
-	"github/wycliff-ochieng/middleware"
 	"log"
 	"net/http"
 	"time"
@@ -23,12 +22,13 @@ type TeamHandler struct {
 }
 
 type createTeamReq struct {
-	TeamID      uuid.UUID `json:"teamid"`
-	Name        string    `json:"name"`
-	Sport       string    `json:"sport"`
-	Description string    `json:"description"`
-	Createdat   time.Time `json:"createdat"`
-	Updatedat   time.Time `json:"updatedat"`
+	TeamID         uuid.UUID `json:"teamid"`
+	OrganizationID uuid.UUID `json:"organizationid"`
+	Name           string    `json:"name"`
+	Sport          string    `json:"sport"`
+	Description    string    `json:"description"`
+	Createdat      time.Time `json:"createdat"`
+	Updatedat      time.Time `json:"updatedat"`
 }
 
 type updateTeamDetailsReq struct {
@@ -43,6 +43,25 @@ func NewTeamHandler(l *log.Logger, t *service.TeamService) *TeamHandler {
 	}
 }
 
+// serviceErrorStatus maps service layer errors to http status codes
+func serviceErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, service.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrBadRequest):
+		return http.StatusBadRequest
+	case errors.Is(err, service.ErrAlreadyMember), errors.Is(err, service.ErrInviteNotUsable), errors.Is(err, service.ErrLastCoach), errors.Is(err, service.ErrRoleInUse), errors.Is(err, service.ErrLastAdmin), errors.Is(err, service.ErrNumberTaken),
+		errors.Is(err, service.ErrJoinRequestPending), errors.Is(err, service.ErrJoinRequestClosed), errors.Is(err, service.ErrTeamArchived), errors.Is(err, service.ErrTeamStatus):
+		return http.StatusConflict
+	case errors.Is(err, service.ErrFileStoreUnavailable):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
 // POST :: api/teams/
 func (h *TeamHandler) CreateTeam(w http.ResponseWriter, r *http.Request) {
 
@@ -66,10 +85,14 @@ func (h *TeamHandler) CreateTeam(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	team, err := h.t.CreateTeam(ctx, userID, create.TeamID, create.Name, create.Sport, create.Description, create.Createdat, create.Updatedat)
+	team, err := h.t.CreateTeam(ctx, userID, create.TeamID, create.OrganizationID, create.Name, create.Sport, create.Description, create.Createdat, create.Updatedat)
 	if err != nil {
 		h.l.Printf("somethig is wrong in the service transaction: %s", err)
-		http.Error(w, "FAILED:Error creating team service", http.StatusExpectationFailed)
+		status := http.StatusExpectationFailed
+		if errors.Is(err, service.ErrForbidden) {
+			status = http.StatusForbidden
+		}
+		http.Error(w, "FAILED:Error creating team service", status)
 		return
 	}
 
@@ -179,7 +202,7 @@ func (h *TeamHandler) UpdateTeam(w http.ResponseWriter, r *http.Request) {
 	team, err := h.t.UpdateTeamDetails(ctx, teamID, userID, update)
 	if err != nil {
 		log.Printf("FAILING DUE TO: %v", err)
-		http.Error(w, "update team service transaction error", http.StatusFailedDependency)
+		http.Error(w, "update team service transaction error", serviceErrorStatus(err))
 		return
 	}
 
@@ -233,7 +256,8 @@ func (h *TeamHandler) AddTeamMember(w http.ResponseWriter, r *http.Request) {
 	//call service layer =
 	addedMember, err := h.t.AddTeamMember(ctx, teamID, userID, addMemberReq)
 	if err != nil {
-		http.Error(w, "ERROR: something wrong with addTeamMember subscriptio", http.StatusExpectationFailed)
+		h.l.Printf("add team member failed due to: %v", err)
+		http.Error(w, "ERROR: something wrong with addTeamMember subscriptio", serviceErrorStatus(err))
 		return
 	}
 
@@ -283,31 +307,37 @@ func (h *TeamHandler) UpdateTeamMember(w http.ResponseWriter, r *http.Request) {
 
 	vars := mux.Vars(r)
 
-	teamIDStr := vars["teamid"]
-	teamID, err := uuid.Parse(teamIDStr)
+	teamID, err := uuid.Parse(vars["teamid"])
 	if err != nil {
-		log.Fatal("failed to convert string to uuid for teamid")
+		http.Error(w, "invalid team id", http.StatusBadRequest)
+		return
 	}
 
-	//get req userId from req context
+	memberID, err := uuid.Parse(vars["user_id"])
+	if err != nil {
+		http.Error(w, "invalid member user id", http.StatusBadRequest)
+		return
+	}
 
-	userID, err := auth.GetUserUUIDFromContext(ctx)
+	//get req userId from req context
+	reqUserID, err := auth.GetUserUUIDFromContext(ctx)
 	if err != nil {
-		http.Error(w, "failed to get userID from context", http.StatusNotFound)
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
 	}
 
 	var updateMember models.UpdateTeamMemberReq
 
 	err = json.NewDecoder(r.Body).Decode(&updateMember)
 	if err != nil {
-		http.Error(w, "decode team member roles daata", http.StatusExpectationFailed)
+		http.Error(w, "decode team member roles daata", http.StatusBadRequest)
 		return
 	}
 
-	//calll user service
-	member, err := h.t.UpdateTeamMembersRoles(ctx, userID, teamID, updateMember)
+	member, err := h.t.UpdateTeamMembersRoles(ctx, reqUserID, memberID, teamID, updateMember)
 	if err != nil {
-		http.Error(w, "update datatabase operation failed", http.StatusFailedDependency)
+		h.l.Printf("update member role failed due to: %v", err)
+		http.Error(w, "failed to update member role", serviceErrorStatus(err))
 		return
 	}
 
@@ -323,34 +353,54 @@ func (h *TeamHandler) RemoveTeamMember(w http.ResponseWriter, r *http.Request) {
 
 	vars := mux.Vars(r)
 
-	teamIDStr := vars["teamid"]
-	userIDStr := vars["userid"]
+	teamID, err := uuid.Parse(vars["teamid"])
+	if err != nil {
+		http.Error(w, "invalid team id", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := uuid.Parse(vars["user_id"])
+	if err != nil {
+		http.Error(w, "invalid member user id", http.StatusBadRequest)
+		return
+	}
 
-	teamID, err := uuid.Parse(teamIDStr)
+	reqUserID, err := auth.GetUserUUIDFromContext(ctx)
 	if err != nil {
-		http.Error(w, "Error changing teamId to uuid", http.StatusExpectationFailed)
+		http.Error(w, "failed to get requester's userId from context", http.StatusUnauthorized)
 		return
 	}
 
-	userID, err := uuid.Parse(userIDStr)
+	h.removeMember(w, r, reqUserID, userID, teamID)
+}
+
+// DELETE :: api/team/{team_id}/leave -> a member leaves the team on their own
+func (h *TeamHandler) LeaveTeam(w http.ResponseWriter, r *http.Request) {
+	h.l.Println("Member leaving team")
+
+	ctx := r.Context()
+
+	teamID, err := uuid.Parse(mux.Vars(r)["team_id"])
 	if err != nil {
-		http.Error(w, "Error changing userId to uuid", http.StatusExpectationFailed)
+		http.Error(w, "invalid team id", http.StatusBadRequest)
 		return
 	}
 
-	reqUserID, err := middleware.GetUserUUIDFromContext(ctx)
+	reqUserID, err := auth.GetUserUUIDFromContext(ctx)
 	if err != nil {
-		http.Error(w, "failed to get requester's userId from context", http.StatusNotFound)
+		http.Error(w, "failed to get requester's userId from context", http.StatusUnauthorized)
+		return
 	}
 
-	//call user service
-	_, err = h.t.RemoveMember(ctx, reqUserID, userID, teamID)
+	h.removeMember(w, r, reqUserID, reqUserID, teamID)
+}
+
+func (h *TeamHandler) removeMember(w http.ResponseWriter, r *http.Request, reqUserID, userID, teamID uuid.UUID) {
+	_, err := h.t.RemoveMember(r.Context(), reqUserID, userID, teamID)
 	if err != nil {
-		h.l.Println("cannot delete member from this table")
-		if errors.Is(err, service.ErrNotFound) {
-			http.Error(w, "user with this ID was not found", http.StatusNotFound)
-			return
-		}
+		h.l.Printf("cannot remove member from this team: %v", err)
+		http.Error(w, "failed to remove team member", serviceErrorStatus(err))
+		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"encoding/json"
+	"github/wycliff-ochieng/internal/models"
+	"net/http"
+
+	auth "github.com/wycliff-ochieng/sports-common-package/middleware"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// POST :: api/team/{team_id}/invites -> coach/manager invites by uuid/email or creates a join code
+func (h *TeamHandler) CreateInvite(w http.ResponseWriter, r *http.Request) {
+	h.l.Println("Creating team invite")
+
+	ctx := r.Context()
+
+	teamID, err := uuid.Parse(mux.Vars(r)["team_id"])
+	if err != nil {
+		http.Error(w, "invalid team id", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.CreateInviteReq
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "failed to decode invite request", http.StatusBadRequest)
+		return
+	}
+
+	invite, err := h.t.CreateInvite(ctx, teamID, userID, req)
+	if err != nil {
+		h.l.Printf("create invite failed due to: %v", err)
+		http.Error(w, "failed to create invite", serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(&invite)
+}
+
+// GET :: api/team/{team_id}/invites -> invite status for coaches/managers
+func (h *TeamHandler) GetTeamInvites(w http.ResponseWriter, r *http.Request) {
+	h.l.Println("Fetching team invites")
+
+	ctx := r.Context()
+
+	teamID, err := uuid.Parse(mux.Vars(r)["team_id"])
+	if err != nil {
+		http.Error(w, "invalid team id", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	invites, err := h.t.ListTeamInvites(ctx, teamID, userID)
+	if err != nil {
+		h.l.Printf("list invites failed due to: %v", err)
+		http.Error(w, "failed to fetch team invites", serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(&invites)
+}
+
+// GET :: api/invites/me -> pending invites for the logged in user
+func (h *TeamHandler) GetMyInvites(w http.ResponseWriter, r *http.Request) {
+	h.l.Println("Fetching pending invites for the logged in user")
+
+	ctx := r.Context()
+
+	userID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	invites, err := h.t.GetMyInvites(ctx, userID)
+	if err != nil {
+		h.l.Printf("my invites failed due to: %v", err)
+		http.Error(w, "failed to fetch invites", serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(&invites)
+}
+
+// POST :: api/invites/{invite_id}/accept
+func (h *TeamHandler) AcceptInvite(w http.ResponseWriter, r *http.Request) {
+	h.l.Println("Accepting team invite")
+
+	ctx := r.Context()
+
+	inviteID, err := uuid.Parse(mux.Vars(r)["invite_id"])
+	if err != nil {
+		http.Error(w, "invalid invite id", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	member, err := h.t.AcceptInvite(ctx, inviteID, userID)
+	if err != nil {
+		h.l.Printf("accept invite failed due to: %v", err)
+		http.Error(w, "failed to accept invite", serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(&member)
+}
+
+// POST :: api/invites/{invite_id}/decline
+func (h *TeamHandler) DeclineInvite(w http.ResponseWriter, r *http.Request) {
+	h.l.Println("Declining team invite")
+
+	ctx := r.Context()
+
+	inviteID, err := uuid.Parse(mux.Vars(r)["invite_id"])
+	if err != nil {
+		http.Error(w, "invalid invite id", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	invite, err := h.t.DeclineInvite(ctx, inviteID, userID)
+	if err != nil {
+		h.l.Printf("decline invite failed due to: %v", err)
+		http.Error(w, "failed to decline invite", serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(&invite)
+}
+
+// POST :: api/invites/join/{code} -> join a team through a shared code/link
+func (h *TeamHandler) JoinWithCode(w http.ResponseWriter, r *http.Request) {
+	h.l.Println("Joining team with invite code")
+
+	ctx := r.Context()
+
+	code := mux.Vars(r)["code"]
+	if code == "" {
+		http.Error(w, "missing invite code", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	member, err := h.t.JoinWithCode(ctx, code, userID)
+	if err != nil {
+		h.l.Printf("join with code failed due to: %v", err)
+		http.Error(w, "failed to join team", serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(&member)
+}
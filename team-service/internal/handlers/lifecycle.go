@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"github/wycliff-ochieng/internal/models"
+	"net/http"
+
+	auth "github.com/wycliff-ochieng/sports-common-package/middleware"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// POST :: api/team/{team_id}/archive
+func (h *TeamHandler) ArchiveTeam(w http.ResponseWriter, r *http.Request) {
+	h.l.Println("Archiving team")
+	h.changeTeamStatus(w, r, "archive", h.t.ArchiveTeam)
+}
+
+// POST :: api/team/{team_id}/restore
+func (h *TeamHandler) RestoreTeam(w http.ResponseWriter, r *http.Request) {
+	h.l.Println("Restoring team")
+	h.changeTeamStatus(w, r, "restore", h.t.RestoreTeam)
+}
+
+// DELETE :: api/team/{team_id}
+func (h *TeamHandler) DeleteTeam(w http.ResponseWriter, r *http.Request) {
+	h.l.Println("Scheduling team deletion")
+	h.changeTeamStatus(w, r, "delete", h.t.DeleteTeam)
+}
+
+func (h *TeamHandler) changeTeamStatus(w http.ResponseWriter, r *http.Request, action string, change func(context.Context, uuid.UUID, uuid.UUID) (*models.Team, error)) {
+
+	ctx := r.Context()
+
+	teamID, err := uuid.Parse(mux.Vars(r)["team_id"])
+	if err != nil {
+		http.Error(w, "invalid team id", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	team, err := change(ctx, teamID, userID)
+	if err != nil {
+		h.l.Printf("team %s failed due to: %v", action, err)
+		http.Error(w, "failed to "+action+" team", serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(&team)
+}
@@ -0,0 +1,301 @@
+package handlers
+
+import (
+	"encoding/json"
+	"github/wycliff-ochieng/internal/models"
+	"net/http"
+
+	auth "github.com/wycliff-ochieng/sports-common-package/middleware"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// POST :: api/organizations -> create a club, the caller becomes its admin
+func (h *TeamHandler) CreateOrganization(w http.ResponseWriter, r *http.Request) {
+	h.l.Println("Creating organization")
+
+	ctx := r.Context()
+
+	userID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.OrganizationReq
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "failed to decode organization request", http.StatusBadRequest)
+		return
+	}
+
+	org, err := h.t.CreateOrganization(ctx, userID, req)
+	if err != nil {
+		h.l.Printf("create organization failed due to: %v", err)
+		http.Error(w, "failed to create organization", serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(&org)
+}
+
+// GET :: api/organizations/me -> organizations the caller administers or plays in
+func (h *TeamHandler) GetMyOrganizations(w http.ResponseWriter, r *http.Request) {
+	h.l.Println("Fetching organizations for the logged in user")
+
+	ctx := r.Context()
+
+	userID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	orgs, err := h.t.GetMyOrganizations(ctx, userID)
+	if err != nil {
+		h.l.Printf("my organizations failed due to: %v", err)
+		http.Error(w, "failed to fetch organizations", serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(&orgs)
+}
+
+// GET :: api/organizations/{org_id} -> organization with teams and admins
+func (h *TeamHandler) GetOrganization(w http.ResponseWriter, r *http.Request) {
+	h.l.Println("Fetching organization")
+
+	ctx := r.Context()
+
+	orgID, err := uuid.Parse(mux.Vars(r)["org_id"])
+	if err != nil {
+		http.Error(w, "invalid organization id", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	org, err := h.t.GetOrganization(ctx, orgID, userID)
+	if err != nil {
+		h.l.Printf("get organization failed due to: %v", err)
+		http.Error(w, "failed to fetch organization", serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(&org)
+}
+
+// PUT :: api/organizations/{org_id}
+func (h *TeamHandler) UpdateOrganization(w http.ResponseWriter, r *http.Request) {
+	h.l.Println("Updating organization")
+
+	ctx := r.Context()
+
+	orgID, err := uuid.Parse(mux.Vars(r)["org_id"])
+	if err != nil {
+		http.Error(w, "invalid organization id", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.OrganizationReq
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "failed to decode organization request", http.StatusBadRequest)
+		return
+	}
+
+	org, err := h.t.UpdateOrganization(ctx, orgID, userID, req)
+	if err != nil {
+		h.l.Printf("update organization failed due to: %v", err)
+		http.Error(w, "failed to update organization", serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(&org)
+}
+
+// GET :: api/organizations/{org_id}/teams
+func (h *TeamHandler) GetOrganizationTeams(w http.ResponseWriter, r *http.Request) {
+	h.l.Println("Fetching organization teams")
+
+	ctx := r.Context()
+
+	orgID, err := uuid.Parse(mux.Vars(r)["org_id"])
+	if err != nil {
+		http.Error(w, "invalid organization id", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	teams, err := h.t.GetOrganizationTeams(ctx, orgID, userID)
+	if err != nil {
+		h.l.Printf("organization teams failed due to: %v", err)
+		http.Error(w, "failed to fetch organization teams", serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(&teams)
+}
+
+// GET :: api/organizations/{org_id}/members -> member directory across all teams
+func (h *TeamHandler) GetOrganizationMembers(w http.ResponseWriter, r *http.Request) {
+	h.l.Println("Fetching organization member directory")
+
+	ctx := r.Context()
+
+	orgID, err := uuid.Parse(mux.Vars(r)["org_id"])
+	if err != nil {
+		http.Error(w, "invalid organization id", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	members, err := h.t.GetOrganizationMembers(ctx, orgID, userID)
+	if err != nil {
+		h.l.Printf("organization members failed due to: %v", err)
+		http.Error(w, "failed to fetch organization members", serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(&members)
+}
+
+// POST :: api/organizations/{org_id}/admins
+func (h *TeamHandler) AddOrganizationAdmin(w http.ResponseWriter, r *http.Request) {
+	h.l.Println("Adding organization admin")
+
+	ctx := r.Context()
+
+	orgID, err := uuid.Parse(mux.Vars(r)["org_id"])
+	if err != nil {
+		http.Error(w, "invalid organization id", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.OrgAdminReq
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "failed to decode admin request", http.StatusBadRequest)
+		return
+	}
+
+	admins, err := h.t.AddOrganizationAdmin(ctx, orgID, userID, req)
+	if err != nil {
+		h.l.Printf("add organization admin failed due to: %v", err)
+		http.Error(w, "failed to add organization admin", serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(&admins)
+}
+
+// DELETE :: api/organizations/{org_id}/admins/{user_id}
+func (h *TeamHandler) RemoveOrganizationAdmin(w http.ResponseWriter, r *http.Request) {
+	h.l.Println("Removing organization admin")
+
+	ctx := r.Context()
+
+	orgID, err := uuid.Parse(mux.Vars(r)["org_id"])
+	if err != nil {
+		http.Error(w, "invalid organization id", http.StatusBadRequest)
+		return
+	}
+
+	targetID, err := uuid.Parse(mux.Vars(r)["user_id"])
+	if err != nil {
+		http.Error(w, "invalid user id", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.t.RemoveOrganizationAdmin(ctx, orgID, userID, targetID); err != nil {
+		h.l.Printf("remove organization admin failed due to: %v", err)
+		http.Error(w, "failed to remove organization admin", serviceErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// PUT :: api/team/{team_id}/organization -> move a team to another organization
+func (h *TeamHandler) MoveTeamToOrganization(w http.ResponseWriter, r *http.Request) {
+	h.l.Println("Moving team to another organization")
+
+	ctx := r.Context()
+
+	teamID, err := uuid.Parse(mux.Vars(r)["team_id"])
+	if err != nil {
+		http.Error(w, "invalid team id", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.MoveTeamReq
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "failed to decode move request", http.StatusBadRequest)
+		return
+	}
+
+	team, err := h.t.MoveTeamToOrganization(ctx, teamID, userID, req)
+	if err != nil {
+		h.l.Printf("move team failed due to: %v", err)
+		http.Error(w, "failed to move team", serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(&team)
+}
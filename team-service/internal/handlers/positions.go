@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"encoding/json"
+	"github/wycliff-ochieng/internal/models"
+	"net/http"
+
+	auth "github.com/wycliff-ochieng/sports-common-package/middleware"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// GET :: api/sports/{sport}/positions -> positions configured for a sport
+func (h *TeamHandler) GetSportPositions(w http.ResponseWriter, r *http.Request) {
+	h.l.Println("Fetching sport positions")
+
+	ctx := r.Context()
+
+	positions, err := h.t.ListSportPositions(ctx, mux.Vars(r)["sport"])
+	if err != nil {
+		h.l.Printf("sport positions failed due to: %v", err)
+		http.Error(w, "failed to fetch positions", serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(&positions)
+}
+
+// PUT :: api/team/{team_id}/members/{user_id}/position -> squad number and positions
+func (h *TeamHandler) UpdateMemberPosition(w http.ResponseWriter, r *http.Request) {
+	h.l.Println("Updating member number and positions")
+
+	ctx := r.Context()
+
+	teamID, err := uuid.Parse(mux.Vars(r)["team_id"])
+	if err != nil {
+		http.Error(w, "invalid team id", http.StatusBadRequest)
+		return
+	}
+
+	targetID, err := uuid.Parse(mux.Vars(r)["user_id"])
+	if err != nil {
+		http.Error(w, "invalid user id", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.MemberProfileReq
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "failed to decode position request", http.StatusBadRequest)
+		return
+	}
+
+	member, err := h.t.UpdateMemberPosition(ctx, teamID, targetID, userID, req)
+	if err != nil {
+		h.l.Printf("update member position failed due to: %v", err)
+		http.Error(w, "failed to update member position", serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(&member)
+}
+
+// PUT :: api/team/{team_id}/depth-chart -> coaches order the players of a position
+func (h *TeamHandler) SetDepthChart(w http.ResponseWriter, r *http.Request) {
+	h.l.Println("Updating depth chart")
+
+	ctx := r.Context()
+
+	teamID, err := uuid.Parse(mux.Vars(r)["team_id"])
+	if err != nil {
+		http.Error(w, "invalid team id", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.DepthChartReq
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "failed to decode depth chart request", http.StatusBadRequest)
+		return
+	}
+
+	chart, err := h.t.SetDepthChart(ctx, teamID, userID, req)
+	if err != nil {
+		h.l.Printf("depth chart update failed due to: %v", err)
+		http.Error(w, "failed to update depth chart", serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(&chart)
+}
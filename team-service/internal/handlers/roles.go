@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"encoding/json"
+	"github/wycliff-ochieng/internal/models"
+	"net/http"
+
+	auth "github.com/wycliff-ochieng/sports-common-package/middleware"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// GET :: api/team/{team_id}/roles -> built in and custom roles with their permissions
+func (h *TeamHandler) GetTeamRoles(w http.ResponseWriter, r *http.Request) {
+	h.l.Println("Fetching team roles")
+
+	ctx := r.Context()
+
+	teamID, err := uuid.Parse(mux.Vars(r)["team_id"])
+	if err != nil {
+		http.Error(w, "invalid team id", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	roles, err := h.t.ListTeamRoles(ctx, teamID, userID)
+	if err != nil {
+		h.l.Printf("list roles failed due to: %v", err)
+		http.Error(w, "failed to fetch team roles", serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(&roles)
+}
+
+// POST :: api/team/{team_id}/roles -> create a custom role
+func (h *TeamHandler) CreateTeamRole(w http.ResponseWriter, r *http.Request) {
+	h.l.Println("Creating custom team role")
+
+	ctx := r.Context()
+
+	teamID, err := uuid.Parse(mux.Vars(r)["team_id"])
+	if err != nil {
+		http.Error(w, "invalid team id", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.TeamRoleReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "failed to decode role request", http.StatusBadRequest)
+		return
+	}
+
+	role, err := h.t.CreateTeamRole(ctx, teamID, userID, req)
+	if err != nil {
+		h.l.Printf("create role failed due to: %v", err)
+		http.Error(w, "failed to create team role", serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(&role)
+}
+
+// PUT :: api/team/{team_id}/roles/{role} -> replace the permissions of a custom role
+func (h *TeamHandler) UpdateTeamRole(w http.ResponseWriter, r *http.Request) {
+	h.l.Println("Updating custom team role")
+
+	ctx := r.Context()
+	vars := mux.Vars(r)
+
+	teamID, err := uuid.Parse(vars["team_id"])
+	if err != nil {
+		http.Error(w, "invalid team id", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.TeamRoleReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "failed to decode role request", http.StatusBadRequest)
+		return
+	}
+
+	role, err := h.t.UpdateTeamRole(ctx, teamID, userID, vars["role"], req)
+	if err != nil {
+		h.l.Printf("update role failed due to: %v", err)
+		http.Error(w, "failed to update team role", serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(&role)
+}
+
+// DELETE :: api/team/{team_id}/roles/{role}
+func (h *TeamHandler) DeleteTeamRole(w http.ResponseWriter, r *http.Request) {
+	h.l.Println("Deleting custom team role")
+
+	ctx := r.Context()
+	vars := mux.Vars(r)
+
+	teamID, err := uuid.Parse(vars["team_id"])
+	if err != nil {
+		http.Error(w, "invalid team id", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.t.DeleteTeamRole(ctx, teamID, userID, vars["role"]); err != nil {
+		h.l.Printf("delete role failed due to: %v", err)
+		http.Error(w, "failed to delete team role", serviceErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
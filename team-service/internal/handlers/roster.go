@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github/wycliff-ochieng/internal/models"
+	"github/wycliff-ochieng/internal/roster"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	auth "github.com/wycliff-ochieng/sports-common-package/middleware"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// maxRosterImportSize keeps a single upload well above a full squad but bounded
+const maxRosterImportSize = 1 << 20
+
+// POST :: api/team/{team_id}/roster/import?dry_run=true&invite_unknown=true
+// accepts a text/csv body or a multipart form with a "file" field
+func (h *TeamHandler) ImportRoster(w http.ResponseWriter, r *http.Request) {
+	h.l.Println("Importing team roster")
+
+	ctx := r.Context()
+
+	teamID, err := uuid.Parse(mux.Vars(r)["team_id"])
+	if err != nil {
+		http.Error(w, "invalid team id", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	var opts models.RosterImportOptions
+	for name, target := range map[string]*bool{"dry_run": &opts.DryRun, "invite_unknown": &opts.InviteUnknown} {
+		if raw := r.URL.Query().Get(name); raw != "" {
+			if *target, err = strconv.ParseBool(raw); err != nil {
+				http.Error(w, fmt.Sprintf("invalid %s flag", name), http.StatusBadRequest)
+				return
+			}
+		}
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxRosterImportSize)
+
+	var body io.Reader = r.Body
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			http.Error(w, "missing csv file", http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+		body = file
+	}
+
+	rows, err := roster.ParseImport(body)
+	if err != nil {
+		h.l.Printf("roster csv parse failed due to: %v", err)
+		http.Error(w, fmt.Sprintf("invalid roster csv: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.t.ImportRoster(ctx, teamID, userID, rows, opts)
+	if err != nil {
+		h.l.Printf("roster import failed due to: %v", err)
+		http.Error(w, "failed to import roster", serviceErrorStatus(err))
+		return
+	}
+
+	status := http.StatusOK
+	switch {
+	case report.Committed:
+		status = http.StatusCreated
+	case !report.DryRun:
+		status = http.StatusUnprocessableEntity
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(&report)
+}
+
+// GET :: api/team/{team_id}/roster/export?format=csv|json
+func (h *TeamHandler) ExportRoster(w http.ResponseWriter, r *http.Request) {
+	h.l.Println("Exporting team roster")
+
+	ctx := r.Context()
+
+	teamID, err := uuid.Parse(mux.Vars(r)["team_id"])
+	if err != nil {
+		http.Error(w, "invalid team id", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	format := strings.ToLower(r.URL.Query().Get("format"))
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "json" {
+		http.Error(w, "format must be csv or json", http.StatusBadRequest)
+		return
+	}
+
+	rows, err := h.t.ExportRoster(ctx, teamID, userID)
+	if err != nil {
+		h.l.Printf("roster export failed due to: %v", err)
+		http.Error(w, "failed to export roster", serviceErrorStatus(err))
+		return
+	}
+
+	if format == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(&rows)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"roster-%s.csv\"", teamID))
+	w.WriteHeader(http.StatusOK)
+	if err := roster.WriteExport(w, rows); err != nil && !errors.Is(err, http.ErrHandlerTimeout) {
+		h.l.Printf("writing roster csv failed due to: %v", err)
+	}
+}
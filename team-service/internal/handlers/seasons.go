@@ -0,0 +1,243 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"github/wycliff-ochieng/internal/models"
+	"io"
+	"net/http"
+	"time"
+
+	auth "github.com/wycliff-ochieng/sports-common-package/middleware"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// POST :: api/team/{team_id}/seasons
+func (h *TeamHandler) CreateSeason(w http.ResponseWriter, r *http.Request) {
+	h.l.Println("Creating team season")
+
+	ctx := r.Context()
+
+	teamID, err := uuid.Parse(mux.Vars(r)["team_id"])
+	if err != nil {
+		http.Error(w, "invalid team id", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.SeasonReq
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "failed to decode season request", http.StatusBadRequest)
+		return
+	}
+
+	season, err := h.t.CreateSeason(ctx, teamID, userID, req)
+	if err != nil {
+		h.l.Printf("create season failed due to: %v", err)
+		http.Error(w, "failed to create season", serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(&season)
+}
+
+// GET :: api/team/{team_id}/seasons
+func (h *TeamHandler) GetSeasons(w http.ResponseWriter, r *http.Request) {
+	h.l.Println("Fetching team seasons")
+
+	ctx := r.Context()
+
+	teamID, err := uuid.Parse(mux.Vars(r)["team_id"])
+	if err != nil {
+		http.Error(w, "invalid team id", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	seasons, err := h.t.ListSeasons(ctx, teamID, userID)
+	if err != nil {
+		h.l.Printf("list seasons failed due to: %v", err)
+		http.Error(w, "failed to fetch seasons", serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(&seasons)
+}
+
+// PUT :: api/team/{team_id}/seasons/{season_id}
+func (h *TeamHandler) UpdateSeason(w http.ResponseWriter, r *http.Request) {
+	h.l.Println("Updating team season")
+
+	ctx := r.Context()
+
+	teamID, err := uuid.Parse(mux.Vars(r)["team_id"])
+	if err != nil {
+		http.Error(w, "invalid team id", http.StatusBadRequest)
+		return
+	}
+
+	seasonID, err := uuid.Parse(mux.Vars(r)["season_id"])
+	if err != nil {
+		http.Error(w, "invalid season id", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.SeasonReq
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "failed to decode season request", http.StatusBadRequest)
+		return
+	}
+
+	season, err := h.t.UpdateSeason(ctx, teamID, seasonID, userID, req)
+	if err != nil {
+		h.l.Printf("update season failed due to: %v", err)
+		http.Error(w, "failed to update season", serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(&season)
+}
+
+// POST :: api/team/{team_id}/seasons/{season_id}/rollover -> copy the previous season's roster
+func (h *TeamHandler) RolloverSeason(w http.ResponseWriter, r *http.Request) {
+	h.l.Println("Rolling over season roster")
+
+	ctx := r.Context()
+
+	teamID, err := uuid.Parse(mux.Vars(r)["team_id"])
+	if err != nil {
+		http.Error(w, "invalid team id", http.StatusBadRequest)
+		return
+	}
+
+	seasonID, err := uuid.Parse(mux.Vars(r)["season_id"])
+	if err != nil {
+		http.Error(w, "invalid season id", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	//the body is optional, an empty one rolls over from the previous season
+	var req models.RolloverReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "failed to decode rollover request", http.StatusBadRequest)
+		return
+	}
+
+	roster, err := h.t.RolloverSeason(ctx, teamID, seasonID, userID, req)
+	if err != nil {
+		h.l.Printf("season rollover failed due to: %v", err)
+		http.Error(w, "failed to roll over season roster", serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(&roster)
+}
+
+// GET :: api/team/{team_id}/seasons/{season_id}/roster
+func (h *TeamHandler) GetSeasonRoster(w http.ResponseWriter, r *http.Request) {
+	h.l.Println("Fetching season roster")
+
+	ctx := r.Context()
+
+	teamID, err := uuid.Parse(mux.Vars(r)["team_id"])
+	if err != nil {
+		http.Error(w, "invalid team id", http.StatusBadRequest)
+		return
+	}
+
+	seasonID, err := uuid.Parse(mux.Vars(r)["season_id"])
+	if err != nil {
+		http.Error(w, "invalid season id", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	roster, err := h.t.GetSeasonRoster(ctx, teamID, seasonID, userID)
+	if err != nil {
+		h.l.Printf("season roster failed due to: %v", err)
+		http.Error(w, "failed to fetch season roster", serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(&roster)
+}
+
+// GET :: api/team/{team_id}/roster?date=YYYY-MM-DD -> who was on the roster that day
+func (h *TeamHandler) GetRosterOnDate(w http.ResponseWriter, r *http.Request) {
+	h.l.Println("Fetching roster on date")
+
+	ctx := r.Context()
+
+	teamID, err := uuid.Parse(mux.Vars(r)["team_id"])
+	if err != nil {
+		http.Error(w, "invalid team id", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := auth.GetUserUUIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "failed to get userID from context", http.StatusUnauthorized)
+		return
+	}
+
+	day := time.Now().UTC()
+	if date := r.URL.Query().Get("date"); date != "" {
+		day, err = time.Parse(models.SeasonDateLayout, date)
+		if err != nil {
+			http.Error(w, "invalid date, expected YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+	}
+
+	roster, err := h.t.GetRosterOnDate(ctx, teamID, userID, day)
+	if err != nil {
+		h.l.Printf("roster on date failed due to: %v", err)
+		http.Error(w, "failed to fetch roster", serviceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(&roster)
+}
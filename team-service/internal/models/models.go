@@ -1,38 +1,280 @@
 package models
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
 )
 
 type Team struct {
-	TeamID      uuid.UUID `json:"teamid"`
-	Name        string    `json:"name"`
-	Sport       string    `json:"sport"`
-	Description string    `json:"description"`
-	Createdat   time.Time `json:"createdat"`
-	Updatedat   time.Time `json:"updatedat"`
+	TeamID         uuid.UUID `json:"teamid"`
+	OrganizationID uuid.UUID `json:"organizationid"`
+	Name           string    `json:"name"`
+	Sport          string    `json:"sport"`
+	Description    string    `json:"description"`
+	Visibility     string    `json:"visibility"`
+	Createdat      time.Time `json:"createdat"`
+	Updatedat      time.Time `json:"updatedat"`
+	TeamBranding
+	TeamLifecycle
+}
+
+// team statuses, anything but ACTIVE is read-only and hidden from listings
+const (
+	TeamStatusActive          = "ACTIVE"
+	TeamStatusArchived        = "ARCHIVED"
+	TeamStatusPendingDeletion = "PENDING_DELETION"
+)
+
+// TeamLifecycle is embedded in Team, DeleteAfter is set while a hard delete is pending and
+// NeedsCoach while no active coach is left on the team
+type TeamLifecycle struct {
+	Status      string     `json:"status"`
+	ArchivedAt  *time.Time `json:"archivedat,omitempty"`
+	ArchivedBy  *uuid.UUID `json:"archivedBy,omitempty"`
+	DeleteAfter *time.Time `json:"deleteAfter,omitempty"`
+	NeedsCoach  bool       `json:"needsCoach"`
+}
+
+// member statuses, suspended users are INACTIVE and hold no permissions
+const (
+	MemberStatusActive   = "ACTIVE"
+	MemberStatusInactive = "INACTIVE"
+)
+
+// team visibility, public teams show up in discovery and accept join requests
+const (
+	TeamVisibilityPublic  = "public"
+	TeamVisibilityPrivate = "private"
+)
+
+type TeamVisibilityReq struct {
+	Visibility string `json:"visibility"`
+}
+
+// ListTeamsParams filters GET /api/teams, Cursor is the NextCursor of the previous page.
+// Archived lists the caller's archived/pending deletion teams instead of the active ones
+type ListTeamsParams struct {
+	Sport          string
+	Search         string
+	OrganizationID *uuid.UUID
+	Visibility     string
+	Archived       bool
+	Limit          int
+	Cursor         string
+}
+
+// TeamListing is the limited team profile shown in discovery, non-members only ever see this
+type TeamListing struct {
+	TeamID            uuid.UUID `json:"teamid"`
+	OrganizationID    uuid.UUID `json:"organizationid"`
+	Name              string    `json:"name"`
+	Sport             string    `json:"sport"`
+	Description       string    `json:"description"`
+	Visibility        string    `json:"visibility"`
+	Status            string    `json:"status"`
+	LogoURL           string    `json:"logoUrl,omitempty"`
+	PrimaryColor      string    `json:"primaryColor,omitempty"`
+	SecondaryColor    string    `json:"secondaryColor,omitempty"`
+	HomeVenue         string    `json:"homeVenue,omitempty"`
+	MemberCount       int       `json:"memberCount"`
+	IsMember          bool      `json:"isMember"`
+	Role              string    `json:"role,omitempty"`
+	JoinRequestStatus string    `json:"joinRequestStatus,omitempty"`
+	Createdat         time.Time `json:"createdat"`
+}
+
+type PaginatedTeams struct {
+	Data       []TeamListing
+	NextCursor string
+}
+
+// join request statuses
+const (
+	JoinRequestPending   = "PENDING"
+	JoinRequestApproved  = "APPROVED"
+	JoinRequestRejected  = "REJECTED"
+	JoinRequestCancelled = "CANCELLED"
+)
+
+type JoinRequest struct {
+	RequestID uuid.UUID  `json:"requestid"`
+	TeamID    uuid.UUID  `json:"teamid"`
+	TeamName  string     `json:"teamName,omitempty"`
+	UserID    uuid.UUID  `json:"userid"`
+	Firstname string     `json:"firstName,omitempty"`
+	Lastname  string     `json:"lastName,omitempty"`
+	Email     string     `json:"email,omitempty"`
+	Message   string     `json:"message,omitempty"`
+	Status    string     `json:"status"`
+	DecidedBy *uuid.UUID `json:"decidedBy,omitempty"`
+	DecidedAt *time.Time `json:"decidedat,omitempty"`
+	Createdat time.Time  `json:"createdat"`
+}
+
+type JoinRequestReq struct {
+	Message string `json:"message"`
+}
+
+// Announcement is a message to the whole roster. AckCount/RecipientCount are the "read by 14/20"
+// of announcements that ask for an acknowledgement, counted over the current members
+type Announcement struct {
+	AnnouncementID uuid.UUID  `json:"announcementid"`
+	TeamID         uuid.UUID  `json:"teamid"`
+	AuthorID       uuid.UUID  `json:"authorid"`
+	AuthorName     string     `json:"authorName,omitempty"`
+	Title          string     `json:"title"`
+	Body           string     `json:"body"`
+	Pinned         bool       `json:"pinned"`
+	RequiresAck    bool       `json:"requiresAck"`
+	ExpiresAt      *time.Time `json:"expiresAt,omitempty"`
+	AckCount       int        `json:"ackCount"`
+	RecipientCount int        `json:"recipientCount"`
+	AcknowledgedAt *time.Time `json:"acknowledgedAt,omitempty"`
+	Createdat      time.Time  `json:"createdat"`
+	Updatedat      time.Time  `json:"updatedat"`
+}
+
+type AnnouncementReq struct {
+	Title       string     `json:"title"`
+	Body        string     `json:"body"`
+	Pinned      bool       `json:"pinned"`
+	RequiresAck bool       `json:"requiresAck"`
+	ExpiresAt   *time.Time `json:"expiresAt"`
+}
+
+type PinAnnouncementReq struct {
+	Pinned bool `json:"pinned"`
+}
+
+// AnnouncementAck is one member's read status, AcknowledgedAt is nil until they confirm
+type AnnouncementAck struct {
+	UserID         uuid.UUID  `json:"userid"`
+	Firstname      string     `json:"firstName,omitempty"`
+	Lastname       string     `json:"lastName,omitempty"`
+	AcknowledgedAt *time.Time `json:"acknowledgedAt,omitempty"`
+}
+
+// ChatMessage is one message of the team channel, ClientID is the sender's own id for resends
+type ChatMessage struct {
+	MessageID uuid.UUID `json:"messageid"`
+	TeamID    uuid.UUID `json:"teamid"`
+	UserID    uuid.UUID `json:"userid"`
+	Body      string    `json:"body"`
+	ClientID  string    `json:"clientid,omitempty"`
+	Createdat time.Time `json:"createdat"`
+}
+
+type PaginatedChatMessages struct {
+	Data       []ChatMessage
+	NextCursor string
+}
+
+// activity types of the team feed, roster changes use the TeamRosterChanged change type
+// (MEMBER_ADDED, ROLE_CHANGED, MEMBER_REMOVED...)
+const (
+	ActivityMemberJoined        = "MEMBER_JOINED"
+	ActivityTeamUpdated         = "TEAM_UPDATED"
+	ActivityOrganizationChanged = "ORGANIZATION_CHANGED"
+	ActivityJoinRequested       = "JOIN_REQUESTED"
+	ActivityJoinRequestApproved = "JOIN_REQUEST_APPROVED"
+	ActivityJoinRequestRejected = "JOIN_REQUEST_REJECTED"
+	ActivityTeamArchived        = "TEAM_ARCHIVED"
+	ActivityDeletionScheduled   = "DELETION_SCHEDULED"
+	ActivityTeamRestored        = "TEAM_RESTORED"
+	ActivityCoachMissing        = "COACH_MISSING"
+	ActivityAnnouncementPosted  = "ANNOUNCEMENT_POSTED"
+	ActivityEventCreated        = "EVENT_CREATED"
+	ActivityEventCancelled      = "EVENT_CANCELLED"
+)
+
+// TeamActivity is one entry of the team feed. ActorID is nil for system changes, SubjectID is the
+// member, event or workout the entry is about and Data the payload of the event it was built from
+type TeamActivity struct {
+	ActivityID uuid.UUID       `json:"activityid"`
+	TeamID     uuid.UUID       `json:"teamid"`
+	Type       string          `json:"type"`
+	ActorID    *uuid.UUID      `json:"actorid,omitempty"`
+	ActorName  string          `json:"actorName,omitempty"`
+	SubjectID  *uuid.UUID      `json:"subjectid,omitempty"`
+	Source     string          `json:"source"`
+	Data       json.RawMessage `json:"data"`
+	Createdat  time.Time       `json:"createdat"`
+}
+
+// ListActivityParams filters GET /api/team/{team_id}/activity, an empty Types returns every type
+type ListActivityParams struct {
+	Types  []string
+	Limit  int
+	Cursor string
+}
+
+type PaginatedActivity struct {
+	Data       []TeamActivity
+	NextCursor string
+}
+
+// TeamBranding is embedded in the team responses, LogoURL is a short lived presigned link
+type TeamBranding struct {
+	LogoURL        string            `json:"logoUrl,omitempty"`
+	PrimaryColor   string            `json:"primaryColor,omitempty"`
+	SecondaryColor string            `json:"secondaryColor,omitempty"`
+	HomeVenue      string            `json:"homeVenue,omitempty"`
+	FoundedYear    int               `json:"foundedYear,omitempty"`
+	SocialLinks    map[string]string `json:"socialLinks,omitempty"`
 }
 
 type TeamMembers struct {
 	TeamID    uuid.UUID `json:"teamid"`
 	Role      string    `json:"role"`
+	Status    string    `json:"status,omitempty"`
 	Joinedat  time.Time `json:"joinedat"`
 	UserID    uuid.UUID `json:"userid"`
 	Firstname string    `json:"firstName,omitempty"`
 	Lastname  string    `json:"lastName,omitempty"`
 	Email     string    `json:"email,omitempty"`
+	MemberPosition
+}
+
+// MemberPosition is the squad number, positions and depth chart slot of a member
+type MemberPosition struct {
+	JerseyNumber      *int   `json:"jerseyNumber,omitempty"`
+	PrimaryPosition   string `json:"primaryPosition,omitempty"`
+	SecondaryPosition string `json:"secondaryPosition,omitempty"`
+	DepthOrder        *int   `json:"depthOrder,omitempty"`
+}
+
+type SportPosition struct {
+	Sport     string `json:"sport"`
+	Code      string `json:"code"`
+	Name      string `json:"name"`
+	SortOrder int    `json:"sortOrder"`
+}
+
+// MemberProfileReq replaces a member's number and positions, a nil number clears it
+type MemberProfileReq struct {
+	JerseyNumber      *int   `json:"jerseyNumber"`
+	PrimaryPosition   string `json:"primaryPosition"`
+	SecondaryPosition string `json:"secondaryPosition"`
+}
+
+// DepthChartReq orders the members playing Position, first is the starter
+type DepthChartReq struct {
+	Position string      `json:"position"`
+	UserIDs  []uuid.UUID `json:"userids"`
 }
 
 type TeamInfo struct {
-	TeamID      uuid.UUID
-	Name        string
-	Sport       string
-	Role        string
-	Description string
-	Updatedat   time.Time
-	Joinedat    time.Time
+	TeamID         uuid.UUID
+	OrganizationID uuid.UUID
+	Name           string
+	Sport          string
+	Role           string
+	Description    string
+	Updatedat      time.Time
+	Joinedat       time.Time
+	TeamBranding
 }
 
 type TeamMembersResponse struct {
@@ -40,16 +282,23 @@ type TeamMembersResponse struct {
 	Firstname string `json:"firstName"`
 	Lastname  string `json:"lastName"`
 	Email     string
+	Role      string `json:"role"`
+	Status    string `json:"status"`
 	Createdat time.Time
 	Updatedat time.Time
+	MemberPosition
 }
 
 type TeamDetailsInfo struct {
-	TeamID      uuid.UUID
-	Name        string
-	Sport       string
-	Description string
-	Members     []TeamMembers
+	TeamID         uuid.UUID
+	OrganizationID uuid.UUID
+	Name           string
+	Sport          string
+	Description    string
+	TeamBranding
+	Members []TeamMembers
+	//active announcements, pinned first
+	Announcements []Announcement
 }
 
 type UpdateTeamReq struct {
@@ -61,6 +310,30 @@ type UpdateTeamReq struct {
 	Updatedat   time.Time `json:"updatedat"`
 }
 
+// UpdateBrandingReq replaces the team's colours, venue, founding year and social links
+type UpdateBrandingReq struct {
+	PrimaryColor   string            `json:"primaryColor"`
+	SecondaryColor string            `json:"secondaryColor"`
+	HomeVenue      string            `json:"homeVenue"`
+	FoundedYear    int               `json:"foundedYear"`
+	SocialLinks    map[string]string `json:"socialLinks"`
+}
+
+type LogoUploadReq struct {
+	Filename string `json:"file_name"`
+	MimeType string `json:"mime_type"`
+}
+
+type LogoUploadCompleteReq struct {
+	ObjectKey string `json:"object_key"`
+}
+
+type PresignedURLRes struct {
+	UploadURL string
+	ObjectKey string
+	ExpiresAT time.Time
+}
+
 type AddMemberReq struct {
 	UserID     uuid.UUID `json:"-"` // resolved target user UUID
 	Role       string    `json:"role"`
@@ -72,6 +345,205 @@ type UpdateTeamMemberReq struct {
 	Role string
 }
 
+type TeamRole struct {
+	TeamID      uuid.UUID `json:"teamid"`
+	Name        string    `json:"name"`
+	Permissions []string  `json:"permissions"`
+	Builtin     bool      `json:"builtin"`
+	Createdat   time.Time `json:"createdat,omitempty"`
+	Updatedat   time.Time `json:"updatedat,omitempty"`
+}
+
+type TeamRoleReq struct {
+	Name        string   `json:"name"`
+	Permissions []string `json:"permissions"`
+}
+
+type Organization struct {
+	OrgID       uuid.UUID          `json:"organizationid"`
+	Name        string             `json:"name"`
+	Description string             `json:"description"`
+	CreatedBy   *uuid.UUID         `json:"createdby,omitempty"`
+	IsAdmin     bool               `json:"isAdmin"`
+	Admins      []uuid.UUID        `json:"admins,omitempty"`
+	Teams       []OrganizationTeam `json:"teams,omitempty"`
+	Createdat   time.Time          `json:"createdat"`
+	Updatedat   time.Time          `json:"updatedat"`
+}
+
+type OrganizationTeam struct {
+	TeamID uuid.UUID `json:"teamid"`
+	Name   string    `json:"name"`
+	Sport  string    `json:"sport"`
+}
+
+type OrganizationReq struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// OrgAdminReq accepts a user uuid or email like AddMemberReq
+type OrgAdminReq struct {
+	Identifier string `json:"userid"`
+}
+
+// OrgMember is one entry of the organization wide member directory
+type OrgMember struct {
+	UserID    uuid.UUID       `json:"userid"`
+	Firstname string          `json:"firstName,omitempty"`
+	Lastname  string          `json:"lastName,omitempty"`
+	Email     string          `json:"email,omitempty"`
+	IsAdmin   bool            `json:"isAdmin"`
+	Teams     []OrgMemberTeam `json:"teams"`
+}
+
+type OrgMemberTeam struct {
+	TeamID   uuid.UUID `json:"teamid"`
+	TeamName string    `json:"teamName"`
+	Role     string    `json:"role"`
+}
+
+type MoveTeamReq struct {
+	OrganizationID uuid.UUID `json:"organizationid"`
+}
+
+// season statuses, only one season per team can be ACTIVE
+const (
+	SeasonStatusPlanned   = "PLANNED"
+	SeasonStatusActive    = "ACTIVE"
+	SeasonStatusCompleted = "COMPLETED"
+)
+
+// SeasonDateLayout is used for season start/end dates and roster date queries
+const SeasonDateLayout = "2006-01-02"
+
+type Season struct {
+	SeasonID  uuid.UUID `json:"seasonid"`
+	TeamID    uuid.UUID `json:"teamid"`
+	Name      string    `json:"name"`
+	StartDate string    `json:"startDate"`
+	EndDate   string    `json:"endDate"`
+	Status    string    `json:"status"`
+	Createdat time.Time `json:"createdat"`
+	Updatedat time.Time `json:"updatedat"`
+}
+
+type SeasonReq struct {
+	Name      string `json:"name"`
+	StartDate string `json:"startDate"`
+	EndDate   string `json:"endDate"`
+	Status    string `json:"status"`
+}
+
+// RolloverReq copies a roster into a season, FromSeasonID defaults to the previous season
+type RolloverReq struct {
+	FromSeasonID *uuid.UUID `json:"fromSeasonid"`
+}
+
+type SeasonRosterMember struct {
+	UserID       uuid.UUID  `json:"userid"`
+	Role         string     `json:"role"`
+	JerseyNumber *int       `json:"jerseyNumber,omitempty"`
+	Joinedat     time.Time  `json:"joinedat"`
+	Leftat       *time.Time `json:"leftat,omitempty"`
+	Firstname    string     `json:"firstName,omitempty"`
+	Lastname     string     `json:"lastName,omitempty"`
+	Email        string     `json:"email,omitempty"`
+}
+
+// SeasonRoster is a season's roster, Season is nil when no season covers a requested date
+type SeasonRoster struct {
+	Season  *Season              `json:"season"`
+	Members []SeasonRosterMember `json:"members"`
+}
+
+// roster import row actions
+const (
+	RosterImportAdd    = "ADD"
+	RosterImportUpdate = "UPDATE"
+	RosterImportInvite = "INVITE"
+	RosterImportError  = "ERROR"
+)
+
+// RosterImportRow is one csv line of a roster import, Line is the line number in the file
+type RosterImportRow struct {
+	Line     int        `json:"line"`
+	Email    string     `json:"email"`
+	Role     string     `json:"role,omitempty"`
+	Number   *int       `json:"number,omitempty"`
+	Position string     `json:"position,omitempty"`
+	UserID   *uuid.UUID `json:"userid,omitempty"`
+	Action   string     `json:"action"`
+	Errors   []string   `json:"errors,omitempty"`
+	Notes    []string   `json:"notes,omitempty"`
+}
+
+type RosterImportOptions struct {
+	DryRun        bool
+	InviteUnknown bool
+}
+
+// RosterImportReport is returned for every import, nothing is written unless Committed is true
+type RosterImportReport struct {
+	DryRun    bool              `json:"dryRun"`
+	Committed bool              `json:"committed"`
+	Added     int               `json:"added"`
+	Updated   int               `json:"updated"`
+	Invited   int               `json:"invited"`
+	Failed    int               `json:"failed"`
+	Errors    []string          `json:"errors,omitempty"`
+	Rows      []RosterImportRow `json:"rows"`
+}
+
+// RosterExportRow starts with the import columns so an export can be edited and imported again
+type RosterExportRow struct {
+	Email             string    `json:"email"`
+	Role              string    `json:"role"`
+	Number            *int      `json:"number,omitempty"`
+	Position          string    `json:"position,omitempty"`
+	SecondaryPosition string    `json:"secondaryPosition,omitempty"`
+	Firstname         string    `json:"firstName,omitempty"`
+	Lastname          string    `json:"lastName,omitempty"`
+	UserID            uuid.UUID `json:"userid"`
+	Joinedat          time.Time `json:"joinedat"`
+}
+
+// invite statuses
+const (
+	InviteStatusPending  = "PENDING"
+	InviteStatusAccepted = "ACCEPTED"
+	InviteStatusDeclined = "DECLINED"
+	InviteStatusRevoked  = "REVOKED"
+	InviteStatusExpired  = "EXPIRED"
+)
+
+type TeamInvite struct {
+	InviteID      uuid.UUID  `json:"inviteid"`
+	TeamID        uuid.UUID  `json:"teamid"`
+	TeamName      string     `json:"teamName,omitempty"`
+	InvitedBy     uuid.UUID  `json:"invitedby"`
+	InviteeUserID *uuid.UUID `json:"inviteeUserid,omitempty"`
+	InviteeEmail  string     `json:"inviteeEmail,omitempty"`
+	Code          string     `json:"code,omitempty"`
+	Link          string     `json:"link,omitempty"`
+	Role          string     `json:"role"`
+	MaxUses       *int       `json:"maxUses,omitempty"`
+	Uses          int        `json:"uses"`
+	Status        string     `json:"status"`
+	ExpiresAt     *time.Time `json:"expiresat,omitempty"`
+	RespondedAt   *time.Time `json:"respondedat,omitempty"`
+	Createdat     time.Time  `json:"createdat"`
+}
+
+// CreateInviteReq targets a single user when Invitee (uuid or email) is set,
+// otherwise a reusable join code is generated
+type CreateInviteReq struct {
+	Invitee   string     `json:"invitee"`
+	Role      string     `json:"role"`
+	MaxUses   *int       `json:"maxUses"`
+	ExpiresAt *time.Time `json:"expiresat"`
+}
+
 func NewTeam(teamID uuid.UUID, name string, sport string, description string, createdat, updatedat time.Time) (*Team, error) {
 	return &Team{
 		TeamID:      uuid.New(),
@@ -0,0 +1,132 @@
+package permissions
+
+import "sort"
+
+// named permissions checked by team-service and, over gRPC CheckPermission, by the other services
+const (
+	TeamUpdate        = "team.update"
+	TeamDelete        = "team.delete"
+	RosterView        = "roster.view"
+	RosterManage      = "roster.manage"
+	RolesManage       = "roles.manage"
+	EventsView        = "events.view"
+	EventsCreate      = "events.create"
+	EventsManage      = "events.manage"
+	AttendanceTake    = "attendance.take"
+	WorkoutsView      = "workouts.view"
+	WorkoutsAssign    = "workouts.assign"
+	StatsManage       = "stats.manage"
+	AnnouncementsPost = "announcements.post"
+)
+
+// built in team roles, every team has them
+const (
+	RoleCoach   = "coach"
+	RoleManager = "manager"
+	RolePlayer  = "player"
+)
+
+var all = []string{
+	TeamUpdate,
+	TeamDelete,
+	RosterView,
+	RosterManage,
+	RolesManage,
+	EventsView,
+	EventsCreate,
+	EventsManage,
+	AttendanceTake,
+	WorkoutsView,
+	WorkoutsAssign,
+	StatsManage,
+	AnnouncementsPost,
+}
+
+// defaultMatrix maps the built in roles to their permissions
+var defaultMatrix = map[string][]string{
+	RoleCoach: all,
+	RoleManager: {
+		TeamUpdate,
+		RosterView,
+		RosterManage,
+		EventsView,
+		EventsCreate,
+		EventsManage,
+		AttendanceTake,
+		WorkoutsView,
+		WorkoutsAssign,
+		StatsManage,
+		AnnouncementsPost,
+	},
+	RolePlayer: {
+		RosterView,
+		EventsView,
+		WorkoutsView,
+	},
+}
+
+// All returns every known permission
+func All() []string {
+	out := make([]string, len(all))
+	copy(out, all)
+	return out
+}
+
+// IsValid reports whether p is a known permission
+func IsValid(p string) bool {
+	for _, known := range all {
+		if known == p {
+			return true
+		}
+	}
+	return false
+}
+
+// IsReadOnly reports whether p only grants viewing, read-only permissions
+// still apply to archived teams
+func IsReadOnly(p string) bool {
+	return p == RosterView || p == EventsView || p == WorkoutsView
+}
+
+// IsBuiltinRole reports whether role is one of coach, manager or player
+func IsBuiltinRole(role string) bool {
+	_, ok := defaultMatrix[role]
+	return ok
+}
+
+// BuiltinRoles returns the built in roles sorted by name
+func BuiltinRoles() []string {
+	roles := make([]string, 0, len(defaultMatrix))
+	for role := range defaultMatrix {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+	return roles
+}
+
+// GuardedRole reports whether handing out or taking away role needs roles.manage. Custom roles
+// can grant anything, so only built in roles without roles.manage are left to roster.manage
+func GuardedRole(role string) bool {
+	return !IsBuiltinRole(role) || Has(defaultMatrix[role], RolesManage)
+}
+
+// ForBuiltinRole returns the permissions of a built in role, nil for unknown roles
+func ForBuiltinRole(role string) []string {
+	perms, ok := defaultMatrix[role]
+	if !ok {
+		return nil
+	}
+	out := make([]string, len(perms))
+	copy(out, perms)
+	return out
+}
+
+// Has reports whether the permission set grants p
+func Has(granted []string, p string) bool {
+	for _, g := range granted {
+		if g == p {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+package permissions
+
+import "testing"
+
+func TestBuiltinRoleMatrix(t *testing.T) {
+	tests := []struct {
+		role       string
+		permission string
+		want       bool
+	}{
+		{RoleCoach, RolesManage, true},
+		{RoleCoach, EventsCreate, true},
+		{RoleManager, RosterManage, true},
+		{RoleManager, EventsCreate, true},
+		{RoleManager, RolesManage, false},
+		{RoleManager, TeamDelete, false},
+		{RolePlayer, EventsView, true},
+		{RolePlayer, EventsCreate, false},
+		{RolePlayer, RosterManage, false},
+		{"unknown", EventsView, false},
+	}
+
+	for _, tt := range tests {
+		if got := Has(ForBuiltinRole(tt.role), tt.permission); got != tt.want {
+			t.Errorf("role %q permission %q: got %v, want %v", tt.role, tt.permission, got, tt.want)
+		}
+	}
+}
+
+func TestCoachHoldsEveryPermission(t *testing.T) {
+	for _, p := range All() {
+		if !Has(ForBuiltinRole(RoleCoach), p) {
+			t.Errorf("coach is missing %q", p)
+		}
+	}
+}
+
+func TestGuardedRole(t *testing.T) {
+	tests := map[string]bool{
+		RoleCoach:   true,
+		RoleManager: false,
+		RolePlayer:  false,
+		"captain":   true,
+	}
+	for role, want := range tests {
+		if got := GuardedRole(role); got != want {
+			t.Errorf("GuardedRole(%q) = %v, want %v", role, got, want)
+		}
+	}
+}
+
+func TestForBuiltinRoleReturnsCopy(t *testing.T) {
+	perms := ForBuiltinRole(RolePlayer)
+	perms[0] = RolesManage
+
+	if Has(ForBuiltinRole(RolePlayer), RolesManage) {
+		t.Fatal("mutating the returned slice changed the default matrix")
+	}
+}
+
+func TestIsValid(t *testing.T) {
+	if !IsValid(WorkoutsAssign) {
+		t.Errorf("%q should be valid", WorkoutsAssign)
+	}
+	if IsValid("roster.destroy") {
+		t.Error("unknown permission reported as valid")
+	}
+}
+
+func TestPlayerPermissionsAreReadOnly(t *testing.T) {
+	//players keep their access to archived teams, so everything they hold must be read-only
+	for _, p := range ForBuiltinRole(RolePlayer) {
+		if !IsReadOnly(p) {
+			t.Errorf("player permission %q is not read-only", p)
+		}
+	}
+	if IsReadOnly(RosterManage) || IsReadOnly(EventsCreate) {
+		t.Error("write permission reported as read-only")
+	}
+}
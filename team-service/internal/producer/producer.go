@@ -2,16 +2,19 @@ package internal
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
 	"os"
 
 	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/wycliff-ochieng/common_packages/events"
 )
 
+// ProducerName is stamped on the envelope of every event this service publishes
+const ProducerName = "team-service"
+
 type KafkaProducer interface {
-	PublishTeamUpdate(ctx context.Context, teamData interface{}) error
+	PublishTeamUpdate(ctx context.Context, event events.Payload) error
 }
 
 type UpdateTeam struct {
@@ -28,11 +31,12 @@ func NewUpdateTeam(p *kafka.Producer, topic string) *UpdateTeam {
 	}
 }
 
-func (c *UpdateTeam) PublishTeamUpdate(ctx context.Context, teamData interface{}) error {
+// PublishTeamUpdate validates the event and publishes it wrapped in the shared envelope
+func (c *UpdateTeam) PublishTeamUpdate(ctx context.Context, event events.Payload) error {
 
-	data, err := json.Marshal(teamData)
+	data, err := events.Marshal(ProducerName, event)
 	if err != nil {
-		return fmt.Errorf("failed to marshal data: %s", err)
+		return fmt.Errorf("failed to marshal %s: %w", event.EventType(), err)
 	}
 
 	err = c.producer.Produce(&kafka.Message{
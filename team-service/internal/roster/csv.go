@@ -0,0 +1,164 @@
+package roster
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"github/wycliff-ochieng/internal/models"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxImportRows bounds a single import, bigger squads can be split over several files
+const MaxImportRows = 500
+
+var ErrMissingEmailColumn = errors.New("csv header must contain an email column")
+var ErrTooManyRows = fmt.Errorf("csv has more than %d rows", MaxImportRows)
+var ErrEmptyFile = errors.New("csv has no rows")
+
+// ImportColumns is the header written on export and understood on import
+var ImportColumns = []string{"email", "role", "number", "position"}
+
+var exportColumns = append(append([]string{}, ImportColumns...), "secondary_position", "first_name", "last_name", "userid", "joinedat")
+
+// ParseImport reads a roster csv with a header row. Column order is free and unknown
+// columns are ignored, only email is required. Problems with a single line are reported
+// on the row so the whole file can be validated in one pass.
+func ParseImport(r io.Reader) ([]models.RosterImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, ErrEmptyFile
+		}
+		return nil, err
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if _, seen := columns[name]; !seen {
+			columns[name] = i
+		}
+	}
+	if _, ok := columns["email"]; !ok {
+		return nil, ErrMissingEmailColumn
+	}
+
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	rows := make([]models.RosterImportRow, 0)
+	emails := map[string]int{}
+	numbers := map[int]int{}
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+
+		if isBlank(record) {
+			continue
+		}
+		if len(rows) == MaxImportRows {
+			return nil, ErrTooManyRows
+		}
+
+		row := models.RosterImportRow{
+			Line:     line,
+			Email:    strings.ToLower(field(record, "email")),
+			Role:     strings.ToLower(field(record, "role")),
+			Position: field(record, "position"),
+		}
+
+		if row.Email == "" || !strings.Contains(row.Email, "@") {
+			row.Errors = append(row.Errors, "invalid email")
+		} else if first, dup := emails[row.Email]; dup {
+			row.Errors = append(row.Errors, fmt.Sprintf("email already listed on line %d", first))
+		} else {
+			emails[row.Email] = line
+		}
+
+		if raw := field(record, "number"); raw != "" {
+			n, err := strconv.Atoi(raw)
+			switch {
+			case err != nil || n < 0 || n > 999:
+				row.Errors = append(row.Errors, "number must be between 0 and 999")
+			default:
+				if first, dup := numbers[n]; dup {
+					row.Errors = append(row.Errors, fmt.Sprintf("number %d already used on line %d", n, first))
+				} else {
+					numbers[n] = line
+				}
+				row.Number = &n
+			}
+		}
+
+		if len(row.Errors) > 0 {
+			row.Action = models.RosterImportError
+		}
+		rows = append(rows, row)
+	}
+
+	if len(rows) == 0 {
+		return nil, ErrEmptyFile
+	}
+	return rows, nil
+}
+
+func isBlank(record []string) bool {
+	for _, v := range record {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// WriteExport writes the roster as csv, the first columns match ParseImport
+func WriteExport(w io.Writer, rows []models.RosterExportRow) error {
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write(exportColumns); err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		number := ""
+		if row.Number != nil {
+			number = strconv.Itoa(*row.Number)
+		}
+		record := []string{
+			row.Email,
+			row.Role,
+			number,
+			row.Position,
+			row.SecondaryPosition,
+			row.Firstname,
+			row.Lastname,
+			row.UserID.String(),
+			row.Joinedat.UTC().Format(time.RFC3339),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
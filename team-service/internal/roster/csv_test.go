@@ -0,0 +1,118 @@
+package roster
+
+import (
+	"bytes"
+	"errors"
+	"github/wycliff-ochieng/internal/models"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestParseImport(t *testing.T) {
+	input := "Position,EMAIL,number,role,notes\n" +
+		"QB, Alice@Example.com ,12,player,captain\n" +
+		"\n" +
+		"WR,bob@example.com,,coach,\n" +
+		"LB,alice@example.com,7,,\n" +
+		"CB,not-an-email,1000,,\n" +
+		"TE,carol@example.com,12,,\n"
+
+	rows, err := ParseImport(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rows) != 5 {
+		t.Fatalf("got %d rows, want 5", len(rows))
+	}
+
+	first := rows[0]
+	if first.Line != 2 || first.Email != "alice@example.com" || first.Position != "QB" || first.Role != "player" {
+		t.Errorf("unexpected first row: %+v", first)
+	}
+	if first.Number == nil || *first.Number != 12 || len(first.Errors) != 0 {
+		t.Errorf("unexpected first row number/errors: %+v", first)
+	}
+
+	if bob := rows[1]; bob.Line != 4 || bob.Number != nil || bob.Role != "coach" || bob.Action != "" {
+		t.Errorf("unexpected second row: %+v", bob)
+	}
+
+	tests := []struct {
+		row  int
+		want string
+	}{
+		{2, "email already listed on line 2"},
+		{3, "invalid email"},
+		{3, "number must be between 0 and 999"},
+		{4, "number 12 already used on line 2"},
+	}
+	for _, tt := range tests {
+		row := rows[tt.row]
+		if row.Action != models.RosterImportError {
+			t.Errorf("line %d: action %q, want %q", row.Line, row.Action, models.RosterImportError)
+		}
+		found := false
+		for _, e := range row.Errors {
+			if e == tt.want {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("line %d: errors %v, want %q", row.Line, row.Errors, tt.want)
+		}
+	}
+}
+
+func TestParseImportRejectsBadFiles(t *testing.T) {
+	if _, err := ParseImport(strings.NewReader("")); !errors.Is(err, ErrEmptyFile) {
+		t.Errorf("empty file: got %v", err)
+	}
+	if _, err := ParseImport(strings.NewReader("email,role\n")); !errors.Is(err, ErrEmptyFile) {
+		t.Errorf("header only: got %v", err)
+	}
+	if _, err := ParseImport(strings.NewReader("name,role\nbob,player\n")); !errors.Is(err, ErrMissingEmailColumn) {
+		t.Errorf("missing email column: got %v", err)
+	}
+
+	var b strings.Builder
+	b.WriteString("email\n")
+	for i := 0; i <= MaxImportRows; i++ {
+		b.WriteString("p@example.com\n")
+	}
+	if _, err := ParseImport(strings.NewReader(b.String())); !errors.Is(err, ErrTooManyRows) {
+		t.Errorf("too many rows: got %v", err)
+	}
+}
+
+func TestExportRoundTrip(t *testing.T) {
+	number := 9
+	exported := []models.RosterExportRow{
+		{Email: "alice@example.com", Role: "player", Number: &number, Position: "QB", Firstname: "Alice", UserID: uuid.New(), Joinedat: time.Now()},
+		{Email: "bob@example.com", Role: "coach", UserID: uuid.New(), Joinedat: time.Now()},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteExport(&buf, exported); err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+
+	rows, err := ParseImport(&buf)
+	if err != nil {
+		t.Fatalf("re-import failed: %v", err)
+	}
+	if len(rows) != len(exported) {
+		t.Fatalf("got %d rows, want %d", len(rows), len(exported))
+	}
+	for i, row := range rows {
+		want := exported[i]
+		if row.Email != want.Email || row.Role != want.Role || row.Position != want.Position {
+			t.Errorf("row %d: got %+v, want %+v", i, row, want)
+		}
+		if (row.Number == nil) != (want.Number == nil) || (row.Number != nil && *row.Number != *want.Number) {
+			t.Errorf("row %d: number %v, want %v", i, row.Number, want.Number)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github/wycliff-ochieng/internal/models"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wycliff-ochieng/common_packages/events"
+)
+
+// ActivityCursor is the position of the last entry on an activity page
+type ActivityCursor struct {
+	Createdat  time.Time
+	ActivityID uuid.UUID
+}
+
+// activityEntry is what the feed keeps of a domain event, uuid.Nil ids are stored as NULL
+type activityEntry struct {
+	teamID    uuid.UUID
+	kind      string
+	actorID   uuid.UUID
+	subjectID uuid.UUID
+}
+
+// activityTypes are the types GET activity can filter on
+var activityTypes = map[string]bool{
+	events.RosterMemberAdded:       true,
+	events.RosterRoleChanged:       true,
+	events.RosterMemberRemoved:     true,
+	events.RosterMemberLeft:        true,
+	events.RosterAccountDeleted:    true,
+	events.RosterMemberSuspended:   true,
+	events.RosterMemberReactivated: true,
+	events.RosterCoachReassigned:   true,
+
+	models.ActivityMemberJoined:        true,
+	models.ActivityTeamUpdated:         true,
+	models.ActivityOrganizationChanged: true,
+	models.ActivityJoinRequested:       true,
+	models.ActivityJoinRequestApproved: true,
+	models.ActivityJoinRequestRejected: true,
+	models.ActivityTeamArchived:        true,
+	models.ActivityDeletionScheduled:   true,
+	models.ActivityTeamRestored:        true,
+	models.ActivityCoachMissing:        true,
+	models.ActivityAnnouncementPosted:  true,
+	models.ActivityEventCreated:        true,
+	models.ActivityEventCancelled:      true,
+}
+
+// activityFor maps an event to its feed entry, false for events that are not about a single team
+// (user events, and TeamDeleted whose feed is gone with the team)
+func activityFor(p events.Payload) (activityEntry, bool) {
+	switch e := p.(type) {
+	case *events.TeamMemberJoined:
+		return activityEntry{e.TeamID, models.ActivityMemberJoined, e.UserID, e.UserID}, true
+	case *events.TeamRosterChanged:
+		return activityEntry{e.TeamID, e.ChangeType, e.ChangedBy, e.UserID}, true
+	case *events.TeamUpdated:
+		return activityEntry{e.TeamID, models.ActivityTeamUpdated, e.UpdatedBy, uuid.Nil}, true
+	case *events.TeamOrganizationChanged:
+		return activityEntry{e.TeamID, models.ActivityOrganizationChanged, e.ChangedBy, uuid.Nil}, true
+	case *events.TeamJoinRequested:
+		return activityEntry{e.TeamID, models.ActivityJoinRequested, e.UserID, e.UserID}, true
+	case *events.TeamJoinRequestDecided:
+		kind := models.ActivityJoinRequestRejected
+		if e.Status == models.JoinRequestApproved {
+			kind = models.ActivityJoinRequestApproved
+		}
+		return activityEntry{e.TeamID, kind, e.DecidedBy, e.UserID}, true
+	case *events.TeamArchived:
+		kind := models.ActivityTeamArchived
+		if e.DeleteAfter != nil {
+			kind = models.ActivityDeletionScheduled
+		}
+		return activityEntry{e.TeamID, kind, e.ArchivedBy, uuid.Nil}, true
+	case *events.TeamRestored:
+		return activityEntry{e.TeamID, models.ActivityTeamRestored, e.RestoredBy, uuid.Nil}, true
+	case *events.TeamCoachMissing:
+		return activityEntry{e.TeamID, models.ActivityCoachMissing, uuid.Nil, e.UserID}, true
+	case *events.TeamAnnouncementPosted:
+		return activityEntry{e.TeamID, models.ActivityAnnouncementPosted, e.PostedBy, e.AnnouncementID}, true
+	case *events.EventCreated:
+		return activityEntry{e.TeamID, models.ActivityEventCreated, e.CreatedBy, e.EventID}, true
+	case *events.EventCancelled:
+		return activityEntry{e.TeamID, models.ActivityEventCancelled, e.CancelledBy, e.EventID}, true
+	}
+	return activityEntry{}, false
+}
+
+func nullableUUID(id uuid.UUID) *uuid.UUID {
+	if id == uuid.Nil {
+		return nil
+	}
+	return &id
+}
+
+// RecordActivity adds the feed entry of a consumed event. Our own team events come back through
+// team_events like everyone else's, so the feed only ever shows what was actually published.
+// Redeliveries and events of teams deleted since are skipped
+func (ts *TeamService) RecordActivity(ctx context.Context, env *events.Envelope, payload events.Payload) error {
+	entry, ok := activityFor(payload)
+	if !ok {
+		return nil
+	}
+
+	query := `INSERT INTO team_activity(team_id,event_id,type,actor_id,subject_id,source,data,createdat)
+	SELECT $1::uuid,$2::uuid,$3::varchar,$4::uuid,$5::uuid,$6::varchar,$7::jsonb,$8::timestamp
+	WHERE EXISTS(SELECT 1 FROM teams WHERE id=$1::uuid)
+	ON CONFLICT (event_id) DO NOTHING`
+	_, err := ts.db.ExecContext(ctx, query, entry.teamID, env.ID, entry.kind, nullableUUID(entry.actorID), nullableUUID(entry.subjectID),
+		env.Producer, string(env.Payload), env.OccurredAt.UTC())
+	return err
+}
+
+// GET :: the team's activity feed, newest first, only for members of the team
+func (ts *TeamService) ListActivity(ctx context.Context, teamID uuid.UUID, userID uuid.UUID, params models.ListActivityParams) (*models.PaginatedActivity, error) {
+
+	isMember, err := ts.IsTeamMember(ctx, userID, teamID)
+	if err != nil {
+		return nil, err
+	}
+	if !isMember {
+		return nil, ErrForbidden
+	}
+
+	for _, t := range params.Types {
+		if !activityTypes[t] {
+			log.Printf("unknown activity type %q", t)
+			return nil, ErrBadRequest
+		}
+	}
+
+	var cursor *ActivityCursor
+	if params.Cursor != "" {
+		cursor = &ActivityCursor{}
+		if err := decodeCursor(params.Cursor, cursor); err != nil {
+			log.Printf("invalid activity cursor %q", params.Cursor)
+			return nil, err
+		}
+	}
+
+	var queryBuilder strings.Builder
+	queryBuilder.WriteString(`SELECT id,team_id,type,actor_id,subject_id,source,data,createdat FROM team_activity WHERE team_id = $1`)
+
+	args := []interface{}{teamID}
+	paramIndex := 2
+
+	if len(params.Types) > 0 {
+		placeholders := make([]string, 0, len(params.Types))
+		for _, t := range params.Types {
+			placeholders = append(placeholders, fmt.Sprintf("$%d", paramIndex))
+			args = append(args, t)
+			paramIndex++
+		}
+		queryBuilder.WriteString(" AND type IN (" + strings.Join(placeholders, ",") + ")")
+	}
+	if cursor != nil {
+		queryBuilder.WriteString(fmt.Sprintf(" AND (createdat, id) < ($%d, $%d)", paramIndex, paramIndex+1))
+		args = append(args, cursor.Createdat, cursor.ActivityID)
+		paramIndex += 2
+	}
+
+	//one extra row tells whether there is a next page
+	queryBuilder.WriteString(fmt.Sprintf(" ORDER BY createdat DESC, id DESC LIMIT $%d", paramIndex))
+	args = append(args, params.Limit+1)
+
+	rows, err := ts.db.QueryContext(ctx, queryBuilder.String(), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := make([]models.TeamActivity, 0)
+	for rows.Next() {
+		var a models.TeamActivity
+		var data []byte
+		if err := rows.Scan(&a.ActivityID, &a.TeamID, &a.Type, &a.ActorID, &a.SubjectID, &a.Source, &data, &a.Createdat); err != nil {
+			return nil, err
+		}
+		a.Data = data
+		entries = append(entries, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	page := &models.PaginatedActivity{Data: entries}
+	if len(entries) > params.Limit {
+		page.Data = entries[:params.Limit]
+		last := page.Data[len(page.Data)-1]
+		nextCursor, err := encodeCursor(ActivityCursor{Createdat: last.Createdat, ActivityID: last.ActivityID})
+		if err != nil {
+			return nil, err
+		}
+		page.NextCursor = nextCursor
+	}
+
+	//names are best effort, the feed still loads when user-service is down
+	actorIDs := make([]uuid.UUID, 0, len(page.Data))
+	for _, a := range page.Data {
+		if a.ActorID != nil {
+			actorIDs = append(actorIDs, *a.ActorID)
+		}
+	}
+	profiles := ts.fetchProfiles(ctx, actorIDs)
+	for i := range page.Data {
+		if page.Data[i].ActorID == nil {
+			continue
+		}
+		if profile, ok := profiles[page.Data[i].ActorID.String()]; ok {
+			page.Data[i].ActorName = strings.TrimSpace(profile.GetFirstname() + " " + profile.GetLastname())
+		}
+	}
+	return page, nil
+}
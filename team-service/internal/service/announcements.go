@@ -0,0 +1,291 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github/wycliff-ochieng/internal/models"
+	"github/wycliff-ochieng/internal/permissions"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wycliff-ochieng/common_packages/events"
+)
+
+// announcementColumns needs the viewing user as $1 for their own acknowledgement
+const announcementColumns = `a.id,a.team_id,a.author_id,a.title,a.body,a.pinned,a.requires_ack,a.expires_at,a.createdat,a.updatedat,
+	(SELECT COUNT(*) FROM announcement_acknowledgements ack JOIN team_members tm ON tm.team_id = a.team_id AND tm.user_id = ack.user_id
+	WHERE ack.announcement_id = a.id),
+	(SELECT COUNT(*) FROM team_members tm WHERE tm.team_id = a.team_id),
+	(SELECT ack.acknowledged_at FROM announcement_acknowledgements ack WHERE ack.announcement_id = a.id AND ack.user_id = $1)`
+
+func scanAnnouncement(row rowScanner) (*models.Announcement, error) {
+	var a models.Announcement
+	var expiresAt, acknowledgedAt sql.NullTime
+	err := row.Scan(&a.AnnouncementID, &a.TeamID, &a.AuthorID, &a.Title, &a.Body, &a.Pinned, &a.RequiresAck, &expiresAt,
+		&a.Createdat, &a.Updatedat, &a.AckCount, &a.RecipientCount, &acknowledgedAt)
+	if err != nil {
+		return nil, err
+	}
+	if expiresAt.Valid {
+		a.ExpiresAt = &expiresAt.Time
+	}
+	if acknowledgedAt.Valid {
+		a.AcknowledgedAt = &acknowledgedAt.Time
+	}
+	return &a, nil
+}
+
+func validateAnnouncementReq(req *models.AnnouncementReq) error {
+	req.Title = strings.TrimSpace(req.Title)
+	req.Body = strings.TrimSpace(req.Body)
+
+	if req.Title == "" || len(req.Title) > 200 || len(req.Body) > 5000 {
+		return ErrBadRequest
+	}
+	if req.ExpiresAt != nil {
+		if !req.ExpiresAt.After(time.Now()) {
+			return ErrBadRequest
+		}
+		expiresAt := req.ExpiresAt.UTC()
+		req.ExpiresAt = &expiresAt
+	}
+	return nil
+}
+
+// POST :: post an announcement to the roster and publish it for the notification pipeline
+func (ts *TeamService) PostAnnouncement(ctx context.Context, teamID uuid.UUID, reqUserID uuid.UUID, req models.AnnouncementReq) (*models.Announcement, error) {
+
+	if err := validateAnnouncementReq(&req); err != nil {
+		return nil, err
+	}
+
+	if _, err := ts.requirePermission(ctx, teamID, reqUserID, permissions.AnnouncementsPost); err != nil {
+		return nil, err
+	}
+
+	var announcementID uuid.UUID
+	query := `INSERT INTO team_announcements(team_id,author_id,title,body,pinned,requires_ack,expires_at) VALUES($1,$2,$3,$4,$5,$6,$7) RETURNING id`
+	err := ts.db.QueryRowContext(ctx, query, teamID, reqUserID, req.Title, req.Body, req.Pinned, req.RequiresAck, req.ExpiresAt).Scan(&announcementID)
+	if err != nil {
+		return nil, err
+	}
+
+	announcement, err := ts.getAnnouncement(ctx, teamID, announcementID, reqUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	recipients, err := ts.teamMemberIDs(ctx, ts.db, teamID)
+	if err != nil {
+		log.Printf("could not list recipients of announcement %s: %v", announcementID, err)
+	}
+
+	event := events.TeamAnnouncementPosted{
+		AnnouncementID: announcement.AnnouncementID,
+		TeamID:         teamID,
+		Title:          announcement.Title,
+		Body:           announcement.Body,
+		Pinned:         announcement.Pinned,
+		RequiresAck:    announcement.RequiresAck,
+		ExpiresAt:      announcement.ExpiresAt,
+		PostedBy:       reqUserID,
+		RecipientIDs:   recipients,
+	}
+	if err := ts.prod.PublishTeamUpdate(ctx, event); err != nil {
+		log.Printf("kafka error publishing %s: %s", events.TypeTeamAnnouncementPosted, err)
+	}
+
+	return announcement, nil
+}
+
+func (ts *TeamService) getAnnouncement(ctx context.Context, teamID uuid.UUID, announcementID uuid.UUID, viewerID uuid.UUID) (*models.Announcement, error) {
+	query := `SELECT ` + announcementColumns + ` FROM team_announcements a WHERE a.id=$2 AND a.team_id=$3`
+	announcement, err := scanAnnouncement(ts.db.QueryRowContext(ctx, query, viewerID, announcementID, teamID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return announcement, nil
+}
+
+// listAnnouncements returns the team's announcements as seen by viewerID, pinned first then newest
+func (ts *TeamService) listAnnouncements(ctx context.Context, teamID uuid.UUID, viewerID uuid.UUID, includeExpired bool) ([]models.Announcement, error) {
+	query := `SELECT ` + announcementColumns + ` FROM team_announcements a WHERE a.team_id=$2`
+	if !includeExpired {
+		query += ` AND (a.expires_at IS NULL OR a.expires_at > NOW())`
+	}
+	query += ` ORDER BY a.pinned DESC, a.createdat DESC`
+
+	rows, err := ts.db.QueryContext(ctx, query, viewerID, teamID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	announcements := make([]models.Announcement, 0)
+	authorIDs := make([]uuid.UUID, 0)
+	for rows.Next() {
+		announcement, err := scanAnnouncement(rows)
+		if err != nil {
+			return nil, err
+		}
+		announcements = append(announcements, *announcement)
+		authorIDs = append(authorIDs, announcement.AuthorID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	profiles := ts.fetchProfiles(ctx, authorIDs)
+	for i := range announcements {
+		if profile, ok := profiles[announcements[i].AuthorID.String()]; ok {
+			announcements[i].AuthorName = strings.TrimSpace(profile.GetFirstname() + " " + profile.GetLastname())
+		}
+	}
+	return announcements, nil
+}
+
+// GET :: announcements of a team, expired ones only when asked for
+func (ts *TeamService) ListAnnouncements(ctx context.Context, teamID uuid.UUID, reqUserID uuid.UUID, includeExpired bool) ([]models.Announcement, error) {
+
+	canView, err := ts.canViewTeam(ctx, teamID, reqUserID)
+	if err != nil {
+		return nil, err
+	}
+	if !canView {
+		return nil, ErrForbidden
+	}
+
+	return ts.listAnnouncements(ctx, teamID, reqUserID, includeExpired)
+}
+
+// PUT :: pin or unpin an announcement
+func (ts *TeamService) PinAnnouncement(ctx context.Context, teamID uuid.UUID, announcementID uuid.UUID, reqUserID uuid.UUID, pinned bool) (*models.Announcement, error) {
+
+	if _, err := ts.requirePermission(ctx, teamID, reqUserID, permissions.AnnouncementsPost); err != nil {
+		return nil, err
+	}
+
+	query := `UPDATE team_announcements SET pinned=$1, updatedat=NOW() WHERE id=$2 AND team_id=$3`
+	result, err := ts.db.ExecContext(ctx, query, pinned, announcementID, teamID)
+	if err != nil {
+		return nil, err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return nil, err
+	} else if n == 0 {
+		return nil, ErrNotFound
+	}
+
+	return ts.getAnnouncement(ctx, teamID, announcementID, reqUserID)
+}
+
+// DELETE :: remove an announcement together with its acknowledgements
+func (ts *TeamService) DeleteAnnouncement(ctx context.Context, teamID uuid.UUID, announcementID uuid.UUID, reqUserID uuid.UUID) error {
+
+	if _, err := ts.requirePermission(ctx, teamID, reqUserID, permissions.AnnouncementsPost); err != nil {
+		return err
+	}
+
+	result, err := ts.db.ExecContext(ctx, `DELETE FROM team_announcements WHERE id=$1 AND team_id=$2`, announcementID, teamID)
+	if err != nil {
+		return err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// POST :: a member confirms reading an announcement that asks for it, repeating it is a no-op
+func (ts *TeamService) AcknowledgeAnnouncement(ctx context.Context, teamID uuid.UUID, announcementID uuid.UUID, reqUserID uuid.UUID) (*models.Announcement, error) {
+
+	if err := ts.requireWritableTeam(ctx, teamID); err != nil {
+		return nil, err
+	}
+
+	isMember, err := ts.IsTeamMember(ctx, reqUserID, teamID)
+	if err != nil {
+		return nil, err
+	}
+	if !isMember {
+		return nil, ErrForbidden
+	}
+
+	announcement, err := ts.getAnnouncement(ctx, teamID, announcementID, reqUserID)
+	if err != nil {
+		return nil, err
+	}
+	if !announcement.RequiresAck {
+		return nil, ErrBadRequest
+	}
+
+	query := `INSERT INTO announcement_acknowledgements(announcement_id,user_id) VALUES($1,$2) ON CONFLICT (announcement_id,user_id) DO NOTHING`
+	if _, err := ts.db.ExecContext(ctx, query, announcementID, reqUserID); err != nil {
+		return nil, err
+	}
+
+	return ts.getAnnouncement(ctx, teamID, announcementID, reqUserID)
+}
+
+// GET :: who has and has not acknowledged an announcement, for the members who can post
+func (ts *TeamService) GetAnnouncementAcks(ctx context.Context, teamID uuid.UUID, announcementID uuid.UUID, reqUserID uuid.UUID) ([]models.AnnouncementAck, error) {
+
+	//reading the acknowledgements stays possible on archived teams
+	allowed, _, err := ts.HasPermission(ctx, teamID, reqUserID, permissions.AnnouncementsPost)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, ErrForbidden
+	}
+
+	if _, err := ts.getAnnouncement(ctx, teamID, announcementID, reqUserID); err != nil {
+		return nil, err
+	}
+
+	query := `SELECT tm.user_id, ack.acknowledged_at FROM team_members tm
+	LEFT JOIN announcement_acknowledgements ack ON ack.announcement_id=$1 AND ack.user_id = tm.user_id
+	WHERE tm.team_id=$2 ORDER BY ack.acknowledged_at NULLS LAST, tm.user_id`
+	rows, err := ts.db.QueryContext(ctx, query, announcementID, teamID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	acks := make([]models.AnnouncementAck, 0)
+	userIDs := make([]uuid.UUID, 0)
+	for rows.Next() {
+		var ack models.AnnouncementAck
+		var acknowledgedAt sql.NullTime
+		if err := rows.Scan(&ack.UserID, &acknowledgedAt); err != nil {
+			return nil, err
+		}
+		if acknowledgedAt.Valid {
+			ack.AcknowledgedAt = &acknowledgedAt.Time
+		}
+		acks = append(acks, ack)
+		userIDs = append(userIDs, ack.UserID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	profiles := ts.fetchProfiles(ctx, userIDs)
+	for i := range acks {
+		if profile, ok := profiles[acks[i].UserID.String()]; ok {
+			acks[i].Firstname = profile.GetFirstname()
+			acks[i].Lastname = profile.GetLastname()
+		}
+	}
+	return acks, nil
+}
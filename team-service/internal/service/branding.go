@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github/wycliff-ochieng/internal/models"
+	"github/wycliff-ochieng/internal/permissions"
+	"log"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/minio/minio-go/v7"
+)
+
+var ErrFileStoreUnavailable = errors.New("file storage is not configured")
+
+var hexColor = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// logos are uploaded straight to minio, these bound how long the links stay valid
+const (
+	logoUploadExpiry = 15 * time.Minute
+	logoViewExpiry   = time.Hour
+)
+
+var allowedLogoTypes = []string{"image/jpeg", "image/png"}
+
+// brandingRow holds the nullable branding columns of teams while scanning
+type brandingRow struct {
+	logoKey   sql.NullString
+	primary   sql.NullString
+	secondary sql.NullString
+	venue     sql.NullString
+	founded   sql.NullInt64
+	links     []byte
+}
+
+// dest matches the column order logo_object_key,primary_color,secondary_color,home_venue,founded_year,social_links
+func (b *brandingRow) dest() []interface{} {
+	return []interface{}{&b.logoKey, &b.primary, &b.secondary, &b.venue, &b.founded, &b.links}
+}
+
+// branding converts the scanned columns, presigning the logo when one is stored
+func (ts *TeamService) branding(ctx context.Context, b *brandingRow) models.TeamBranding {
+	branding := models.TeamBranding{
+		PrimaryColor:   b.primary.String,
+		SecondaryColor: b.secondary.String,
+		HomeVenue:      b.venue.String,
+		FoundedYear:    int(b.founded.Int64),
+	}
+
+	if len(b.links) > 0 {
+		if err := json.Unmarshal(b.links, &branding.SocialLinks); err != nil {
+			log.Printf("ignoring malformed social links: %v", err)
+		}
+	}
+
+	if b.logoKey.Valid && b.logoKey.String != "" {
+		branding.LogoURL = ts.logoURL(ctx, b.logoKey.String)
+	}
+	return branding
+}
+
+func (ts *TeamService) logoURL(ctx context.Context, objectKey string) string {
+	if ts.files == nil || ts.files.Client == nil {
+		return ""
+	}
+	u, err := ts.files.Client.PresignedGetObject(ctx, ts.files.Bucket, objectKey, logoViewExpiry, nil)
+	if err != nil {
+		log.Printf("failed to presign logo %s: %v", objectKey, err)
+		return ""
+	}
+	return u.String()
+}
+
+func logoPrefix(teamID uuid.UUID) string {
+	return fmt.Sprintf("teams/%s/logo/", teamID)
+}
+
+func validateBrandingReq(req models.UpdateBrandingReq) error {
+	for _, c := range []string{req.PrimaryColor, req.SecondaryColor} {
+		if c != "" && !hexColor.MatchString(c) {
+			return ErrBadRequest
+		}
+	}
+
+	if len(req.HomeVenue) > 200 {
+		return ErrBadRequest
+	}
+
+	if req.FoundedYear != 0 && (req.FoundedYear < 1800 || req.FoundedYear > time.Now().Year()) {
+		return ErrBadRequest
+	}
+
+	for network, link := range req.SocialLinks {
+		if strings.TrimSpace(network) == "" {
+			return ErrBadRequest
+		}
+		u, err := url.Parse(link)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return ErrBadRequest
+		}
+	}
+	return nil
+}
+
+// POST :: presigned upload url for a new team logo
+func (ts *TeamService) GenerateLogoUploadURL(ctx context.Context, teamID uuid.UUID, reqUserID uuid.UUID, req models.LogoUploadReq) (*models.PresignedURLRes, error) {
+
+	isAllowed := false
+	for _, allowed := range allowedLogoTypes {
+		if req.MimeType == allowed {
+			isAllowed = true
+			break
+		}
+	}
+	if !isAllowed || req.Filename == "" {
+		return nil, ErrBadRequest
+	}
+
+	if _, err := ts.requirePermission(ctx, teamID, reqUserID, permissions.TeamUpdate); err != nil {
+		return nil, err
+	}
+
+	if ts.files == nil || ts.files.Client == nil {
+		return nil, ErrFileStoreUnavailable
+	}
+
+	objectKey := logoPrefix(teamID) + fmt.Sprintf("%s-%s", uuid.New().String(), path.Base(req.Filename))
+
+	u, err := ts.files.Client.PresignedPutObject(ctx, ts.files.Bucket, objectKey, logoUploadExpiry)
+	if err != nil {
+		log.Printf("error generating logo upload url due to: %s", err)
+		return nil, err
+	}
+
+	return &models.PresignedURLRes{
+		UploadURL: u.String(),
+		ObjectKey: objectKey,
+		ExpiresAT: time.Now().Add(logoUploadExpiry),
+	}, nil
+}
+
+// PUT :: store the uploaded logo against the team once the client finished the upload
+func (ts *TeamService) CompleteLogoUpload(ctx context.Context, teamID uuid.UUID, reqUserID uuid.UUID, req models.LogoUploadCompleteReq) (*models.Team, error) {
+
+	//only keys handed out for this team are accepted
+	if !strings.HasPrefix(req.ObjectKey, logoPrefix(teamID)) {
+		return nil, ErrBadRequest
+	}
+
+	if _, err := ts.requirePermission(ctx, teamID, reqUserID, permissions.TeamUpdate); err != nil {
+		return nil, err
+	}
+
+	if ts.files == nil || ts.files.Client == nil {
+		return nil, ErrFileStoreUnavailable
+	}
+
+	if _, err := ts.files.Client.StatObject(ctx, ts.files.Bucket, req.ObjectKey, minio.StatObjectOptions{}); err != nil {
+		log.Printf("logo %s was not uploaded: %v", req.ObjectKey, err)
+		return nil, ErrBadRequest
+	}
+
+	query := `UPDATE teams SET logo_object_key=$1, updatedat=NOW() WHERE id=$2`
+	result, err := ts.db.ExecContext(ctx, query, req.ObjectKey, teamID)
+	if err != nil {
+		return nil, err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return nil, err
+	} else if n == 0 {
+		return nil, ErrNotFound
+	}
+
+	return ts.GetTeamByID(ctx, teamID)
+}
+
+// PUT :: colours, home venue, founding year and social links
+func (ts *TeamService) UpdateTeamBranding(ctx context.Context, teamID uuid.UUID, reqUserID uuid.UUID, req models.UpdateBrandingReq) (*models.Team, error) {
+
+	if err := validateBrandingReq(req); err != nil {
+		return nil, err
+	}
+
+	if _, err := ts.requirePermission(ctx, teamID, reqUserID, permissions.TeamUpdate); err != nil {
+		return nil, err
+	}
+
+	if req.SocialLinks == nil {
+		req.SocialLinks = map[string]string{}
+	}
+	links, err := json.Marshal(req.SocialLinks)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `UPDATE teams SET primary_color=NULLIF($1,''), secondary_color=NULLIF($2,''), home_venue=NULLIF($3,''),
+	founded_year=NULLIF($4,0), social_links=$5, updatedat=NOW() WHERE id=$6`
+
+	result, err := ts.db.ExecContext(ctx, query, req.PrimaryColor, req.SecondaryColor, req.HomeVenue, req.FoundedYear, links, teamID)
+	if err != nil {
+		return nil, err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return nil, err
+	} else if n == 0 {
+		return nil, ErrNotFound
+	}
+
+	return ts.GetTeamByID(ctx, teamID)
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github/wycliff-ochieng/internal/models"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// ChatCursor is the position of the oldest message on a history page
+type ChatCursor struct {
+	Createdat time.Time
+	MessageID uuid.UUID
+}
+
+const chatMessageColumns = `id,team_id,user_id,body,COALESCE(client_id,''),createdat`
+
+func scanChatMessage(row rowScanner) (*models.ChatMessage, error) {
+	var m models.ChatMessage
+	if err := row.Scan(&m.MessageID, &m.TeamID, &m.UserID, &m.Body, &m.ClientID, &m.Createdat); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// SaveChatMessage stores a message of a member on the team channel. Membership is checked on every
+// message so removed members cannot keep posting on an open connection, a resend with the same
+// clientID returns the stored message
+func (ts *TeamService) SaveChatMessage(ctx context.Context, teamID uuid.UUID, userID uuid.UUID, body string, clientID string) (*models.ChatMessage, error) {
+
+	body = strings.TrimSpace(body)
+	if body == "" || utf8.RuneCountInString(body) > 4000 || len(clientID) > 64 {
+		return nil, ErrBadRequest
+	}
+
+	if err := ts.requireWritableTeam(ctx, teamID); err != nil {
+		return nil, err
+	}
+
+	isMember, err := ts.IsTeamMember(ctx, userID, teamID)
+	if err != nil {
+		return nil, err
+	}
+	if !isMember {
+		return nil, ErrForbidden
+	}
+
+	var client sql.NullString
+	if clientID != "" {
+		client = sql.NullString{String: clientID, Valid: true}
+	}
+
+	query := `INSERT INTO team_chat_messages(team_id,user_id,body,client_id) VALUES($1,$2,$3,$4)
+	ON CONFLICT (team_id,user_id,client_id) DO UPDATE SET client_id=EXCLUDED.client_id
+	RETURNING ` + chatMessageColumns
+	return scanChatMessage(ts.db.QueryRowContext(ctx, query, teamID, userID, body, client))
+}
+
+// GET :: chat history of a team, newest first, only for members of the team
+func (ts *TeamService) ListChatMessages(ctx context.Context, teamID uuid.UUID, userID uuid.UUID, limit int, cursorParam string) (*models.PaginatedChatMessages, error) {
+
+	isMember, err := ts.IsTeamMember(ctx, userID, teamID)
+	if err != nil {
+		return nil, err
+	}
+	if !isMember {
+		return nil, ErrForbidden
+	}
+
+	query := `SELECT ` + chatMessageColumns + ` FROM team_chat_messages WHERE team_id=$1`
+	args := []interface{}{teamID}
+
+	if cursorParam != "" {
+		var cursor ChatCursor
+		if err := decodeCursor(cursorParam, &cursor); err != nil {
+			log.Printf("invalid chat cursor %q", cursorParam)
+			return nil, err
+		}
+		query += ` AND (createdat, id) < ($2, $3)`
+		args = append(args, cursor.Createdat, cursor.MessageID)
+	}
+
+	//one extra row tells whether there is a next page
+	query += fmt.Sprintf(` ORDER BY createdat DESC, id DESC LIMIT $%d`, len(args)+1)
+	args = append(args, limit+1)
+
+	rows, err := ts.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	messages := make([]models.ChatMessage, 0)
+	for rows.Next() {
+		m, err := scanChatMessage(rows)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, *m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	page := &models.PaginatedChatMessages{Data: messages}
+	if len(messages) > limit {
+		page.Data = messages[:limit]
+		last := page.Data[len(page.Data)-1]
+		nextCursor, err := encodeCursor(ChatCursor{Createdat: last.Createdat, MessageID: last.MessageID})
+		if err != nil {
+			return nil, err
+		}
+		page.NextCursor = nextCursor
+	}
+	return page, nil
+}
@@ -0,0 +1,492 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github/wycliff-ochieng/internal/models"
+	"github/wycliff-ochieng/internal/permissions"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/wycliff-ochieng/common_packages/events"
+)
+
+var ErrJoinRequestPending = errors.New("a join request for this team is already pending")
+var ErrJoinRequestClosed = errors.New("join request was already decided")
+
+// TeamCursor is the position of the last team on a listing page
+type TeamCursor struct {
+	Createdat time.Time
+	TeamUUID  uuid.UUID
+}
+
+// encodeCursor and decodeCursor turn the last row of a page into an opaque cursor and back
+func encodeCursor(c interface{}) (string, error) {
+	cursorJSON, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(cursorJSON), nil
+}
+
+func decodeCursor(cursor string, c interface{}) error {
+	cursorJSON, err := base64.StdEncoding.DecodeString(cursor)
+	if err != nil {
+		return ErrBadRequest
+	}
+	if err := json.Unmarshal(cursorJSON, c); err != nil {
+		return ErrBadRequest
+	}
+	return nil
+}
+
+// likePattern escapes the ILIKE wildcards of user input
+func likePattern(search string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return "%" + replacer.Replace(search) + "%"
+}
+
+// teamListingQuery selects the limited profile of every team the user can see:
+// public teams, teams they are on and teams of organizations they administer. $1 is the user
+const teamListingQuery = `SELECT t.id,t.organization_id,t.name,t.sports,COALESCE(t.description,''),t.visibility,t.status,t.createdat,
+	t.logo_object_key,t.primary_color,t.secondary_color,t.home_venue,t.founded_year,t.social_links,
+	(SELECT COUNT(*) FROM team_members c WHERE c.team_id = t.id),
+	COALESCE(tm.role,''),
+	COALESCE((SELECT jr.status FROM team_join_requests jr WHERE jr.team_id = t.id AND jr.user_id = $1 ORDER BY jr.createdat DESC LIMIT 1),'')
+	FROM teams t LEFT JOIN team_members tm ON tm.team_id = t.id AND tm.user_id = $1
+	WHERE (t.visibility = 'public' OR tm.user_id IS NOT NULL
+	OR EXISTS(SELECT 1 FROM organization_admins oa WHERE oa.organization_id = t.organization_id AND oa.user_id = $1))`
+
+func (ts *TeamService) queryTeamListings(ctx context.Context, query string, args ...interface{}) ([]models.TeamListing, error) {
+	rows, err := ts.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	teams := make([]models.TeamListing, 0)
+	for rows.Next() {
+		var team models.TeamListing
+		var branding brandingRow
+
+		dest := []interface{}{
+			&team.TeamID,
+			&team.OrganizationID,
+			&team.Name,
+			&team.Sport,
+			&team.Description,
+			&team.Visibility,
+			&team.Status,
+			&team.Createdat,
+		}
+		dest = append(dest, branding.dest()...)
+		dest = append(dest, &team.MemberCount, &team.Role, &team.JoinRequestStatus)
+
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+
+		team.IsMember = team.Role != ""
+		full := ts.branding(ctx, &branding)
+		team.LogoURL = full.LogoURL
+		team.PrimaryColor = full.PrimaryColor
+		team.SecondaryColor = full.SecondaryColor
+		team.HomeVenue = full.HomeVenue
+
+		teams = append(teams, team)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return teams, nil
+}
+
+// GET :: discover teams, filtered by sport/name/organization/visibility with cursor pagination
+func (ts *TeamService) ListTeams(ctx context.Context, userID uuid.UUID, params models.ListTeamsParams) (*models.PaginatedTeams, error) {
+
+	if params.Visibility != "" && params.Visibility != models.TeamVisibilityPublic && params.Visibility != models.TeamVisibilityPrivate {
+		return nil, ErrBadRequest
+	}
+
+	var cursor *TeamCursor
+	if params.Cursor != "" {
+		cursor = &TeamCursor{}
+		if err := decodeCursor(params.Cursor, cursor); err != nil {
+			log.Printf("invalid team cursor %q", params.Cursor)
+			return nil, err
+		}
+	}
+
+	var queryBuilder strings.Builder
+	queryBuilder.WriteString(teamListingQuery)
+
+	args := []interface{}{userID}
+	paramIndex := 2
+
+	//archived teams are hidden unless asked for, and then only shown to their members and org admins
+	if params.Archived {
+		queryBuilder.WriteString(` AND t.status <> 'ACTIVE' AND (tm.user_id IS NOT NULL
+		OR EXISTS(SELECT 1 FROM organization_admins oa WHERE oa.organization_id = t.organization_id AND oa.user_id = $1))`)
+	} else {
+		queryBuilder.WriteString(` AND t.status = 'ACTIVE'`)
+	}
+
+	if params.Sport != "" {
+		queryBuilder.WriteString(fmt.Sprintf(" AND LOWER(t.sports) = $%d", paramIndex))
+		args = append(args, sportKey(params.Sport))
+		paramIndex++
+	}
+	if search := strings.TrimSpace(params.Search); search != "" {
+		queryBuilder.WriteString(fmt.Sprintf(" AND t.name ILIKE $%d", paramIndex))
+		args = append(args, likePattern(search))
+		paramIndex++
+	}
+	if params.OrganizationID != nil {
+		queryBuilder.WriteString(fmt.Sprintf(" AND t.organization_id = $%d", paramIndex))
+		args = append(args, *params.OrganizationID)
+		paramIndex++
+	}
+	if params.Visibility != "" {
+		queryBuilder.WriteString(fmt.Sprintf(" AND t.visibility = $%d", paramIndex))
+		args = append(args, params.Visibility)
+		paramIndex++
+	}
+	if cursor != nil {
+		queryBuilder.WriteString(fmt.Sprintf(" AND (t.createdat, t.id) < ($%d, $%d)", paramIndex, paramIndex+1))
+		args = append(args, cursor.Createdat, cursor.TeamUUID)
+		paramIndex += 2
+	}
+
+	//one extra row tells whether there is a next page
+	queryBuilder.WriteString(fmt.Sprintf(" ORDER BY t.createdat DESC, t.id DESC LIMIT $%d", paramIndex))
+	args = append(args, params.Limit+1)
+
+	teams, err := ts.queryTeamListings(ctx, queryBuilder.String(), args...)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &models.PaginatedTeams{Data: teams}
+	if len(teams) > params.Limit {
+		page.Data = teams[:params.Limit]
+		last := page.Data[len(page.Data)-1]
+		nextCursor, err := encodeCursor(TeamCursor{Createdat: last.Createdat, TeamUUID: last.TeamID})
+		if err != nil {
+			return nil, err
+		}
+		page.NextCursor = nextCursor
+	}
+	return page, nil
+}
+
+// GET :: limited profile of a single team, private teams are only visible to members and org admins,
+// archived ones only to members
+func (ts *TeamService) GetTeamProfile(ctx context.Context, teamID uuid.UUID, userID uuid.UUID) (*models.TeamListing, error) {
+	teams, err := ts.queryTeamListings(ctx, teamListingQuery+` AND t.id = $2 AND (t.status = 'ACTIVE' OR tm.user_id IS NOT NULL)`, userID, teamID)
+	if err != nil {
+		return nil, err
+	}
+	if len(teams) == 0 {
+		return nil, ErrNotFound
+	}
+	return &teams[0], nil
+}
+
+// PUT :: make a team public (discoverable, open to join requests) or private
+func (ts *TeamService) UpdateTeamVisibility(ctx context.Context, teamID uuid.UUID, reqUserID uuid.UUID, req models.TeamVisibilityReq) (*models.Team, error) {
+
+	visibility := strings.ToLower(strings.TrimSpace(req.Visibility))
+	if visibility != models.TeamVisibilityPublic && visibility != models.TeamVisibilityPrivate {
+		return nil, ErrBadRequest
+	}
+
+	if _, err := ts.requirePermission(ctx, teamID, reqUserID, permissions.TeamUpdate); err != nil {
+		return nil, err
+	}
+
+	result, err := ts.db.ExecContext(ctx, `UPDATE teams SET visibility=$1, updatedat=NOW() WHERE id=$2`, visibility, teamID)
+	if err != nil {
+		return nil, err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return nil, err
+	} else if n == 0 {
+		return nil, ErrNotFound
+	}
+
+	return ts.GetTeamByID(ctx, teamID)
+}
+
+const joinRequestColumns = `jr.id,jr.team_id,t.name,jr.user_id,COALESCE(jr.message,''),jr.status,jr.decided_by,jr.decided_at,jr.createdat`
+
+func scanJoinRequest(row rowScanner) (*models.JoinRequest, error) {
+	var req models.JoinRequest
+	var decidedBy uuid.NullUUID
+	var decidedAt sql.NullTime
+
+	err := row.Scan(&req.RequestID, &req.TeamID, &req.TeamName, &req.UserID, &req.Message, &req.Status, &decidedBy, &decidedAt, &req.Createdat)
+	if err != nil {
+		return nil, err
+	}
+	if decidedBy.Valid {
+		req.DecidedBy = &decidedBy.UUID
+	}
+	if decidedAt.Valid {
+		req.DecidedAt = &decidedAt.Time
+	}
+	return &req, nil
+}
+
+func (ts *TeamService) queryJoinRequests(ctx context.Context, query string, args ...interface{}) ([]models.JoinRequest, error) {
+	rows, err := ts.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	requests := make([]models.JoinRequest, 0)
+	for rows.Next() {
+		req, err := scanJoinRequest(rows)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, *req)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+// rosterApprovers are the members whose role can manage the roster and so decide join requests
+func (ts *TeamService) rosterApprovers(ctx context.Context, teamID uuid.UUID) ([]uuid.UUID, error) {
+	members, err := ts.GetTeamsMembers(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	canApprove := map[string]bool{}
+	approvers := make([]uuid.UUID, 0)
+	for _, m := range members {
+		allowed, seen := canApprove[m.Role]
+		if !seen {
+			granted, err := ts.PermissionsForRole(ctx, teamID, m.Role)
+			if err != nil {
+				return nil, err
+			}
+			allowed = permissions.Has(granted, permissions.RosterManage)
+			canApprove[m.Role] = allowed
+		}
+		if allowed {
+			approvers = append(approvers, m.UserID)
+		}
+	}
+	return approvers, nil
+}
+
+// POST :: a user asks to join a public team
+func (ts *TeamService) RequestToJoin(ctx context.Context, teamID uuid.UUID, userID uuid.UUID, req models.JoinRequestReq) (*models.JoinRequest, error) {
+
+	req.Message = strings.TrimSpace(req.Message)
+	if len(req.Message) > 500 {
+		return nil, ErrBadRequest
+	}
+
+	team, err := ts.GetTeamByID(ctx, teamID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	//private and archived teams are not discoverable, answer as if they did not exist
+	if team.Visibility != models.TeamVisibilityPublic || team.Status != models.TeamStatusActive {
+		return nil, ErrNotFound
+	}
+
+	isMember, err := ts.IsTeamMember(ctx, userID, teamID)
+	if err != nil {
+		return nil, err
+	}
+	if isMember {
+		return nil, ErrAlreadyMember
+	}
+
+	var requestID uuid.UUID
+	query := `INSERT INTO team_join_requests(team_id,user_id,message) VALUES($1,$2,NULLIF($3,'')) RETURNING id`
+	if err := ts.db.QueryRowContext(ctx, query, teamID, userID, req.Message).Scan(&requestID); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return nil, ErrJoinRequestPending
+		}
+		return nil, err
+	}
+
+	approvers, err := ts.rosterApprovers(ctx, teamID)
+	if err != nil {
+		log.Printf("could not resolve approvers for team %s: %v", teamID, err)
+	}
+	event := events.TeamJoinRequested{
+		RequestID: requestID,
+		TeamID:    teamID,
+		UserID:    userID,
+		Message:   req.Message,
+		Approvers: approvers,
+	}
+	if err := ts.prod.PublishTeamUpdate(ctx, event); err != nil {
+		log.Printf("kafka error publishing %s: %s", events.TypeTeamJoinRequested, err)
+	}
+
+	return ts.getJoinRequest(ctx, teamID, requestID)
+}
+
+func (ts *TeamService) getJoinRequest(ctx context.Context, teamID uuid.UUID, requestID uuid.UUID) (*models.JoinRequest, error) {
+	query := `SELECT ` + joinRequestColumns + ` FROM team_join_requests jr JOIN teams t ON t.id = jr.team_id
+	WHERE jr.team_id=$1 AND jr.id=$2`
+	req, err := scanJoinRequest(ts.db.QueryRowContext(ctx, query, teamID, requestID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return req, nil
+}
+
+// GET :: join requests of a team for the coaches, pending ones by default
+func (ts *TeamService) ListJoinRequests(ctx context.Context, teamID uuid.UUID, reqUserID uuid.UUID, status string) ([]models.JoinRequest, error) {
+
+	if _, err := ts.requirePermission(ctx, teamID, reqUserID, permissions.RosterManage); err != nil {
+		return nil, err
+	}
+
+	status = strings.ToUpper(status)
+	if status == "" {
+		status = models.JoinRequestPending
+	}
+
+	query := `SELECT ` + joinRequestColumns + ` FROM team_join_requests jr JOIN teams t ON t.id = jr.team_id
+	WHERE jr.team_id=$1 AND jr.status=$2 ORDER BY jr.createdat`
+	requests, err := ts.queryJoinRequests(ctx, query, teamID, status)
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]uuid.UUID, 0, len(requests))
+	for _, req := range requests {
+		userIDs = append(userIDs, req.UserID)
+	}
+	profiles := ts.fetchProfiles(ctx, userIDs)
+	for i := range requests {
+		if profile, ok := profiles[requests[i].UserID.String()]; ok {
+			requests[i].Firstname = profile.GetFirstname()
+			requests[i].Lastname = profile.GetLastname()
+			requests[i].Email = profile.GetEmail()
+		}
+	}
+	return requests, nil
+}
+
+// GET :: the caller's own join requests
+func (ts *TeamService) GetMyJoinRequests(ctx context.Context, userID uuid.UUID) ([]models.JoinRequest, error) {
+	query := `SELECT ` + joinRequestColumns + ` FROM team_join_requests jr JOIN teams t ON t.id = jr.team_id
+	WHERE jr.user_id=$1 ORDER BY jr.createdat DESC`
+	return ts.queryJoinRequests(ctx, query, userID)
+}
+
+// POST :: coach/manager approves or rejects a pending request, approving adds the user as a player
+func (ts *TeamService) DecideJoinRequest(ctx context.Context, teamID uuid.UUID, requestID uuid.UUID, reqUserID uuid.UUID, approve bool) (*models.JoinRequest, error) {
+
+	if _, err := ts.requirePermission(ctx, teamID, reqUserID, permissions.RosterManage); err != nil {
+		return nil, err
+	}
+
+	txs, err := ts.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer txs.Rollback()
+
+	var userID uuid.UUID
+	var status string
+	query := `SELECT user_id,status FROM team_join_requests WHERE id=$1 AND team_id=$2 FOR UPDATE`
+	if err := txs.QueryRowContext(ctx, query, requestID, teamID).Scan(&userID, &status); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	if status != models.JoinRequestPending {
+		return nil, ErrJoinRequestClosed
+	}
+
+	decision := models.JoinRequestRejected
+	now := time.Now().UTC()
+
+	if approve {
+		decision = models.JoinRequestApproved
+
+		insert := `INSERT INTO team_members(team_id,role,joinedat,user_id) VALUES($1,$2,$3,$4) ON CONFLICT DO NOTHING`
+		result, err := txs.ExecContext(ctx, insert, teamID, permissions.RolePlayer, now, userID)
+		if err != nil {
+			return nil, err
+		}
+		if n, err := result.RowsAffected(); err != nil {
+			return nil, err
+		} else if n == 0 {
+			return nil, ErrAlreadyMember
+		}
+
+		if err := ts.syncSeasonMember(ctx, txs, teamID, userID, now); err != nil {
+			return nil, err
+		}
+	}
+
+	update := `UPDATE team_join_requests SET status=$1, decided_by=$2, decided_at=$3 WHERE id=$4`
+	if _, err := txs.ExecContext(ctx, update, decision, reqUserID, now, requestID); err != nil {
+		return nil, err
+	}
+
+	if err := txs.Commit(); err != nil {
+		return nil, err
+	}
+
+	if approve {
+		ts.publishRosterChange(ctx, events.RosterMemberAdded, teamID, userID, "", permissions.RolePlayer, reqUserID)
+	}
+	event := events.TeamJoinRequestDecided{
+		RequestID: requestID,
+		TeamID:    teamID,
+		UserID:    userID,
+		Status:    decision,
+		DecidedBy: reqUserID,
+	}
+	if err := ts.prod.PublishTeamUpdate(ctx, event); err != nil {
+		log.Printf("kafka error publishing %s: %s", events.TypeTeamJoinRequestDecided, err)
+	}
+
+	return ts.getJoinRequest(ctx, teamID, requestID)
+}
+
+// DELETE :: the requester withdraws a pending request
+func (ts *TeamService) CancelJoinRequest(ctx context.Context, teamID uuid.UUID, requestID uuid.UUID, userID uuid.UUID) (*models.JoinRequest, error) {
+	query := `UPDATE team_join_requests SET status=$1, decided_by=$2, decided_at=NOW()
+	WHERE id=$3 AND team_id=$4 AND user_id=$2 AND status=$5`
+	result, err := ts.db.ExecContext(ctx, query, models.JoinRequestCancelled, userID, requestID, teamID, models.JoinRequestPending)
+	if err != nil {
+		return nil, err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return nil, err
+	} else if n == 0 {
+		return nil, ErrNotFound
+	}
+	return ts.getJoinRequest(ctx, teamID, requestID)
+}
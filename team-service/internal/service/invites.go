@@ -0,0 +1,402 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github/wycliff-ochieng/internal/models"
+	"github/wycliff-ochieng/internal/permissions"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wycliff-ochieng/common_packages/events"
+)
+
+var ErrBadRequest = errors.New("invalid request data")
+var ErrInviteNotUsable = errors.New("invite is no longer valid")
+var ErrAlreadyMember = errors.New("user is already a member of this team")
+
+const inviteColumns = `i.id,i.team_id,t.name,i.invited_by,i.invitee_user_id,i.invitee_email,i.code,i.role,i.max_uses,i.uses,i.status,i.expires_at,i.responded_at,i.createdat`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanInvite(row rowScanner) (*models.TeamInvite, error) {
+	var invite models.TeamInvite
+	var inviteeUserID uuid.NullUUID
+	var inviteeEmail, code sql.NullString
+	var maxUses sql.NullInt32
+	var expiresAt, respondedAt sql.NullTime
+
+	err := row.Scan(
+		&invite.InviteID,
+		&invite.TeamID,
+		&invite.TeamName,
+		&invite.InvitedBy,
+		&inviteeUserID,
+		&inviteeEmail,
+		&code,
+		&invite.Role,
+		&maxUses,
+		&invite.Uses,
+		&invite.Status,
+		&expiresAt,
+		&respondedAt,
+		&invite.Createdat,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if inviteeUserID.Valid {
+		invite.InviteeUserID = &inviteeUserID.UUID
+	}
+	invite.InviteeEmail = inviteeEmail.String
+	invite.Code = code.String
+	if invite.Code != "" {
+		invite.Link = fmt.Sprintf("/api/invites/join/%s", invite.Code)
+	}
+	if maxUses.Valid {
+		n := int(maxUses.Int32)
+		invite.MaxUses = &n
+	}
+	if expiresAt.Valid {
+		invite.ExpiresAt = &expiresAt.Time
+	}
+	if respondedAt.Valid {
+		invite.RespondedAt = &respondedAt.Time
+	}
+
+	//pending invites past their expiry are reported as expired
+	if invite.Status == models.InviteStatusPending && invite.ExpiresAt != nil && invite.ExpiresAt.Before(time.Now().UTC()) {
+		invite.Status = models.InviteStatusExpired
+	}
+
+	return &invite, nil
+}
+
+// generateInviteCode returns a short code that is easy to share as a link or read out loud
+func generateInviteCode() (string, error) {
+	const alphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	for i, b := range buf {
+		buf[i] = alphabet[int(b)%len(alphabet)]
+	}
+	return string(buf), nil
+}
+
+// lookupUserEmail is the reverse of resolveUserIdentifier, empty when the auth db is unavailable
+func (ts *TeamService) lookupUserEmail(ctx context.Context, userID uuid.UUID) string {
+	if ts.authDB == nil {
+		return ""
+	}
+
+	var email string
+	query := `SELECT email FROM users WHERE userid = $1 LIMIT 1`
+	if err := ts.authDB.QueryRowContext(ctx, query, userID).Scan(&email); err != nil {
+		log.Printf("warn: failed to look up email for %s: %v", userID, err)
+		return ""
+	}
+	return email
+}
+
+// POST :: coach/manager invites a user (uuid or email) or creates a reusable join code
+func (ts *TeamService) CreateInvite(ctx context.Context, teamID uuid.UUID, reqUserID uuid.UUID, req models.CreateInviteReq) (*models.TeamInvite, error) {
+
+	if _, err := ts.requirePermission(ctx, teamID, reqUserID, permissions.RosterManage); err != nil {
+		return nil, err
+	}
+
+	if req.Role == "" {
+		req.Role = permissions.RolePlayer
+	}
+	validRole, err := ts.roleExists(ctx, teamID, req.Role)
+	if err != nil {
+		return nil, err
+	}
+	if !validRole {
+		return nil, ErrBadRequest
+	}
+	//handing out the coach role or a custom role through an invite needs the same rights as assigning it
+	if permissions.GuardedRole(req.Role) {
+		if _, err := ts.requirePermission(ctx, teamID, reqUserID, permissions.RolesManage); err != nil {
+			return nil, err
+		}
+	}
+	if req.ExpiresAt != nil && req.ExpiresAt.Before(time.Now().UTC()) {
+		return nil, ErrBadRequest
+	}
+	if req.MaxUses != nil && *req.MaxUses < 1 {
+		return nil, ErrBadRequest
+	}
+
+	var inviteeUserID uuid.NullUUID
+	var inviteeEmail, code sql.NullString
+	var maxUses sql.NullInt32
+
+	invitee := strings.TrimSpace(req.Invitee)
+
+	switch {
+	case invitee == "":
+		//reusable join code/link
+		generated, err := generateInviteCode()
+		if err != nil {
+			return nil, err
+		}
+		code = sql.NullString{String: generated, Valid: true}
+		if req.MaxUses != nil {
+			maxUses = sql.NullInt32{Int32: int32(*req.MaxUses), Valid: true}
+		}
+	default:
+		if parsed, err := uuid.Parse(invitee); err == nil {
+			inviteeUserID = uuid.NullUUID{UUID: parsed, Valid: true}
+		} else if strings.Contains(invitee, "@") {
+			inviteeEmail = sql.NullString{String: strings.ToLower(invitee), Valid: true}
+			//link the invite to an existing account when there is one
+			if resolved, err := ts.resolveUserIdentifier(ctx, invitee); err == nil {
+				inviteeUserID = uuid.NullUUID{UUID: resolved, Valid: true}
+			}
+		} else {
+			return nil, ErrBadRequest
+		}
+
+		if inviteeUserID.Valid {
+			isMember, err := ts.IsTeamMember(ctx, inviteeUserID.UUID, teamID)
+			if err != nil {
+				return nil, err
+			}
+			if isMember {
+				return nil, ErrAlreadyMember
+			}
+		}
+	}
+
+	var expiresAt sql.NullTime
+	if req.ExpiresAt != nil {
+		expiresAt = sql.NullTime{Time: req.ExpiresAt.UTC(), Valid: true}
+	}
+
+	var inviteID uuid.UUID
+
+	query := `INSERT INTO team_invites(team_id,invited_by,invitee_user_id,invitee_email,code,role,max_uses,expires_at)
+	VALUES($1,$2,$3,$4,$5,$6,$7,$8) RETURNING id`
+
+	err = ts.db.QueryRowContext(ctx, query, teamID, reqUserID, inviteeUserID, inviteeEmail, code, req.Role, maxUses, expiresAt).Scan(&inviteID)
+	if err != nil {
+		log.Printf("ERROR creating invite due to: %v", err)
+		return nil, err
+	}
+
+	return ts.GetInviteByID(ctx, inviteID)
+}
+
+// repo service
+func (ts *TeamService) GetInviteByID(ctx context.Context, inviteID uuid.UUID) (*models.TeamInvite, error) {
+	query := `SELECT ` + inviteColumns + ` FROM team_invites i JOIN teams t ON t.id = i.team_id WHERE i.id = $1`
+
+	invite, err := scanInvite(ts.db.QueryRowContext(ctx, query, inviteID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return invite, nil
+}
+
+// GET :: coach/manager view of every invite for a team and its status
+func (ts *TeamService) ListTeamInvites(ctx context.Context, teamID uuid.UUID, reqUserID uuid.UUID) ([]*models.TeamInvite, error) {
+
+	if _, err := ts.requirePermission(ctx, teamID, reqUserID, permissions.RosterManage); err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + inviteColumns + ` FROM team_invites i JOIN teams t ON t.id = i.team_id
+	WHERE i.team_id = $1 ORDER BY i.createdat DESC`
+
+	return ts.queryInvites(ctx, query, teamID)
+}
+
+// GET :: pending invites addressed to the logged in user
+func (ts *TeamService) GetMyInvites(ctx context.Context, userID uuid.UUID) ([]*models.TeamInvite, error) {
+
+	email := ts.lookupUserEmail(ctx, userID)
+
+	query := `SELECT ` + inviteColumns + ` FROM team_invites i JOIN teams t ON t.id = i.team_id
+	WHERE i.status = 'PENDING' AND i.code IS NULL
+	AND (i.expires_at IS NULL OR i.expires_at > NOW())
+	AND (i.invitee_user_id = $1 OR ($2 <> '' AND LOWER(i.invitee_email) = LOWER($2)))
+	ORDER BY i.createdat DESC`
+
+	return ts.queryInvites(ctx, query, userID, email)
+}
+
+func (ts *TeamService) queryInvites(ctx context.Context, query string, args ...interface{}) ([]*models.TeamInvite, error) {
+	rows, err := ts.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	invites := make([]*models.TeamInvite, 0)
+	for rows.Next() {
+		invite, err := scanInvite(rows)
+		if err != nil {
+			return nil, err
+		}
+		invites = append(invites, invite)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return invites, nil
+}
+
+// POST :: invitee accepts a targeted invite
+func (ts *TeamService) AcceptInvite(ctx context.Context, inviteID uuid.UUID, userID uuid.UUID) (*models.TeamMembers, error) {
+	query := `SELECT ` + inviteColumns + ` FROM team_invites i JOIN teams t ON t.id = i.team_id WHERE i.id = $1 FOR UPDATE OF i`
+	return ts.redeemInvite(ctx, query, inviteID, userID)
+}
+
+// POST :: any logged in user joins through a shared code/link
+func (ts *TeamService) JoinWithCode(ctx context.Context, code string, userID uuid.UUID) (*models.TeamMembers, error) {
+	query := `SELECT ` + inviteColumns + ` FROM team_invites i JOIN teams t ON t.id = i.team_id WHERE i.code = $1 FOR UPDATE OF i`
+	return ts.redeemInvite(ctx, query, strings.ToUpper(strings.TrimSpace(code)), userID)
+}
+
+func (ts *TeamService) redeemInvite(ctx context.Context, query string, key interface{}, userID uuid.UUID) (*models.TeamMembers, error) {
+
+	txs, err := ts.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer txs.Rollback()
+
+	invite, err := scanInvite(txs.QueryRowContext(ctx, query, key))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	if invite.Status != models.InviteStatusPending {
+		return nil, ErrInviteNotUsable
+	}
+
+	if err := ts.requireWritableTeam(ctx, invite.TeamID); err != nil {
+		return nil, err
+	}
+
+	if invite.Code == "" {
+		//targeted invites can only be accepted by the invitee
+		if !ts.isInvitee(ctx, invite, userID) {
+			return nil, ErrForbidden
+		}
+	} else if invite.MaxUses != nil && invite.Uses >= *invite.MaxUses {
+		return nil, ErrInviteNotUsable
+	}
+
+	var exists bool
+	memberQuery := `SELECT EXISTS(SELECT 1 FROM team_members WHERE user_id = $1 AND team_id = $2)`
+	if err := txs.QueryRowContext(ctx, memberQuery, userID, invite.TeamID).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, ErrAlreadyMember
+	}
+
+	joinedAt := time.Now().UTC()
+
+	addMemberQuery := `INSERT INTO team_members(team_id,role,joinedat,user_id) VALUES($1,$2,$3,$4)`
+	if _, err := txs.ExecContext(ctx, addMemberQuery, invite.TeamID, invite.Role, joinedAt, userID); err != nil {
+		log.Printf("ERROR adding member from invite due to: %v", err)
+		return nil, err
+	}
+
+	if err := ts.syncSeasonMember(ctx, txs, invite.TeamID, userID, joinedAt); err != nil {
+		return nil, err
+	}
+
+	if invite.Code != "" {
+		//join codes stay pending until they run out or expire
+		_, err = txs.ExecContext(ctx, `UPDATE team_invites SET uses=uses+1, updatedat=NOW() WHERE id=$1`, invite.InviteID)
+	} else {
+		_, err = txs.ExecContext(ctx, `UPDATE team_invites SET status='ACCEPTED', invitee_user_id=$2, uses=uses+1, responded_at=NOW(), updatedat=NOW() WHERE id=$1`, invite.InviteID, userID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := txs.Commit(); err != nil {
+		return nil, err
+	}
+
+	event := events.TeamMemberJoined{
+		TeamID:   invite.TeamID,
+		UserID:   userID,
+		Role:     invite.Role,
+		InviteID: invite.InviteID,
+		JoinedAt: joinedAt,
+	}
+	if err := ts.prod.PublishTeamUpdate(ctx, event); err != nil {
+		//membership is already committed, the event is best effort
+		log.Printf("kafka error publishing %s: %s", events.TypeTeamMemberJoined, err)
+	}
+
+	return models.NewTeamMembers(invite.TeamID, userID, invite.Role, joinedAt), nil
+}
+
+// POST :: invitee declines a targeted invite
+func (ts *TeamService) DeclineInvite(ctx context.Context, inviteID uuid.UUID, userID uuid.UUID) (*models.TeamInvite, error) {
+
+	invite, err := ts.GetInviteByID(ctx, inviteID)
+	if err != nil {
+		return nil, err
+	}
+
+	if invite.Code != "" || !ts.isInvitee(ctx, invite, userID) {
+		return nil, ErrForbidden
+	}
+
+	if invite.Status != models.InviteStatusPending {
+		return nil, ErrInviteNotUsable
+	}
+
+	query := `UPDATE team_invites SET status='DECLINED', responded_at=NOW(), updatedat=NOW() WHERE id=$1 AND status='PENDING'`
+
+	result, err := ts.db.ExecContext(ctx, query, inviteID)
+	if err != nil {
+		return nil, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if rowsAffected == 0 {
+		return nil, ErrInviteNotUsable
+	}
+
+	return ts.GetInviteByID(ctx, inviteID)
+}
+
+func (ts *TeamService) isInvitee(ctx context.Context, invite *models.TeamInvite, userID uuid.UUID) bool {
+	if invite.InviteeUserID != nil {
+		return *invite.InviteeUserID == userID
+	}
+	if invite.InviteeEmail == "" {
+		return false
+	}
+	email := ts.lookupUserEmail(ctx, userID)
+	return email != "" && strings.EqualFold(email, invite.InviteeEmail)
+}
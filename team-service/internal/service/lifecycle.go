@@ -0,0 +1,280 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github/wycliff-ochieng/internal/models"
+	"github/wycliff-ochieng/internal/permissions"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wycliff-ochieng/common_packages/events"
+)
+
+var ErrTeamArchived = errors.New("team is archived and read-only")
+var ErrTeamStatus = errors.New("team cannot make this lifecycle change from its current status")
+
+// DefaultDeleteGracePeriod applies when TEAM_DELETE_GRACE_DAYS is not set
+const DefaultDeleteGracePeriod = 30 * 24 * time.Hour
+
+// purgeBatchSize bounds the teams hard deleted per purge run
+const purgeBatchSize = 50
+
+func (ts *TeamService) teamStatus(ctx context.Context, teamID uuid.UUID) (string, error) {
+	var status string
+	if err := ts.db.QueryRowContext(ctx, `SELECT status FROM teams WHERE id=$1`, teamID).Scan(&status); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", ErrNotFound
+		}
+		return "", err
+	}
+	return status, nil
+}
+
+// requireWritableTeam guards every write to a team, its roster and its settings
+func (ts *TeamService) requireWritableTeam(ctx context.Context, teamID uuid.UUID) error {
+	status, err := ts.teamStatus(ctx, teamID)
+	if err != nil {
+		return err
+	}
+	if status != models.TeamStatusActive {
+		return ErrTeamArchived
+	}
+	return nil
+}
+
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func (ts *TeamService) teamMemberIDs(ctx context.Context, q queryer, teamID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := q.QueryContext(ctx, `SELECT user_id FROM team_members WHERE team_id=$1`, teamID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := make([]uuid.UUID, 0)
+	for rows.Next() {
+		var userID uuid.UUID
+		if err := rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+		members = append(members, userID)
+	}
+	return members, rows.Err()
+}
+
+// setTeamStatus moves a team between lifecycle states, from lists the states it may leave
+func (ts *TeamService) setTeamStatus(ctx context.Context, teamID uuid.UUID, reqUserID uuid.UUID, status string, deleteAfter *time.Time, from ...string) (*models.Team, error) {
+
+	//lifecycle changes must work on read-only teams, so the archived check is skipped
+	allowed, _, err := ts.rolePermission(ctx, teamID, reqUserID, permissions.TeamDelete)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, ErrForbidden
+	}
+
+	current, err := ts.teamStatus(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+	canMove := false
+	for _, s := range from {
+		if current == s {
+			canMove = true
+		}
+	}
+	if !canMove {
+		return nil, ErrTeamStatus
+	}
+
+	var query string
+	var args []interface{}
+	switch status {
+	case models.TeamStatusActive:
+		query = `UPDATE teams SET status=$1, archived_at=NULL, archived_by=NULL, delete_after=NULL, updatedat=NOW() WHERE id=$2 AND status=$3`
+		args = []interface{}{status, teamID, current}
+	default:
+		//keep the original archive time when an archived team is scheduled for deletion
+		query = `UPDATE teams SET status=$1, archived_at=COALESCE(archived_at,NOW()), archived_by=COALESCE(archived_by,$2), delete_after=$3, updatedat=NOW()
+		WHERE id=$4 AND status=$5`
+		args = []interface{}{status, reqUserID, deleteAfter, teamID, current}
+	}
+
+	result, err := ts.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return nil, err
+	} else if n == 0 {
+		//someone else changed the status in between
+		return nil, ErrTeamStatus
+	}
+
+	return ts.GetTeamByID(ctx, teamID)
+}
+
+// POST :: archive a team, it becomes read-only and disappears from listings
+func (ts *TeamService) ArchiveTeam(ctx context.Context, teamID uuid.UUID, reqUserID uuid.UUID) (*models.Team, error) {
+	team, err := ts.setTeamStatus(ctx, teamID, reqUserID, models.TeamStatusArchived, nil, models.TeamStatusActive)
+	if err != nil {
+		return nil, err
+	}
+	ts.publishTeamArchived(ctx, team, reqUserID)
+	return team, nil
+}
+
+// POST :: bring an archived team, or one waiting for deletion, back
+func (ts *TeamService) RestoreTeam(ctx context.Context, teamID uuid.UUID, reqUserID uuid.UUID) (*models.Team, error) {
+	team, err := ts.setTeamStatus(ctx, teamID, reqUserID, models.TeamStatusActive, nil, models.TeamStatusArchived, models.TeamStatusPendingDeletion)
+	if err != nil {
+		return nil, err
+	}
+
+	event := events.TeamRestored{
+		TeamID:     teamID,
+		RestoredBy: reqUserID,
+	}
+	if err := ts.prod.PublishTeamUpdate(ctx, event); err != nil {
+		log.Printf("kafka error publishing %s: %s", events.TypeTeamRestored, err)
+	}
+	return team, nil
+}
+
+// DELETE :: schedule a hard delete once the grace period ran out, the team is archived meanwhile
+func (ts *TeamService) DeleteTeam(ctx context.Context, teamID uuid.UUID, reqUserID uuid.UUID) (*models.Team, error) {
+	deleteAfter := time.Now().UTC().Add(ts.deleteGrace)
+	team, err := ts.setTeamStatus(ctx, teamID, reqUserID, models.TeamStatusPendingDeletion, &deleteAfter, models.TeamStatusActive, models.TeamStatusArchived)
+	if err != nil {
+		return nil, err
+	}
+	ts.publishTeamArchived(ctx, team, reqUserID)
+	return team, nil
+}
+
+// publishTeamArchived tells event-service to cancel the team's future events, it is sent again
+// when an archived team is scheduled for deletion
+func (ts *TeamService) publishTeamArchived(ctx context.Context, team *models.Team, archivedBy uuid.UUID) {
+	members, err := ts.teamMemberIDs(ctx, ts.db, team.TeamID)
+	if err != nil {
+		log.Printf("could not list members of archived team %s: %v", team.TeamID, err)
+	}
+
+	event := events.TeamArchived{
+		TeamID:         team.TeamID,
+		OrganizationID: team.OrganizationID,
+		Status:         team.Status,
+		DeleteAfter:    team.DeleteAfter,
+		ArchivedBy:     archivedBy,
+		MemberIDs:      members,
+	}
+	if err := ts.prod.PublishTeamUpdate(ctx, event); err != nil {
+		log.Printf("kafka error publishing %s: %s", events.TypeTeamArchived, err)
+	}
+}
+
+// PurgeDeletedTeams hard deletes the teams whose grace period is over and returns how many went
+func (ts *TeamService) PurgeDeletedTeams(ctx context.Context) (int, error) {
+	query := `SELECT id FROM teams WHERE status=$1 AND delete_after <= NOW() ORDER BY delete_after LIMIT $2`
+	rows, err := ts.db.QueryContext(ctx, query, models.TeamStatusPendingDeletion, purgeBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	var due []uuid.UUID
+	for rows.Next() {
+		var teamID uuid.UUID
+		if err := rows.Scan(&teamID); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		due = append(due, teamID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, teamID := range due {
+		deleted, err := ts.purgeTeam(ctx, teamID)
+		if err != nil {
+			log.Printf("failed to purge team %s: %v", teamID, err)
+			continue
+		}
+		if deleted {
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+func (ts *TeamService) purgeTeam(ctx context.Context, teamID uuid.UUID) (bool, error) {
+	txs, err := ts.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer txs.Rollback()
+
+	//re-check under lock, the team may have been restored since it was picked
+	var orgID uuid.UUID
+	query := `SELECT organization_id FROM teams WHERE id=$1 AND status=$2 AND delete_after <= NOW() FOR UPDATE SKIP LOCKED`
+	if err := txs.QueryRowContext(ctx, query, teamID, models.TeamStatusPendingDeletion).Scan(&orgID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	members, err := ts.teamMemberIDs(ctx, txs, teamID)
+	if err != nil {
+		return false, err
+	}
+
+	//team_members has no cascade, the other team tables do
+	if _, err := txs.ExecContext(ctx, `DELETE FROM team_members WHERE team_id=$1`, teamID); err != nil {
+		return false, err
+	}
+	if _, err := txs.ExecContext(ctx, `DELETE FROM teams WHERE id=$1`, teamID); err != nil {
+		return false, err
+	}
+
+	if err := txs.Commit(); err != nil {
+		return false, err
+	}
+
+	event := events.TeamDeleted{
+		TeamID:         teamID,
+		OrganizationID: orgID,
+		MemberIDs:      members,
+	}
+	if err := ts.prod.PublishTeamUpdate(ctx, event); err != nil {
+		log.Printf("kafka error publishing %s: %s", events.TypeTeamDeleted, err)
+	}
+	return true, nil
+}
+
+// RunDeletionPurge purges due teams every interval until ctx is cancelled
+func (ts *TeamService) RunDeletionPurge(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := ts.PurgeDeletedTeams(ctx); err != nil {
+			log.Printf("team purge failed: %v", err)
+		} else if n > 0 {
+			log.Printf("hard deleted %d teams past their grace period", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,539 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github/wycliff-ochieng/internal/models"
+	"github/wycliff-ochieng/internal/permissions"
+	"log"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/wycliff-ochieng/common_packages/events"
+)
+
+// DefaultOrganizationID owns every team created before organizations existed and
+// every team created without an organization
+var DefaultOrganizationID = uuid.MustParse("00000000-0000-0000-0000-000000000001")
+
+var ErrLastAdmin = errors.New("an organization must keep at least one admin")
+
+// orgAdminRole is reported by HasPermission when access comes from the organization
+const orgAdminRole = "org_admin"
+
+// IsOrgAdmin reports whether the user administers the organization
+func (ts *TeamService) IsOrgAdmin(ctx context.Context, orgID uuid.UUID, userID uuid.UUID) (bool, error) {
+	var exists bool
+	query := `SELECT EXISTS(SELECT 1 FROM organization_admins WHERE organization_id=$1 AND user_id=$2)`
+	if err := ts.db.QueryRowContext(ctx, query, orgID, userID).Scan(&exists); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+// isOrgAdminForTeam reports whether the user administers the organization owning the team
+func (ts *TeamService) isOrgAdminForTeam(ctx context.Context, teamID uuid.UUID, userID uuid.UUID) (bool, error) {
+	var exists bool
+	query := `SELECT EXISTS(SELECT 1 FROM organization_admins oa JOIN teams t ON t.organization_id = oa.organization_id
+	WHERE t.id=$1 AND oa.user_id=$2)`
+	if err := ts.db.QueryRowContext(ctx, query, teamID, userID).Scan(&exists); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+// canViewTeam is true for team members and admins of the owning organization
+func (ts *TeamService) canViewTeam(ctx context.Context, teamID uuid.UUID, userID uuid.UUID) (bool, error) {
+	isMember, err := ts.IsTeamMember(ctx, userID, teamID)
+	if err != nil || isMember {
+		return isMember, err
+	}
+	return ts.isOrgAdminForTeam(ctx, teamID, userID)
+}
+
+// isOrgMember is true for admins and for members of any team in the organization
+func (ts *TeamService) isOrgMember(ctx context.Context, orgID uuid.UUID, userID uuid.UUID) (bool, error) {
+	var exists bool
+	query := `SELECT EXISTS(SELECT 1 FROM organization_admins WHERE organization_id=$1 AND user_id=$2)
+	OR EXISTS(SELECT 1 FROM team_members tm JOIN teams t ON t.id = tm.team_id WHERE t.organization_id=$1 AND tm.user_id=$2)`
+	if err := ts.db.QueryRowContext(ctx, query, orgID, userID).Scan(&exists); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+// POST :: create an organization, the creator becomes its first admin
+func (ts *TeamService) CreateOrganization(ctx context.Context, reqUserID uuid.UUID, req models.OrganizationReq) (*models.Organization, error) {
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" || len(req.Name) > 200 {
+		return nil, ErrBadRequest
+	}
+
+	txs, err := ts.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer txs.Rollback()
+
+	org := models.Organization{
+		Name:        req.Name,
+		Description: req.Description,
+		CreatedBy:   &reqUserID,
+		IsAdmin:     true,
+		Admins:      []uuid.UUID{reqUserID},
+	}
+
+	query := `INSERT INTO organizations(name,description,created_by) VALUES($1,$2,$3) RETURNING id,createdat,updatedat`
+	if err := txs.QueryRowContext(ctx, query, req.Name, req.Description, reqUserID).Scan(&org.OrgID, &org.Createdat, &org.Updatedat); err != nil {
+		return nil, err
+	}
+
+	if _, err := txs.ExecContext(ctx, `INSERT INTO organization_admins(organization_id,user_id) VALUES($1,$2)`, org.OrgID, reqUserID); err != nil {
+		return nil, err
+	}
+
+	if err := txs.Commit(); err != nil {
+		return nil, err
+	}
+	return &org, nil
+}
+
+// GET :: organizations the user administers or has a team in
+func (ts *TeamService) GetMyOrganizations(ctx context.Context, userID uuid.UUID) ([]models.Organization, error) {
+
+	query := `SELECT o.id,o.name,COALESCE(o.description,''),o.created_by,o.createdat,o.updatedat,
+	EXISTS(SELECT 1 FROM organization_admins oa WHERE oa.organization_id = o.id AND oa.user_id = $1)
+	FROM organizations o
+	WHERE EXISTS(SELECT 1 FROM organization_admins oa WHERE oa.organization_id = o.id AND oa.user_id = $1)
+	OR EXISTS(SELECT 1 FROM team_members tm JOIN teams t ON t.id = tm.team_id WHERE t.organization_id = o.id AND tm.user_id = $1)
+	ORDER BY o.name`
+
+	rows, err := ts.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	orgs := make([]models.Organization, 0)
+	for rows.Next() {
+		var org models.Organization
+		var createdBy uuid.NullUUID
+		if err := rows.Scan(&org.OrgID, &org.Name, &org.Description, &createdBy, &org.Createdat, &org.Updatedat, &org.IsAdmin); err != nil {
+			return nil, err
+		}
+		if createdBy.Valid {
+			org.CreatedBy = &createdBy.UUID
+		}
+		orgs = append(orgs, org)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return orgs, nil
+}
+
+// getOrganizationByID loads the organization row without any authorization check
+func (ts *TeamService) getOrganizationByID(ctx context.Context, orgID uuid.UUID) (*models.Organization, error) {
+	var org models.Organization
+	var createdBy uuid.NullUUID
+
+	query := `SELECT id,name,COALESCE(description,''),created_by,createdat,updatedat FROM organizations WHERE id=$1`
+	err := ts.db.QueryRowContext(ctx, query, orgID).Scan(&org.OrgID, &org.Name, &org.Description, &createdBy, &org.Createdat, &org.Updatedat)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	if createdBy.Valid {
+		org.CreatedBy = &createdBy.UUID
+	}
+	return &org, nil
+}
+
+// ListOrganizationTeams returns the teams owned by an organization, used by REST and gRPC
+func (ts *TeamService) ListOrganizationTeams(ctx context.Context, orgID uuid.UUID) ([]models.OrganizationTeam, error) {
+	query := `SELECT id,name,sports FROM teams WHERE organization_id=$1 AND status='ACTIVE' ORDER BY name`
+	rows, err := ts.db.QueryContext(ctx, query, orgID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	teams := make([]models.OrganizationTeam, 0)
+	for rows.Next() {
+		var team models.OrganizationTeam
+		if err := rows.Scan(&team.TeamID, &team.Name, &team.Sport); err != nil {
+			return nil, err
+		}
+		teams = append(teams, team)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return teams, nil
+}
+
+func (ts *TeamService) listOrganizationAdmins(ctx context.Context, orgID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := ts.db.QueryContext(ctx, `SELECT user_id FROM organization_admins WHERE organization_id=$1 ORDER BY addedat`, orgID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	admins := make([]uuid.UUID, 0)
+	for rows.Next() {
+		var userID uuid.UUID
+		if err := rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+		admins = append(admins, userID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return admins, nil
+}
+
+// GET :: organization with its teams and admins, for admins and members of its teams
+func (ts *TeamService) GetOrganization(ctx context.Context, orgID uuid.UUID, reqUserID uuid.UUID) (*models.Organization, error) {
+
+	isMember, err := ts.isOrgMember(ctx, orgID, reqUserID)
+	if err != nil {
+		return nil, err
+	}
+	if !isMember {
+		return nil, ErrForbidden
+	}
+
+	org, err := ts.getOrganizationByID(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	if org.Admins, err = ts.listOrganizationAdmins(ctx, orgID); err != nil {
+		return nil, err
+	}
+	for _, admin := range org.Admins {
+		if admin == reqUserID {
+			org.IsAdmin = true
+		}
+	}
+
+	if org.Teams, err = ts.visibleOrganizationTeams(ctx, orgID, reqUserID, org.IsAdmin); err != nil {
+		return nil, err
+	}
+	return org, nil
+}
+
+// GetOrganizationSummary loads an organization and its teams for service to service calls
+func (ts *TeamService) GetOrganizationSummary(ctx context.Context, orgID uuid.UUID) (*models.Organization, error) {
+	org, err := ts.getOrganizationByID(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if org.Teams, err = ts.ListOrganizationTeams(ctx, orgID); err != nil {
+		return nil, err
+	}
+	return org, nil
+}
+
+// GET :: teams of an organization
+func (ts *TeamService) GetOrganizationTeams(ctx context.Context, orgID uuid.UUID, reqUserID uuid.UUID) ([]models.OrganizationTeam, error) {
+
+	isMember, err := ts.isOrgMember(ctx, orgID, reqUserID)
+	if err != nil {
+		return nil, err
+	}
+	if !isMember {
+		return nil, ErrForbidden
+	}
+
+	isAdmin, err := ts.IsOrgAdmin(ctx, orgID, reqUserID)
+	if err != nil {
+		return nil, err
+	}
+	return ts.visibleOrganizationTeams(ctx, orgID, reqUserID, isAdmin)
+}
+
+// visibleOrganizationTeams hides the unrelated teams of the default organization from non admins
+func (ts *TeamService) visibleOrganizationTeams(ctx context.Context, orgID uuid.UUID, userID uuid.UUID, isAdmin bool) ([]models.OrganizationTeam, error) {
+	if isAdmin || orgID != DefaultOrganizationID {
+		return ts.ListOrganizationTeams(ctx, orgID)
+	}
+
+	query := `SELECT t.id,t.name,t.sports FROM teams t JOIN team_members tm ON tm.team_id = t.id
+	WHERE t.organization_id=$1 AND tm.user_id=$2 AND t.status='ACTIVE' ORDER BY t.name`
+	rows, err := ts.db.QueryContext(ctx, query, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	teams := make([]models.OrganizationTeam, 0)
+	for rows.Next() {
+		var team models.OrganizationTeam
+		if err := rows.Scan(&team.TeamID, &team.Name, &team.Sport); err != nil {
+			return nil, err
+		}
+		teams = append(teams, team)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return teams, nil
+}
+
+// PUT :: rename/describe an organization
+func (ts *TeamService) UpdateOrganization(ctx context.Context, orgID uuid.UUID, reqUserID uuid.UUID, req models.OrganizationReq) (*models.Organization, error) {
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" || len(req.Name) > 200 {
+		return nil, ErrBadRequest
+	}
+
+	isAdmin, err := ts.IsOrgAdmin(ctx, orgID, reqUserID)
+	if err != nil {
+		return nil, err
+	}
+	if !isAdmin {
+		return nil, ErrForbidden
+	}
+
+	result, err := ts.db.ExecContext(ctx, `UPDATE organizations SET name=$1, description=$2, updatedat=NOW() WHERE id=$3`, req.Name, req.Description, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return nil, err
+	} else if n == 0 {
+		return nil, ErrNotFound
+	}
+
+	org, err := ts.getOrganizationByID(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	org.IsAdmin = true
+	return org, nil
+}
+
+// GET :: organization wide member directory, everyone on any of its teams plus the admins
+func (ts *TeamService) GetOrganizationMembers(ctx context.Context, orgID uuid.UUID, reqUserID uuid.UUID) ([]models.OrgMember, error) {
+
+	isAdmin, err := ts.IsOrgAdmin(ctx, orgID, reqUserID)
+	if err != nil {
+		return nil, err
+	}
+	if !isAdmin {
+		//the default organization groups unrelated teams, only its admins see the whole directory
+		if orgID == DefaultOrganizationID {
+			return nil, ErrForbidden
+		}
+		isMember, err := ts.isOrgMember(ctx, orgID, reqUserID)
+		if err != nil {
+			return nil, err
+		}
+		if !isMember {
+			return nil, ErrForbidden
+		}
+	}
+
+	query := `SELECT tm.user_id,t.id,t.name,tm.role FROM team_members tm JOIN teams t ON t.id = tm.team_id
+	WHERE t.organization_id=$1 AND t.status='ACTIVE' ORDER BY t.name`
+
+	rows, err := ts.db.QueryContext(ctx, query, orgID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	directory := make(map[uuid.UUID]*models.OrgMember)
+	var order []uuid.UUID
+
+	member := func(userID uuid.UUID) *models.OrgMember {
+		m, ok := directory[userID]
+		if !ok {
+			m = &models.OrgMember{UserID: userID, Teams: []models.OrgMemberTeam{}}
+			directory[userID] = m
+			order = append(order, userID)
+		}
+		return m
+	}
+
+	for rows.Next() {
+		var userID uuid.UUID
+		var team models.OrgMemberTeam
+		if err := rows.Scan(&userID, &team.TeamID, &team.TeamName, &team.Role); err != nil {
+			return nil, err
+		}
+		m := member(userID)
+		m.Teams = append(m.Teams, team)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	admins, err := ts.listOrganizationAdmins(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	for _, admin := range admins {
+		member(admin).IsAdmin = true
+	}
+
+	members := make([]models.OrgMember, 0, len(order))
+	if len(order) == 0 {
+		return members, nil
+	}
+
+	//profiles are best effort, the directory is still useful without names
+	profiles := ts.fetchProfiles(ctx, order)
+
+	for _, userID := range order {
+		m := directory[userID]
+		if profile, found := profiles[userID.String()]; found {
+			m.Firstname = profile.GetFirstname()
+			m.Lastname = profile.GetLastname()
+			m.Email = profile.GetEmail()
+		}
+		members = append(members, *m)
+	}
+	return members, nil
+}
+
+// POST :: make a user (uuid or email) an admin of the organization
+func (ts *TeamService) AddOrganizationAdmin(ctx context.Context, orgID uuid.UUID, reqUserID uuid.UUID, req models.OrgAdminReq) ([]uuid.UUID, error) {
+
+	isAdmin, err := ts.IsOrgAdmin(ctx, orgID, reqUserID)
+	if err != nil {
+		return nil, err
+	}
+	if !isAdmin {
+		return nil, ErrForbidden
+	}
+
+	userID, err := ts.resolveUserIdentifier(ctx, strings.TrimSpace(req.Identifier))
+	if err != nil {
+		log.Printf("could not resolve org admin %q: %v", req.Identifier, err)
+		return nil, ErrBadRequest
+	}
+
+	query := `INSERT INTO organization_admins(organization_id,user_id) VALUES($1,$2) ON CONFLICT DO NOTHING`
+	if _, err := ts.db.ExecContext(ctx, query, orgID, userID); err != nil {
+		return nil, err
+	}
+	return ts.listOrganizationAdmins(ctx, orgID)
+}
+
+// DELETE :: remove an organization admin, admins may also step down themselves
+func (ts *TeamService) RemoveOrganizationAdmin(ctx context.Context, orgID uuid.UUID, reqUserID uuid.UUID, targetUserID uuid.UUID) error {
+
+	txs, err := ts.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer txs.Rollback()
+
+	//lock the admin rows so two admins cannot remove each other at once
+	rows, err := txs.QueryContext(ctx, `SELECT user_id FROM organization_admins WHERE organization_id=$1 FOR UPDATE`, orgID)
+	if err != nil {
+		return err
+	}
+	var reqIsAdmin, targetIsAdmin bool
+	admins := 0
+	for rows.Next() {
+		var userID uuid.UUID
+		if err := rows.Scan(&userID); err != nil {
+			rows.Close()
+			return err
+		}
+		admins++
+		reqIsAdmin = reqIsAdmin || userID == reqUserID
+		targetIsAdmin = targetIsAdmin || userID == targetUserID
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if !reqIsAdmin {
+		return ErrForbidden
+	}
+	if !targetIsAdmin {
+		return ErrNotFound
+	}
+	if admins == 1 {
+		return ErrLastAdmin
+	}
+
+	if _, err := txs.ExecContext(ctx, `DELETE FROM organization_admins WHERE organization_id=$1 AND user_id=$2`, orgID, targetUserID); err != nil {
+		return err
+	}
+	return txs.Commit()
+}
+
+// PUT :: move a team into another organization, the caller must administer the
+// target organization and either administer the current one or be allowed to delete the team
+func (ts *TeamService) MoveTeamToOrganization(ctx context.Context, teamID uuid.UUID, reqUserID uuid.UUID, req models.MoveTeamReq) (*models.Team, error) {
+
+	if req.OrganizationID == uuid.Nil {
+		return nil, ErrBadRequest
+	}
+
+	team, err := ts.GetTeamByID(ctx, teamID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	if team.OrganizationID == req.OrganizationID {
+		return team, nil
+	}
+	if team.Status != models.TeamStatusActive {
+		return nil, ErrTeamArchived
+	}
+
+	if _, err := ts.getOrganizationByID(ctx, req.OrganizationID); err != nil {
+		return nil, err
+	}
+
+	targetAdmin, err := ts.IsOrgAdmin(ctx, req.OrganizationID, reqUserID)
+	if err != nil {
+		return nil, err
+	}
+	if !targetAdmin {
+		return nil, ErrForbidden
+	}
+
+	sourceAdmin, err := ts.IsOrgAdmin(ctx, team.OrganizationID, reqUserID)
+	if err != nil {
+		return nil, err
+	}
+	if !sourceAdmin {
+		if _, err := ts.requirePermission(ctx, teamID, reqUserID, permissions.TeamDelete); err != nil {
+			return nil, err
+		}
+	}
+
+	query := `UPDATE teams SET organization_id=$1, updatedat=NOW() WHERE id=$2`
+	if _, err := ts.db.ExecContext(ctx, query, req.OrganizationID, teamID); err != nil {
+		return nil, err
+	}
+
+	event := events.TeamOrganizationChanged{
+		TeamID:                 teamID,
+		PreviousOrganizationID: team.OrganizationID,
+		OrganizationID:         req.OrganizationID,
+		ChangedBy:              reqUserID,
+	}
+	if err := ts.prod.PublishTeamUpdate(ctx, event); err != nil {
+		log.Printf("kafka error publishing %s: %s", events.TypeTeamOrganizationChanged, err)
+	}
+
+	team.OrganizationID = req.OrganizationID
+	return team, nil
+}
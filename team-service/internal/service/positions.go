@@ -0,0 +1,246 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github/wycliff-ochieng/internal/models"
+	"github/wycliff-ochieng/internal/permissions"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+var ErrNumberTaken = errors.New("squad number already taken in this team/season")
+
+// positionRow holds the nullable position columns of team_members while scanning
+type positionRow struct {
+	number    sql.NullInt64
+	primary   sql.NullString
+	secondary sql.NullString
+	depth     sql.NullInt64
+}
+
+// dest matches the column order jersey_number,primary_position,secondary_position,depth_order
+func (p *positionRow) dest() []interface{} {
+	return []interface{}{&p.number, &p.primary, &p.secondary, &p.depth}
+}
+
+func (p *positionRow) position() models.MemberPosition {
+	pos := models.MemberPosition{
+		PrimaryPosition:   p.primary.String,
+		SecondaryPosition: p.secondary.String,
+	}
+	if p.number.Valid {
+		n := int(p.number.Int64)
+		pos.JerseyNumber = &n
+	}
+	if p.depth.Valid {
+		d := int(p.depth.Int64)
+		pos.DepthOrder = &d
+	}
+	return pos
+}
+
+func sportKey(sport string) string {
+	return strings.ToLower(strings.TrimSpace(sport))
+}
+
+// ListSportPositions returns the configured positions of a sport, empty when the sport has none
+func (ts *TeamService) ListSportPositions(ctx context.Context, sport string) ([]models.SportPosition, error) {
+	query := `SELECT sport,code,name,sort_order FROM sport_positions WHERE sport=$1 ORDER BY sort_order, code`
+	rows, err := ts.db.QueryContext(ctx, query, sportKey(sport))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	positions := make([]models.SportPosition, 0)
+	for rows.Next() {
+		var p models.SportPosition
+		if err := rows.Scan(&p.Sport, &p.Code, &p.Name, &p.SortOrder); err != nil {
+			return nil, err
+		}
+		positions = append(positions, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return positions, nil
+}
+
+// normalizePosition checks a position against the sport's configured codes,
+// sports without configured positions accept short free text
+func (ts *TeamService) normalizePosition(ctx context.Context, sport string, position string) (string, error) {
+	position = strings.TrimSpace(position)
+	if position == "" {
+		return "", nil
+	}
+
+	configured, err := ts.ListSportPositions(ctx, sport)
+	if err != nil {
+		return "", err
+	}
+	if len(configured) == 0 {
+		if len(position) > 20 {
+			return "", ErrBadRequest
+		}
+		return position, nil
+	}
+
+	for _, p := range configured {
+		if strings.EqualFold(p.Code, position) {
+			return p.Code, nil
+		}
+	}
+	return "", ErrBadRequest
+}
+
+// PUT :: set a member's squad number and primary/secondary positions
+func (ts *TeamService) UpdateMemberPosition(ctx context.Context, teamID uuid.UUID, targetUserID uuid.UUID, reqUserID uuid.UUID, req models.MemberProfileReq) (*models.TeamMembers, error) {
+
+	if req.JerseyNumber != nil && (*req.JerseyNumber < 0 || *req.JerseyNumber > 999) {
+		return nil, ErrBadRequest
+	}
+
+	if _, err := ts.requirePermission(ctx, teamID, reqUserID, permissions.RosterManage); err != nil {
+		return nil, err
+	}
+
+	team, err := ts.GetTeamByID(ctx, teamID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	primary, err := ts.normalizePosition(ctx, team.Sport, req.PrimaryPosition)
+	if err != nil {
+		return nil, err
+	}
+	secondary, err := ts.normalizePosition(ctx, team.Sport, req.SecondaryPosition)
+	if err != nil {
+		return nil, err
+	}
+	if primary != "" && primary == secondary {
+		return nil, ErrBadRequest
+	}
+
+	txs, err := ts.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer txs.Rollback()
+
+	//a new primary position drops the member out of the old depth chart
+	query := `UPDATE team_members SET jersey_number=$1, primary_position=NULLIF($2,''), secondary_position=NULLIF($3,''),
+	depth_order=CASE WHEN primary_position IS NOT DISTINCT FROM NULLIF($2,'') THEN depth_order ELSE NULL END
+	WHERE team_id=$4 AND user_id=$5
+	RETURNING team_id,role,joinedat,user_id,jersey_number,primary_position,secondary_position,depth_order`
+
+	var member models.TeamMembers
+	var position positionRow
+	dest := []interface{}{&member.TeamID, &member.Role, &member.Joinedat, &member.UserID}
+	err = txs.QueryRowContext(ctx, query, req.JerseyNumber, primary, secondary, teamID, targetUserID).Scan(append(dest, position.dest()...)...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, positionWriteErr(err)
+	}
+	member.MemberPosition = position.position()
+
+	if err := ts.syncSeasonMember(ctx, txs, teamID, targetUserID, time.Now().UTC()); err != nil {
+		return nil, positionWriteErr(err)
+	}
+
+	if err := txs.Commit(); err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+func positionWriteErr(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return ErrNumberTaken
+	}
+	return err
+}
+
+// PUT :: order the members playing a position, the first user is the starter
+func (ts *TeamService) SetDepthChart(ctx context.Context, teamID uuid.UUID, reqUserID uuid.UUID, req models.DepthChartReq) ([]models.TeamMembers, error) {
+
+	if _, err := ts.requirePermission(ctx, teamID, reqUserID, permissions.RosterManage); err != nil {
+		return nil, err
+	}
+
+	team, err := ts.GetTeamByID(ctx, teamID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	position, err := ts.normalizePosition(ctx, team.Sport, req.Position)
+	if err != nil {
+		return nil, err
+	}
+	if position == "" {
+		return nil, ErrBadRequest
+	}
+
+	seen := make(map[uuid.UUID]bool, len(req.UserIDs))
+	for _, userID := range req.UserIDs {
+		if seen[userID] {
+			return nil, ErrBadRequest
+		}
+		seen[userID] = true
+	}
+
+	txs, err := ts.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer txs.Rollback()
+
+	if _, err := txs.ExecContext(ctx, `UPDATE team_members SET depth_order=NULL WHERE team_id=$1 AND primary_position=$2`, teamID, position); err != nil {
+		return nil, err
+	}
+
+	for i, userID := range req.UserIDs {
+		result, err := txs.ExecContext(ctx, `UPDATE team_members SET depth_order=$1 WHERE team_id=$2 AND user_id=$3 AND primary_position=$4`, i+1, teamID, userID, position)
+		if err != nil {
+			return nil, err
+		}
+		n, err := result.RowsAffected()
+		if err != nil {
+			return nil, err
+		}
+		//only members whose primary position matches can be placed
+		if n == 0 {
+			return nil, ErrBadRequest
+		}
+	}
+
+	if err := txs.Commit(); err != nil {
+		return nil, err
+	}
+
+	members, err := ts.GetTeamsMembers(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	chart := make([]models.TeamMembers, 0, len(req.UserIDs))
+	for _, m := range members {
+		if m.PrimaryPosition == position {
+			chart = append(chart, *m)
+		}
+	}
+	return chart, nil
+}
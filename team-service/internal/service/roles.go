@@ -0,0 +1,263 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github/wycliff-ochieng/internal/models"
+	"github/wycliff-ochieng/internal/permissions"
+	"log"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+var ErrRoleInUse = errors.New("role is still assigned to team members")
+
+// PermissionsForRole resolves a team role (built in or custom) to its permissions
+func (ts *TeamService) PermissionsForRole(ctx context.Context, teamID uuid.UUID, role string) ([]string, error) {
+	if permissions.IsBuiltinRole(role) {
+		return permissions.ForBuiltinRole(role), nil
+	}
+
+	var granted []string
+	query := `SELECT permissions FROM team_roles WHERE team_id=$1 AND name=$2`
+	if err := ts.db.QueryRowContext(ctx, query, teamID, role).Scan(pq.Array(&granted)); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			//role was deleted or never existed, grant nothing
+			return nil, nil
+		}
+		return nil, err
+	}
+	return granted, nil
+}
+
+// HasPermission checks a permission for a user against their role on the team,
+// admins of the owning organization hold every permission, other non members have none.
+// Archived teams only grant read-only permissions
+func (ts *TeamService) HasPermission(ctx context.Context, teamID uuid.UUID, userID uuid.UUID, permission string) (bool, string, error) {
+	allowed, role, err := ts.rolePermission(ctx, teamID, userID, permission)
+	if err != nil || !allowed || permissions.IsReadOnly(permission) {
+		return allowed, role, err
+	}
+
+	status, err := ts.teamStatus(ctx, teamID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return false, role, nil
+		}
+		return false, role, err
+	}
+	return status == models.TeamStatusActive, role, nil
+}
+
+// rolePermission is HasPermission without the archived check, the lifecycle endpoints need it
+// to restore a read-only team
+func (ts *TeamService) rolePermission(ctx context.Context, teamID uuid.UUID, userID uuid.UUID, permission string) (bool, string, error) {
+	orgAdmin, err := ts.isOrgAdminForTeam(ctx, teamID, userID)
+	if err != nil {
+		return false, "", err
+	}
+	if orgAdmin {
+		return true, orgAdminRole, nil
+	}
+
+	var role, status string
+	query := `SELECT role,status FROM team_members WHERE team_id=$1 AND user_id=$2`
+	if err := ts.db.QueryRowContext(ctx, query, teamID, userID).Scan(&role, &status); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, "", nil
+		}
+		return false, "", err
+	}
+	//suspended members keep their role but act with no permissions
+	if status != models.MemberStatusActive {
+		return false, role, nil
+	}
+
+	granted, err := ts.PermissionsForRole(ctx, teamID, role)
+	if err != nil {
+		return false, role, err
+	}
+	return permissions.Has(granted, permission), role, nil
+}
+
+// requirePermission returns the caller's team role or ErrForbidden, write permissions
+// on an archived team fail with ErrTeamArchived
+func (ts *TeamService) requirePermission(ctx context.Context, teamID uuid.UUID, userID uuid.UUID, permission string) (string, error) {
+	if !permissions.IsReadOnly(permission) {
+		if err := ts.requireWritableTeam(ctx, teamID); err != nil {
+			return "", err
+		}
+	}
+
+	allowed, role, err := ts.HasPermission(ctx, teamID, userID, permission)
+	if err != nil {
+		return "", err
+	}
+	if !allowed {
+		log.Printf("user %s lacks %s on team %s", userID, permission, teamID)
+		return role, ErrForbidden
+	}
+	return role, nil
+}
+
+// roleExists is true for the built in roles and the team's custom roles
+func (ts *TeamService) roleExists(ctx context.Context, teamID uuid.UUID, role string) (bool, error) {
+	if permissions.IsBuiltinRole(role) {
+		return true, nil
+	}
+
+	var exists bool
+	query := `SELECT EXISTS(SELECT 1 FROM team_roles WHERE team_id=$1 AND name=$2)`
+	if err := ts.db.QueryRowContext(ctx, query, teamID, role).Scan(&exists); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+// GET :: built in and custom roles of a team with their permissions
+func (ts *TeamService) ListTeamRoles(ctx context.Context, teamID uuid.UUID, reqUserID uuid.UUID) ([]models.TeamRole, error) {
+
+	canView, err := ts.canViewTeam(ctx, teamID, reqUserID)
+	if err != nil {
+		return nil, err
+	}
+	if !canView {
+		return nil, ErrForbidden
+	}
+
+	roles := make([]models.TeamRole, 0)
+	for _, name := range permissions.BuiltinRoles() {
+		roles = append(roles, models.TeamRole{
+			TeamID:      teamID,
+			Name:        name,
+			Permissions: permissions.ForBuiltinRole(name),
+			Builtin:     true,
+		})
+	}
+
+	query := `SELECT name,permissions,createdat,updatedat FROM team_roles WHERE team_id=$1 ORDER BY name`
+	rows, err := ts.db.QueryContext(ctx, query, teamID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		role := models.TeamRole{TeamID: teamID}
+		if err := rows.Scan(&role.Name, pq.Array(&role.Permissions), &role.Createdat, &role.Updatedat); err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+func validateRoleReq(req models.TeamRoleReq) error {
+	if req.Name == "" || permissions.IsBuiltinRole(req.Name) {
+		return ErrBadRequest
+	}
+	for _, p := range req.Permissions {
+		if !permissions.IsValid(p) {
+			return ErrBadRequest
+		}
+	}
+	return nil
+}
+
+// POST :: create a custom role for a team
+func (ts *TeamService) CreateTeamRole(ctx context.Context, teamID uuid.UUID, reqUserID uuid.UUID, req models.TeamRoleReq) (*models.TeamRole, error) {
+
+	req.Name = strings.ToLower(strings.TrimSpace(req.Name))
+	if err := validateRoleReq(req); err != nil {
+		return nil, err
+	}
+
+	if _, err := ts.requirePermission(ctx, teamID, reqUserID, permissions.RolesManage); err != nil {
+		return nil, err
+	}
+
+	role := models.TeamRole{
+		TeamID:      teamID,
+		Name:        req.Name,
+		Permissions: req.Permissions,
+	}
+
+	query := `INSERT INTO team_roles(team_id,name,permissions) VALUES($1,$2,$3) RETURNING createdat,updatedat`
+	err := ts.db.QueryRowContext(ctx, query, teamID, req.Name, pq.Array(req.Permissions)).Scan(&role.Createdat, &role.Updatedat)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return nil, ErrBadRequest
+		}
+		return nil, err
+	}
+	return &role, nil
+}
+
+// PUT :: replace the permissions of a custom role
+func (ts *TeamService) UpdateTeamRole(ctx context.Context, teamID uuid.UUID, reqUserID uuid.UUID, name string, req models.TeamRoleReq) (*models.TeamRole, error) {
+
+	req.Name = name
+	if err := validateRoleReq(req); err != nil {
+		return nil, err
+	}
+
+	if _, err := ts.requirePermission(ctx, teamID, reqUserID, permissions.RolesManage); err != nil {
+		return nil, err
+	}
+
+	role := models.TeamRole{
+		TeamID:      teamID,
+		Name:        name,
+		Permissions: req.Permissions,
+	}
+
+	query := `UPDATE team_roles SET permissions=$1, updatedat=NOW() WHERE team_id=$2 AND name=$3 RETURNING createdat,updatedat`
+	err := ts.db.QueryRowContext(ctx, query, pq.Array(req.Permissions), teamID, name).Scan(&role.Createdat, &role.Updatedat)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &role, nil
+}
+
+// DELETE :: remove a custom role that nobody holds anymore
+func (ts *TeamService) DeleteTeamRole(ctx context.Context, teamID uuid.UUID, reqUserID uuid.UUID, name string) error {
+
+	if permissions.IsBuiltinRole(name) {
+		return ErrBadRequest
+	}
+
+	if _, err := ts.requirePermission(ctx, teamID, reqUserID, permissions.RolesManage); err != nil {
+		return err
+	}
+
+	var inUse bool
+	if err := ts.db.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM team_members WHERE team_id=$1 AND role=$2)`, teamID, name).Scan(&inUse); err != nil {
+		return err
+	}
+	if inUse {
+		return ErrRoleInUse
+	}
+
+	result, err := ts.db.ExecContext(ctx, `DELETE FROM team_roles WHERE team_id=$1 AND name=$2`, teamID, name)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,328 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github/wycliff-ochieng/internal/models"
+	"github/wycliff-ochieng/internal/permissions"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wycliff-ochieng/common_packages/events"
+)
+
+// lookupUserByEmail resolves an account by email, found is false when there is none
+func (ts *TeamService) lookupUserByEmail(ctx context.Context, email string) (uuid.UUID, bool, error) {
+	if ts.authDB == nil {
+		return uuid.Nil, false, fmt.Errorf("email lookup unavailable")
+	}
+
+	var userID uuid.UUID
+	query := `SELECT userid FROM users WHERE LOWER(email) = $1 LIMIT 1`
+	if err := ts.authDB.QueryRowContext(ctx, query, strings.ToLower(email)).Scan(&userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return uuid.Nil, false, nil
+		}
+		return uuid.Nil, false, err
+	}
+	return userID, true, nil
+}
+
+// rosterChange is published once the import is committed
+type rosterChange struct {
+	changeType   string
+	userID       uuid.UUID
+	previousRole string
+	role         string
+}
+
+// POST :: bulk add/update members from a parsed csv. The whole file runs in one transaction,
+// every row gets a report entry and nothing is written when a row fails or on a dry run
+func (ts *TeamService) ImportRoster(ctx context.Context, teamID uuid.UUID, reqUserID uuid.UUID, rows []models.RosterImportRow, opts models.RosterImportOptions) (*models.RosterImportReport, error) {
+
+	if _, err := ts.requirePermission(ctx, teamID, reqUserID, permissions.RosterManage); err != nil {
+		return nil, err
+	}
+
+	canManageRoles, _, err := ts.HasPermission(ctx, teamID, reqUserID, permissions.RolesManage)
+	if err != nil {
+		return nil, err
+	}
+
+	team, err := ts.GetTeamByID(ctx, teamID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	txs, err := ts.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer txs.Rollback()
+
+	//lock the roster so the coach check below sees the final state
+	current := map[uuid.UUID]string{}
+	lockRows, err := txs.QueryContext(ctx, `SELECT user_id,role FROM team_members WHERE team_id=$1 FOR UPDATE`, teamID)
+	if err != nil {
+		return nil, err
+	}
+	for lockRows.Next() {
+		var userID uuid.UUID
+		var role string
+		if err := lockRows.Scan(&userID, &role); err != nil {
+			lockRows.Close()
+			return nil, err
+		}
+		current[userID] = role
+	}
+	lockRows.Close()
+	if err := lockRows.Err(); err != nil {
+		return nil, err
+	}
+
+	report := &models.RosterImportReport{DryRun: opts.DryRun, Rows: rows}
+	knownRoles := map[string]bool{}
+	seenUsers := map[uuid.UUID]int{}
+	var changes []rosterChange
+	now := time.Now().UTC()
+
+	for i := range report.Rows {
+		row := &report.Rows[i]
+		if len(row.Errors) > 0 {
+			continue
+		}
+
+		if row.Role != "" {
+			valid, seen := knownRoles[row.Role]
+			if !seen {
+				if valid, err = ts.roleExists(ctx, teamID, row.Role); err != nil {
+					return nil, err
+				}
+				knownRoles[row.Role] = valid
+			}
+			if !valid {
+				row.Errors = append(row.Errors, fmt.Sprintf("unknown role %q", row.Role))
+			}
+		}
+
+		position, err := ts.normalizePosition(ctx, team.Sport, row.Position)
+		if err != nil {
+			if !errors.Is(err, ErrBadRequest) {
+				return nil, err
+			}
+			row.Errors = append(row.Errors, fmt.Sprintf("unknown position %q for %s", row.Position, team.Sport))
+		}
+		row.Position = position
+
+		userID, found, err := ts.lookupUserByEmail(ctx, row.Email)
+		if err != nil {
+			log.Printf("roster import email lookup failed on line %d: %v", row.Line, err)
+			row.Errors = append(row.Errors, "could not look up email")
+		} else if found {
+			row.UserID = &userID
+			if first, dup := seenUsers[userID]; dup {
+				row.Errors = append(row.Errors, fmt.Sprintf("same account as line %d", first))
+			}
+			seenUsers[userID] = row.Line
+		} else if !opts.InviteUnknown {
+			row.Errors = append(row.Errors, "no account with this email")
+		}
+
+		if len(row.Errors) > 0 {
+			continue
+		}
+
+		previousRole, isMember := current[userID]
+		role := row.Role
+		switch {
+		case !found:
+			row.Action = models.RosterImportInvite
+		case isMember:
+			row.Action = models.RosterImportUpdate
+			if role == "" {
+				role = previousRole
+			}
+		default:
+			row.Action = models.RosterImportAdd
+			if role == "" {
+				role = permissions.RolePlayer
+			}
+		}
+		if role == "" {
+			role = permissions.RolePlayer
+		}
+		row.Role = role
+
+		//granting or taking away the coach role or a custom role needs roles.manage, like a single role change
+		guarded := permissions.GuardedRole(role) || (previousRole != "" && permissions.GuardedRole(previousRole))
+		if guarded && role != previousRole && !canManageRoles {
+			row.Errors = append(row.Errors, "changing to or from the coach role or a custom role requires roles.manage")
+			continue
+		}
+
+		savepoint := fmt.Sprintf("roster_row_%d", i)
+		if _, err := txs.ExecContext(ctx, "SAVEPOINT "+savepoint); err != nil {
+			return nil, err
+		}
+
+		if err := ts.importRosterRow(ctx, txs, teamID, reqUserID, row, now); err != nil {
+			if _, rbErr := txs.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+savepoint); rbErr != nil {
+				return nil, rbErr
+			}
+			switch {
+			case errors.Is(err, ErrNumberTaken):
+				row.Errors = append(row.Errors, fmt.Sprintf("number %d is already taken", *row.Number))
+			default:
+				log.Printf("roster import failed on line %d: %v", row.Line, err)
+				row.Errors = append(row.Errors, "could not save row")
+			}
+			continue
+		}
+
+		switch row.Action {
+		case models.RosterImportAdd:
+			current[userID] = role
+			changes = append(changes, rosterChange{events.RosterMemberAdded, userID, "", role})
+		case models.RosterImportUpdate:
+			current[userID] = role
+			if role != previousRole {
+				changes = append(changes, rosterChange{events.RosterRoleChanged, userID, previousRole, role})
+			}
+		}
+	}
+
+	coaches := 0
+	for _, role := range current {
+		if role == permissions.RoleCoach {
+			coaches++
+		}
+	}
+	if coaches == 0 {
+		report.Errors = append(report.Errors, ErrLastCoach.Error())
+	}
+
+	for i := range report.Rows {
+		row := &report.Rows[i]
+		switch {
+		case len(row.Errors) > 0:
+			row.Action = models.RosterImportError
+			report.Failed++
+		case row.Action == models.RosterImportAdd:
+			report.Added++
+		case row.Action == models.RosterImportUpdate:
+			report.Updated++
+		case row.Action == models.RosterImportInvite:
+			report.Invited++
+		}
+	}
+
+	if opts.DryRun || report.Failed > 0 || len(report.Errors) > 0 {
+		return report, nil
+	}
+
+	if err := txs.Commit(); err != nil {
+		return nil, err
+	}
+	report.Committed = true
+
+	for _, change := range changes {
+		ts.publishRosterChange(ctx, change.changeType, teamID, change.userID, change.previousRole, change.role, reqUserID)
+	}
+
+	return report, nil
+}
+
+// importRosterRow writes a single validated row inside the import transaction
+func (ts *TeamService) importRosterRow(ctx context.Context, txs *sql.Tx, teamID uuid.UUID, reqUserID uuid.UUID, row *models.RosterImportRow, now time.Time) error {
+	switch row.Action {
+	case models.RosterImportInvite:
+		var pending bool
+		query := `SELECT EXISTS(SELECT 1 FROM team_invites WHERE team_id=$1 AND LOWER(invitee_email)=$2 AND status=$3 AND (expires_at IS NULL OR expires_at > NOW()))`
+		if err := txs.QueryRowContext(ctx, query, teamID, row.Email, models.InviteStatusPending).Scan(&pending); err != nil {
+			return err
+		}
+		if pending {
+			row.Notes = append(row.Notes, "already invited")
+			return nil
+		}
+
+		insert := `INSERT INTO team_invites(team_id,invited_by,invitee_email,role) VALUES($1,$2,$3,$4)`
+		if _, err := txs.ExecContext(ctx, insert, teamID, reqUserID, row.Email, row.Role); err != nil {
+			return err
+		}
+		if row.Number != nil || row.Position != "" {
+			row.Notes = append(row.Notes, "number and position are not kept on invites, set them once the invite is accepted")
+		}
+		return nil
+
+	case models.RosterImportAdd:
+		query := `INSERT INTO team_members(team_id,role,joinedat,user_id,jersey_number,primary_position) VALUES($1,$2,$3,$4,$5,NULLIF($6,''))`
+		if _, err := txs.ExecContext(ctx, query, teamID, row.Role, now, *row.UserID, row.Number, row.Position); err != nil {
+			return positionWriteErr(err)
+		}
+		return positionWriteErr(ts.syncSeasonMember(ctx, txs, teamID, *row.UserID, now))
+
+	case models.RosterImportUpdate:
+		//blank csv cells keep the current number/position
+		query := `UPDATE team_members SET role=$1, jersey_number=COALESCE($2,jersey_number),
+		depth_order=CASE WHEN NULLIF($3,'') IS NULL OR primary_position IS NOT DISTINCT FROM NULLIF($3,'') THEN depth_order ELSE NULL END,
+		primary_position=COALESCE(NULLIF($3,''),primary_position)
+		WHERE team_id=$4 AND user_id=$5 RETURNING joinedat`
+
+		var joinedAt time.Time
+		if err := txs.QueryRowContext(ctx, query, row.Role, row.Number, row.Position, teamID, *row.UserID).Scan(&joinedAt); err != nil {
+			return positionWriteErr(err)
+		}
+		return positionWriteErr(ts.syncSeasonMember(ctx, txs, teamID, *row.UserID, joinedAt))
+	}
+	return nil
+}
+
+// GET :: current roster with the import columns first, for csv/json export
+func (ts *TeamService) ExportRoster(ctx context.Context, teamID uuid.UUID, reqUserID uuid.UUID) ([]models.RosterExportRow, error) {
+
+	if _, err := ts.requirePermission(ctx, teamID, reqUserID, permissions.RosterView); err != nil {
+		return nil, err
+	}
+
+	members, err := ts.GetTeamsMembers(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]uuid.UUID, 0, len(members))
+	for _, m := range members {
+		userIDs = append(userIDs, m.UserID)
+	}
+	profiles := ts.fetchProfiles(ctx, userIDs)
+
+	rows := make([]models.RosterExportRow, 0, len(members))
+	for _, m := range members {
+		row := models.RosterExportRow{
+			Role:              m.Role,
+			Number:            m.JerseyNumber,
+			Position:          m.PrimaryPosition,
+			SecondaryPosition: m.SecondaryPosition,
+			UserID:            m.UserID,
+			Joinedat:          m.Joinedat,
+		}
+		if profile, ok := profiles[m.UserID.String()]; ok {
+			row.Email = profile.GetEmail()
+			row.Firstname = profile.GetFirstname()
+			row.Lastname = profile.GetLastname()
+		}
+		//the export must stay importable, fall back to the auth db for the email
+		if row.Email == "" {
+			row.Email = ts.lookupUserEmail(ctx, m.UserID)
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
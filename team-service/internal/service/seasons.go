@@ -0,0 +1,403 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github/wycliff-ochieng/internal/models"
+	"github/wycliff-ochieng/internal/permissions"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// execer lets the season roster helpers run on the service db or inside a roster transaction
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+const seasonColumns = `id,team_id,name,start_date,end_date,status,createdat,updatedat`
+
+func scanSeason(row rowScanner) (*models.Season, error) {
+	var season models.Season
+	var start, end time.Time
+	if err := row.Scan(&season.SeasonID, &season.TeamID, &season.Name, &start, &end, &season.Status, &season.Createdat, &season.Updatedat); err != nil {
+		return nil, err
+	}
+	season.StartDate = start.Format(models.SeasonDateLayout)
+	season.EndDate = end.Format(models.SeasonDateLayout)
+	return &season, nil
+}
+
+func validSeasonStatus(status string) bool {
+	switch status {
+	case models.SeasonStatusPlanned, models.SeasonStatusActive, models.SeasonStatusCompleted:
+		return true
+	}
+	return false
+}
+
+func validateSeasonReq(req *models.SeasonReq) error {
+	req.Name = strings.TrimSpace(req.Name)
+	req.Status = strings.ToUpper(strings.TrimSpace(req.Status))
+	if req.Status == "" {
+		req.Status = models.SeasonStatusPlanned
+	}
+
+	if req.Name == "" || len(req.Name) > 100 || !validSeasonStatus(req.Status) {
+		return ErrBadRequest
+	}
+
+	start, err := time.Parse(models.SeasonDateLayout, req.StartDate)
+	if err != nil {
+		return ErrBadRequest
+	}
+	end, err := time.Parse(models.SeasonDateLayout, req.EndDate)
+	if err != nil || end.Before(start) {
+		return ErrBadRequest
+	}
+	return nil
+}
+
+// seasonWriteErr maps duplicate names and date checks to bad requests
+func seasonWriteErr(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && (pqErr.Code == "23505" || pqErr.Code == "23514") {
+		return ErrBadRequest
+	}
+	return err
+}
+
+// syncSeasonMember mirrors the member's current team_members row (a join, role or number change)
+// into the active season's roster, it must run after the team_members write
+func (ts *TeamService) syncSeasonMember(ctx context.Context, ex execer, teamID uuid.UUID, userID uuid.UUID, at time.Time) error {
+	query := `INSERT INTO season_roster(season_id,team_id,user_id,role,joinedat,jersey_number)
+	SELECT s.id,s.team_id,tm.user_id,tm.role,$3,tm.jersey_number
+	FROM team_seasons s JOIN team_members tm ON tm.team_id = s.team_id AND tm.user_id=$2
+	WHERE s.team_id=$1 AND s.status='ACTIVE'
+	ON CONFLICT (season_id,user_id) DO UPDATE SET role=EXCLUDED.role, jersey_number=EXCLUDED.jersey_number, leftat=NULL`
+	_, err := ex.ExecContext(ctx, query, teamID, userID, at)
+	return err
+}
+
+// closeSeasonMember marks a member as gone from the active season without losing the history
+func (ts *TeamService) closeSeasonMember(ctx context.Context, ex execer, teamID uuid.UUID, userID uuid.UUID, at time.Time) error {
+	query := `UPDATE season_roster sr SET leftat=$3 FROM team_seasons s
+	WHERE s.id = sr.season_id AND s.team_id=$1 AND s.status='ACTIVE' AND sr.user_id=$2 AND sr.leftat IS NULL`
+	_, err := ex.ExecContext(ctx, query, teamID, userID, at)
+	return err
+}
+
+// activateSeason completes any other running season and seeds the roster from the current members
+func (ts *TeamService) activateSeason(ctx context.Context, tx *sql.Tx, teamID uuid.UUID, seasonID uuid.UUID) error {
+	complete := `UPDATE team_seasons SET status='COMPLETED', updatedat=NOW() WHERE team_id=$1 AND status='ACTIVE' AND id<>$2`
+	if _, err := tx.ExecContext(ctx, complete, teamID, seasonID); err != nil {
+		return err
+	}
+
+	seed := `INSERT INTO season_roster(season_id,team_id,user_id,role,joinedat,jersey_number)
+	SELECT s.id,tm.team_id,tm.user_id,tm.role,GREATEST(tm.joinedat, s.start_date::timestamp),tm.jersey_number
+	FROM team_members tm JOIN team_seasons s ON s.id=$2 WHERE tm.team_id=$1
+	ON CONFLICT (season_id,user_id) DO NOTHING`
+	_, err := tx.ExecContext(ctx, seed, teamID, seasonID)
+	return err
+}
+
+// POST :: create a season for a team
+func (ts *TeamService) CreateSeason(ctx context.Context, teamID uuid.UUID, reqUserID uuid.UUID, req models.SeasonReq) (*models.Season, error) {
+
+	if err := validateSeasonReq(&req); err != nil {
+		return nil, err
+	}
+
+	if _, err := ts.requirePermission(ctx, teamID, reqUserID, permissions.TeamUpdate); err != nil {
+		return nil, err
+	}
+
+	txs, err := ts.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer txs.Rollback()
+
+	if req.Status == models.SeasonStatusActive {
+		//free the one-active slot before inserting
+		if _, err := txs.ExecContext(ctx, `UPDATE team_seasons SET status='COMPLETED', updatedat=NOW() WHERE team_id=$1 AND status='ACTIVE'`, teamID); err != nil {
+			return nil, err
+		}
+	}
+
+	query := `INSERT INTO team_seasons(team_id,name,start_date,end_date,status) VALUES($1,$2,$3,$4,$5) RETURNING ` + seasonColumns
+	season, err := scanSeason(txs.QueryRowContext(ctx, query, teamID, req.Name, req.StartDate, req.EndDate, req.Status))
+	if err != nil {
+		return nil, seasonWriteErr(err)
+	}
+
+	if season.Status == models.SeasonStatusActive {
+		if err := ts.activateSeason(ctx, txs, teamID, season.SeasonID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := txs.Commit(); err != nil {
+		return nil, err
+	}
+	return season, nil
+}
+
+// GET :: seasons of a team, newest first
+func (ts *TeamService) ListSeasons(ctx context.Context, teamID uuid.UUID, reqUserID uuid.UUID) ([]models.Season, error) {
+
+	canView, err := ts.canViewTeam(ctx, teamID, reqUserID)
+	if err != nil {
+		return nil, err
+	}
+	if !canView {
+		return nil, ErrForbidden
+	}
+
+	rows, err := ts.db.QueryContext(ctx, `SELECT `+seasonColumns+` FROM team_seasons WHERE team_id=$1 ORDER BY start_date DESC`, teamID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	seasons := make([]models.Season, 0)
+	for rows.Next() {
+		season, err := scanSeason(rows)
+		if err != nil {
+			return nil, err
+		}
+		seasons = append(seasons, *season)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return seasons, nil
+}
+
+func (ts *TeamService) getSeason(ctx context.Context, teamID uuid.UUID, seasonID uuid.UUID) (*models.Season, error) {
+	season, err := scanSeason(ts.db.QueryRowContext(ctx, `SELECT `+seasonColumns+` FROM team_seasons WHERE id=$1 AND team_id=$2`, seasonID, teamID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return season, nil
+}
+
+// PUT :: rename, re-date or change the status of a season
+func (ts *TeamService) UpdateSeason(ctx context.Context, teamID uuid.UUID, seasonID uuid.UUID, reqUserID uuid.UUID, req models.SeasonReq) (*models.Season, error) {
+
+	if err := validateSeasonReq(&req); err != nil {
+		return nil, err
+	}
+
+	if _, err := ts.requirePermission(ctx, teamID, reqUserID, permissions.TeamUpdate); err != nil {
+		return nil, err
+	}
+
+	txs, err := ts.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer txs.Rollback()
+
+	if req.Status == models.SeasonStatusActive {
+		if err := ts.activateSeason(ctx, txs, teamID, seasonID); err != nil {
+			return nil, err
+		}
+	}
+
+	query := `UPDATE team_seasons SET name=$1, start_date=$2, end_date=$3, status=$4, updatedat=NOW()
+	WHERE id=$5 AND team_id=$6 RETURNING ` + seasonColumns
+	season, err := scanSeason(txs.QueryRowContext(ctx, query, req.Name, req.StartDate, req.EndDate, req.Status, seasonID, teamID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, seasonWriteErr(err)
+	}
+
+	if err := txs.Commit(); err != nil {
+		return nil, err
+	}
+	return season, nil
+}
+
+// POST :: carry the previous season's players that are still on the team into this season
+func (ts *TeamService) RolloverSeason(ctx context.Context, teamID uuid.UUID, seasonID uuid.UUID, reqUserID uuid.UUID, req models.RolloverReq) (*models.SeasonRoster, error) {
+
+	if _, err := ts.requirePermission(ctx, teamID, reqUserID, permissions.RosterManage); err != nil {
+		return nil, err
+	}
+
+	target, err := ts.getSeason(ctx, teamID, seasonID)
+	if err != nil {
+		return nil, err
+	}
+
+	var fromSeasonID uuid.UUID
+	if req.FromSeasonID != nil {
+		if *req.FromSeasonID == seasonID {
+			return nil, ErrBadRequest
+		}
+		if _, err := ts.getSeason(ctx, teamID, *req.FromSeasonID); err != nil {
+			return nil, err
+		}
+		fromSeasonID = *req.FromSeasonID
+	} else {
+		query := `SELECT id FROM team_seasons WHERE team_id=$1 AND start_date < $2 ORDER BY start_date DESC LIMIT 1`
+		if err := ts.db.QueryRowContext(ctx, query, teamID, target.StartDate).Scan(&fromSeasonID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, ErrNotFound
+			}
+			return nil, err
+		}
+	}
+
+	//current team roles win over last season's, entries already in the target are kept
+	query := `INSERT INTO season_roster(season_id,team_id,user_id,role,joinedat,jersey_number)
+	SELECT $1, tm.team_id, tm.user_id, tm.role, $4::date::timestamp, tm.jersey_number
+	FROM season_roster prev JOIN team_members tm ON tm.team_id = prev.team_id AND tm.user_id = prev.user_id
+	WHERE prev.season_id=$2 AND prev.team_id=$3 AND prev.leftat IS NULL
+	ON CONFLICT (season_id,user_id) DO NOTHING`
+	result, err := ts.db.ExecContext(ctx, query, seasonID, fromSeasonID, teamID, target.StartDate)
+	if err != nil {
+		return nil, err
+	}
+	if n, err := result.RowsAffected(); err == nil {
+		log.Printf("rolled %d members from season %s into %s", n, fromSeasonID, seasonID)
+	}
+
+	members, err := ts.seasonRosterMembers(ctx, seasonID, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &models.SeasonRoster{Season: target, Members: members}, nil
+}
+
+// seasonRosterMembers lists a season's roster, when day is set only members on the roster that day
+func (ts *TeamService) seasonRosterMembers(ctx context.Context, seasonID uuid.UUID, day *time.Time) ([]models.SeasonRosterMember, error) {
+	query := `SELECT user_id,role,joinedat,leftat,jersey_number FROM season_roster WHERE season_id=$1 ORDER BY joinedat`
+	args := []interface{}{seasonID}
+	if day != nil {
+		query = `SELECT user_id,role,joinedat,leftat,jersey_number FROM season_roster
+		WHERE season_id=$1 AND joinedat < $3 AND (leftat IS NULL OR leftat >= $2) ORDER BY joinedat`
+		args = append(args, *day, day.AddDate(0, 0, 1))
+	}
+
+	rows, err := ts.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := make([]models.SeasonRosterMember, 0)
+	for rows.Next() {
+		var member models.SeasonRosterMember
+		var leftAt sql.NullTime
+		var number sql.NullInt64
+		if err := rows.Scan(&member.UserID, &member.Role, &member.Joinedat, &leftAt, &number); err != nil {
+			return nil, err
+		}
+		if leftAt.Valid {
+			member.Leftat = &leftAt.Time
+		}
+		if number.Valid {
+			n := int(number.Int64)
+			member.JerseyNumber = &n
+		}
+		members = append(members, member)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+func (ts *TeamService) withProfiles(ctx context.Context, members []models.SeasonRosterMember) {
+	ids := make([]uuid.UUID, 0, len(members))
+	for _, m := range members {
+		ids = append(ids, m.UserID)
+	}
+	profiles := ts.fetchProfiles(ctx, ids)
+	for i := range members {
+		if profile, found := profiles[members[i].UserID.String()]; found {
+			members[i].Firstname = profile.GetFirstname()
+			members[i].Lastname = profile.GetLastname()
+			members[i].Email = profile.GetEmail()
+		}
+	}
+}
+
+// GET :: full roster history of a season
+func (ts *TeamService) GetSeasonRoster(ctx context.Context, teamID uuid.UUID, seasonID uuid.UUID, reqUserID uuid.UUID) (*models.SeasonRoster, error) {
+
+	canView, err := ts.canViewTeam(ctx, teamID, reqUserID)
+	if err != nil {
+		return nil, err
+	}
+	if !canView {
+		return nil, ErrForbidden
+	}
+
+	season, err := ts.getSeason(ctx, teamID, seasonID)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := ts.seasonRosterMembers(ctx, seasonID, nil)
+	if err != nil {
+		return nil, err
+	}
+	ts.withProfiles(ctx, members)
+
+	return &models.SeasonRoster{Season: season, Members: members}, nil
+}
+
+// RosterOnDate answers who was on the team's roster on a given day, used by REST and gRPC.
+// The running season wins when seasons overlap; with no season covering the day the roster is empty
+func (ts *TeamService) RosterOnDate(ctx context.Context, teamID uuid.UUID, day time.Time) (*models.SeasonRoster, error) {
+
+	day = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+
+	query := `SELECT ` + seasonColumns + ` FROM team_seasons WHERE team_id=$1 AND start_date <= $2 AND end_date >= $2
+	ORDER BY (status = 'ACTIVE') DESC, start_date DESC LIMIT 1`
+	season, err := scanSeason(ts.db.QueryRowContext(ctx, query, teamID, day))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &models.SeasonRoster{Members: []models.SeasonRosterMember{}}, nil
+		}
+		return nil, err
+	}
+
+	members, err := ts.seasonRosterMembers(ctx, season.SeasonID, &day)
+	if err != nil {
+		return nil, err
+	}
+	return &models.SeasonRoster{Season: season, Members: members}, nil
+}
+
+// GET :: roster on a date for members of the team
+func (ts *TeamService) GetRosterOnDate(ctx context.Context, teamID uuid.UUID, reqUserID uuid.UUID, day time.Time) (*models.SeasonRoster, error) {
+
+	canView, err := ts.canViewTeam(ctx, teamID, reqUserID)
+	if err != nil {
+		return nil, err
+	}
+	if !canView {
+		return nil, ErrForbidden
+	}
+
+	roster, err := ts.RosterOnDate(ctx, teamID, day)
+	if err != nil {
+		return nil, err
+	}
+	ts.withProfiles(ctx, roster.Members)
+	return roster, nil
+}
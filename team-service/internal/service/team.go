@@ -7,26 +7,33 @@ import (
 	"fmt"
 	"github/wycliff-ochieng/internal/config"
 	"github/wycliff-ochieng/internal/database"
+	"github/wycliff-ochieng/internal/filestore"
 	"github/wycliff-ochieng/internal/models"
+	"github/wycliff-ochieng/internal/permissions"
 	internal "github/wycliff-ochieng/internal/producer"
 	"log"
 	"time"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 
 	"github.com/google/uuid"
+	"github.com/wycliff-ochieng/common_packages/events"
 	"github.com/wycliff-ochieng/sports-common-package/user_grpc/user_proto"
 	//middleware "github.com/wycliff-ochieng/common_packages"
 )
 
 var ErrForbidden = errors.New("user not allowed here")
 var ErrNotFound = errors.New("team not found/ does not exist")
+var ErrLastCoach = errors.New("a team must keep at least one coach")
 
 type TeamService struct {
 	db         database.DBInterface
 	userClient user_proto.UserServiceRPCClient
 	prod       internal.KafkaProducer
 	authDB     *sql.DB
+	files      *filestore.FileStore
+
+	deleteGrace time.Duration
 }
 
 type updateTeamReq struct {
@@ -38,9 +45,13 @@ type updateTeamReq struct {
 	Updatedat   time.Time `json:"updatedat"`
 }
 
-func NewTeamService(db database.DBInterface, userClient user_proto.UserServiceRPCClient, producer internal.KafkaProducer, cfg *config.Config) *TeamService {
+func NewTeamService(db database.DBInterface, userClient user_proto.UserServiceRPCClient, producer internal.KafkaProducer, files *filestore.FileStore, cfg *config.Config) *TeamService {
 	var authDB *sql.DB
+	deleteGrace := DefaultDeleteGracePeriod
 	if cfg != nil {
+		if cfg.TeamDeleteGraceDays >= 0 {
+			deleteGrace = time.Duration(cfg.TeamDeleteGraceDays) * 24 * time.Hour
+		}
 		dsn := fmt.Sprintf(
 			"postgres://%s:%s@%s:%d/%s?sslmode=%s",
 			cfg.DBUser,
@@ -63,14 +74,30 @@ func NewTeamService(db database.DBInterface, userClient user_proto.UserServiceRP
 		userClient: userClient,
 		prod:       producer,
 		authDB:     authDB,
+		files:      files,
+
+		deleteGrace: deleteGrace,
 	}
 }
 
 // POST :: creating team /api/team/create
-func (ts *TeamService) CreateTeam(ctx context.Context, reqUserID uuid.UUID, teamID uuid.UUID, name string, sport string, description string, createdat, updatedat time.Time) (*models.Team, error) {
+func (ts *TeamService) CreateTeam(ctx context.Context, reqUserID uuid.UUID, teamID uuid.UUID, orgID uuid.UUID, name string, sport string, description string, createdat, updatedat time.Time) (*models.Team, error) {
 
 	var team *models.Team
 
+	//teams without an organization go to the default one, anything else needs an org admin
+	if orgID == uuid.Nil {
+		orgID = DefaultOrganizationID
+	} else if orgID != DefaultOrganizationID {
+		isAdmin, err := ts.IsOrgAdmin(ctx, orgID, reqUserID)
+		if err != nil {
+			return nil, err
+		}
+		if !isAdmin {
+			return nil, ErrForbidden
+		}
+	}
+
 	team, err := models.NewTeam(teamID, name, sport, description, createdat, updatedat)
 	if err != nil {
 		log.Fatalf("error creating new team")
@@ -83,11 +110,11 @@ func (ts *TeamService) CreateTeam(ctx context.Context, reqUserID uuid.UUID, team
 
 	defer txs.Rollback()
 
-	query := `INSERT INTO teams(id,name,sports,description,createdat,updatedat) VALUES($1,$2,$3,$4,$5,$6)`
+	query := `INSERT INTO teams(id,name,sports,description,createdat,updatedat,organization_id) VALUES($1,$2,$3,$4,$5,$6,$7)`
 
 	addMemberQuery := `INSERT INTO team_members(team_id,role,joinedat,user_id) VALUES($1,$2,NOW(),$3)`
 
-	if _, err = txs.ExecContext(ctx, query, team.TeamID, team.Name, team.Sport, team.Description, team.Createdat, team.Updatedat); err != nil {
+	if _, err = txs.ExecContext(ctx, query, team.TeamID, team.Name, team.Sport, team.Description, team.Createdat, team.Updatedat, orgID); err != nil {
 		log.Printf("ERROR creating team due to: %v", err)
 		return nil, err
 	}
@@ -96,7 +123,7 @@ func (ts *TeamService) CreateTeam(ctx context.Context, reqUserID uuid.UUID, team
 	//	return nil,err
 	//}
 
-	if _, err = txs.ExecContext(ctx, addMemberQuery, team.TeamID, "coach", reqUserID); err != nil {
+	if _, err = txs.ExecContext(ctx, addMemberQuery, team.TeamID, permissions.RoleCoach, reqUserID); err != nil {
 		log.Printf("Error creating team due to: %s", err)
 		return nil, err
 	}
@@ -106,11 +133,14 @@ func (ts *TeamService) CreateTeam(ctx context.Context, reqUserID uuid.UUID, team
 	}
 
 	return &models.Team{
-		TeamID:      teamID,
-		Name:        name,
-		Sport:       sport,
-		Description: description,
-		Createdat:   createdat,
+		TeamID:         team.TeamID,
+		OrganizationID: orgID,
+		Name:           name,
+		Sport:          sport,
+		Description:    description,
+		Visibility:     models.TeamVisibilityPrivate,
+		Createdat:      team.Createdat,
+		TeamLifecycle:  models.TeamLifecycle{Status: models.TeamStatusActive},
 	}, nil
 }
 
@@ -120,7 +150,9 @@ func (ts *TeamService) GetMyTeams(ctx context.Context, userID uuid.UUID) (*[]mod
 	var teams []models.TeamInfo
 	//	var members models.TeamMembers
 
-	query := `SELECT t.id,t.name,t.sports,tm.Role,t.description,t.createdat,tm.joinedat FROM teams t  JOIN team_members tm ON  t.id = tm.team_id WHERE tm.user_id = $1`
+	query := `SELECT t.id,t.organization_id,t.name,t.sports,tm.Role,t.description,t.createdat,tm.joinedat,
+	t.logo_object_key,t.primary_color,t.secondary_color,t.home_venue,t.founded_year,t.social_links
+	FROM teams t  JOIN team_members tm ON  t.id = tm.team_id WHERE tm.user_id = $1 AND t.status = 'ACTIVE'`
 
 	rows, err := ts.db.QueryContext(ctx, query, userID)
 	if err != nil {
@@ -131,19 +163,23 @@ func (ts *TeamService) GetMyTeams(ctx context.Context, userID uuid.UUID) (*[]mod
 	for rows.Next() {
 		//var myTeams models.Team
 		var myTeams models.TeamInfo
+		var branding brandingRow
 
-		err := rows.Scan(
+		dest := []interface{}{
 			&myTeams.TeamID,
+			&myTeams.OrganizationID,
 			&myTeams.Name,
 			&myTeams.Sport,
 			&myTeams.Role,
 			&myTeams.Description,
 			&myTeams.Updatedat,
 			&myTeams.Joinedat,
-		)
+		}
+		err := rows.Scan(append(dest, branding.dest()...)...)
 		if err != nil {
 			log.Fatalf("Failed to loop through all teams : %v", err)
 		}
+		myTeams.TeamBranding = ts.branding(ctx, &branding)
 		teams = append(teams, myTeams)
 	}
 	if err := rows.Err(); err != nil {
@@ -152,20 +188,68 @@ func (ts *TeamService) GetMyTeams(ctx context.Context, userID uuid.UUID) (*[]mod
 	return &teams, nil
 }
 
+// UserTeams lists the active teams the user is an active member of, with the user's role, for
+// other services; it skips the branding lookups GetMyTeams does
+func (ts *TeamService) UserTeams(ctx context.Context, userID uuid.UUID) ([]models.TeamInfo, error) {
+	query := `SELECT t.id,t.organization_id,t.name,t.sports,tm.role FROM teams t JOIN team_members tm ON t.id = tm.team_id
+	WHERE tm.user_id = $1 AND tm.status = 'ACTIVE' AND t.status = 'ACTIVE' ORDER BY t.name`
+
+	rows, err := ts.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var teams []models.TeamInfo
+	for rows.Next() {
+		var team models.TeamInfo
+		if err := rows.Scan(&team.TeamID, &team.OrganizationID, &team.Name, &team.Sport, &team.Role); err != nil {
+			return nil, err
+		}
+		teams = append(teams, team)
+	}
+	return teams, rows.Err()
+}
+
 // single team for a single user  ->  change this to repo service - > team details
 func (ts *TeamService) GetTeamByID(ctx context.Context, teamID uuid.UUID) (*models.Team, error) {
 	var AllTeams models.Team
-	query := `SELECT * FROM teams WHERE id=$1`
-	err := ts.db.QueryRowContext(ctx, query, teamID).Scan(
+	var branding brandingRow
+	var archivedAt, deleteAfter sql.NullTime
+	var archivedBy uuid.NullUUID
+	query := `SELECT id,organization_id,name,sports,description,visibility,createdat,updatedat,
+	status,archived_at,archived_by,delete_after,
+	NOT EXISTS(SELECT 1 FROM team_members tm WHERE tm.team_id = teams.id AND tm.role='coach' AND tm.status='ACTIVE'),
+	logo_object_key,primary_color,secondary_color,home_venue,founded_year,social_links FROM teams WHERE id=$1`
+	dest := []interface{}{
 		&AllTeams.TeamID,
+		&AllTeams.OrganizationID,
 		&AllTeams.Name,
 		&AllTeams.Sport,
 		&AllTeams.Description,
+		&AllTeams.Visibility,
 		&AllTeams.Createdat,
-		&AllTeams.Updatedat)
+		&AllTeams.Updatedat,
+		&AllTeams.Status,
+		&archivedAt,
+		&archivedBy,
+		&deleteAfter,
+		&AllTeams.NeedsCoach,
+	}
+	err := ts.db.QueryRowContext(ctx, query, teamID).Scan(append(dest, branding.dest()...)...)
 	if err != nil {
 		return nil, err
 	}
+	AllTeams.TeamBranding = ts.branding(ctx, &branding)
+	if archivedAt.Valid {
+		AllTeams.ArchivedAt = &archivedAt.Time
+	}
+	if archivedBy.Valid {
+		AllTeams.ArchivedBy = &archivedBy.UUID
+	}
+	if deleteAfter.Valid {
+		AllTeams.DeleteAfter = &deleteAfter.Time
+	}
 	return &AllTeams, err
 }
 
@@ -195,7 +279,8 @@ func (ts *TeamService) IsTeam(ctx context.Context, teamID uuid.UUID) (bool, erro
 // repo service
 func (ts *TeamService) GetTeamsMembers(ctx context.Context, teamID uuid.UUID) ([]*models.TeamMembers, error) {
 	var teamMembers []*models.TeamMembers
-	query := `SELECT team_id,role,joinedat,user_id FROM team_members WHERE team_id=$1`
+	query := `SELECT team_id,role,status,joinedat,user_id,jersey_number,primary_position,secondary_position,depth_order
+	FROM team_members WHERE team_id=$1 ORDER BY primary_position NULLS LAST, depth_order NULLS LAST, joinedat`
 	rows, err := ts.db.QueryContext(ctx, query, teamID)
 	if err != nil {
 		log.Fatalf("Error: issue with fetchiing team members: %v", err)
@@ -204,16 +289,20 @@ func (ts *TeamService) GetTeamsMembers(ctx context.Context, teamID uuid.UUID) ([
 
 	for rows.Next() {
 		var members models.TeamMembers
+		var position positionRow
 
-		err := rows.Scan(
+		dest := []interface{}{
 			&members.TeamID,
 			&members.Role,
+			&members.Status,
 			&members.Joinedat,
 			&members.UserID,
-		)
+		}
+		err := rows.Scan(append(dest, position.dest()...)...)
 		if err != nil {
 			log.Fatalf("Error scanning rows: %v", err)
 		}
+		members.MemberPosition = position.position()
 		teamMembers = append(teamMembers, &members)
 	}
 	if err := rows.Err(); err != nil {
@@ -228,8 +317,8 @@ func (ts *TeamService) GetTeamByUUID(tcx context.Context) {}
 // GET :: get a single team by ID
 func (ts *TeamService) GetTeamDetails(ctx context.Context, reqUserID uuid.UUID, teamID uuid.UUID) (*models.TeamDetailsInfo, error) {
 
-	//is the user a member - > authorization check -> are you team member
-	isTeamMember, err := ts.IsTeamMember(ctx, reqUserID, teamID)
+	//is the user a member (or org admin) - > authorization check -> are you team member
+	isTeamMember, err := ts.canViewTeam(ctx, teamID, reqUserID)
 	if err != nil {
 		log.Fatalf("Error cecking membership: %v", err)
 		return nil, err
@@ -253,13 +342,21 @@ func (ts *TeamService) GetTeamDetails(ctx context.Context, reqUserID uuid.UUID,
 		return nil, err
 	}
 
+	announcements, err := ts.listAnnouncements(ctx, teamID, reqUserID, false)
+	if err != nil {
+		return nil, err
+	}
+
 	if len(allTeamMembers) == 0 {
 		return &models.TeamDetailsInfo{
-			TeamID:      team.TeamID,
-			Name:        team.Name,
-			Sport:       team.Sport,
-			Description: team.Description,
-			Members:     []models.TeamMembers{},
+			TeamID:         team.TeamID,
+			OrganizationID: team.OrganizationID,
+			Name:           team.Name,
+			Sport:          team.Sport,
+			Description:    team.Description,
+			TeamBranding:   team.TeamBranding,
+			Members:        []models.TeamMembers{},
+			Announcements:  announcements,
 		}, nil
 	}
 
@@ -286,11 +383,14 @@ func (ts *TeamService) GetTeamDetails(ctx context.Context, reqUserID uuid.UUID,
 
 	//gather final reponse struct
 	finalResponse := models.TeamDetailsInfo{
-		TeamID:      team.TeamID,
-		Name:        team.Name,
-		Sport:       team.Sport,
-		Description: team.Description,
-		Members:     make([]models.TeamMembers, 0, len(allTeamMembers)),
+		TeamID:         team.TeamID,
+		OrganizationID: team.OrganizationID,
+		Name:           team.Name,
+		Sport:          team.Sport,
+		Description:    team.Description,
+		TeamBranding:   team.TeamBranding,
+		Members:        make([]models.TeamMembers, 0, len(allTeamMembers)),
+		Announcements:  announcements,
 		//Joinedat: team.Createdat,
 		//Updatedat: team.Updatedat,
 	}
@@ -302,10 +402,11 @@ func (ts *TeamService) GetTeamDetails(ctx context.Context, reqUserID uuid.UUID,
 		if !found {
 			log.Println("Warning , team member does no exists in the system")
 			finalResponse.Members = append(finalResponse.Members, models.TeamMembers{
-				TeamID:   member.TeamID,
-				UserID:   member.UserID,
-				Role:     member.Role,
-				Joinedat: member.Joinedat,
+				TeamID:         member.TeamID,
+				UserID:         member.UserID,
+				Role:           member.Role,
+				Joinedat:       member.Joinedat,
+				MemberPosition: member.MemberPosition,
 			})
 			continue
 		}
@@ -323,6 +424,8 @@ func (ts *TeamService) GetTeamDetails(ctx context.Context, reqUserID uuid.UUID,
 			Firstname: profile.GetFirstname(),
 			Lastname:  profile.GetLastname(),
 			Email:     profile.GetEmail(),
+
+			MemberPosition: member.MemberPosition,
 		})
 	}
 	return &finalResponse, nil
@@ -337,20 +440,10 @@ func (ts *TeamService) UpdateTeamDetails(ctx context.Context, teamID uuid.UUID,
 
 	defer txs.Rollback()
 
-	//check if team exists
-	//teamID,err := ts.GetTeamByID()
-	role, err := ts.GetRoleForUser(ctx, teamID, reqUserID)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			log.Println("user is not a memeber of this team")
-		}
+	//check permission on this team
+	if _, err := ts.requirePermission(ctx, teamID, reqUserID, permissions.TeamUpdate); err != nil {
 		return nil, err
 	}
-	//check roles
-	isUserAuthorized := role == "coach" || role == "manager" || role == "player"
-	if !isUserAuthorized {
-		log.Fatal("ERROR : user is not Allowed to edit")
-	}
 
 	//if they  are authorized: Database write operation
 	updatedTeam, err := ts.UpdateTeam(ctx, teamID, updateData)
@@ -367,13 +460,16 @@ func (ts *TeamService) UpdateTeamDetails(ctx context.Context, teamID uuid.UUID,
 		log.Fatalf("Failed to commit the transaction, rollback wikk be initiated: %v", err)
 	}
 
-	var updateTeam models.UpdateTeamReq
-
-	//TODO LATER :: produce the updateTeam Event to a Kafka topic  -> High Priority
-	err = ts.prod.PublishTeamUpdate(ctx, updateTeam.TeamID)
-	if err != nil {
-		log.Printf("kafka error: %s", err)
-		return nil, fmt.Errorf("error publishing event to topic")
+	//best effort, the update is already committed
+	event := events.TeamUpdated{
+		TeamID:      updatedTeam.TeamID,
+		Name:        updatedTeam.Name,
+		Sport:       updatedTeam.Sport,
+		Description: updatedTeam.Description,
+		UpdatedBy:   reqUserID,
+	}
+	if err := ts.prod.PublishTeamUpdate(ctx, event); err != nil {
+		log.Printf("kafka error publishing %s: %s", events.TypeTeamUpdated, err)
 	}
 
 	return updatedTeam, nil
@@ -445,15 +541,22 @@ func (ts *TeamService) AddTeamMember(ctx context.Context, teamID uuid.UUID, reqU
 		return nil,err
 	}*/
 
-	//query team memebr to get role -> Auhtorization check
-	role, err := ts.GetRoleForUser(ctx, teamID, reqUserID)
-	if err != nil {
+	//permission check against the requester's team role
+	if _, err := ts.requirePermission(ctx, teamID, reqUserID, permissions.RosterManage); err != nil {
 		return nil, err
 	}
 
-	isAllowed := role == "coach" || role == "manager"
-	if !isAllowed {
-		return nil, ErrForbidden
+	validRole, err := ts.roleExists(ctx, teamID, addMember.Role)
+	if err != nil {
+		return nil, err
+	}
+	if !validRole {
+		return nil, ErrBadRequest
+	}
+	if permissions.GuardedRole(addMember.Role) {
+		if _, err := ts.requirePermission(ctx, teamID, reqUserID, permissions.RolesManage); err != nil {
+			return nil, err
+		}
 	}
 
 	// Resolve target user from UUID or email
@@ -463,20 +566,19 @@ func (ts *TeamService) AddTeamMember(ctx context.Context, teamID uuid.UUID, reqU
 	}
 	addMember.UserID = resolvedUserID
 
-	addedMember, err := ts.AddMember(ctx, teamID, addMember)
+	addedMember, err := ts.AddMember(ctx, txs, teamID, addMember)
 	if err != nil {
-		log.Fatal("Error: Failed to add Member to team due to: ")
 		return nil, err
 	}
 
 	if err := txs.Commit(); err != nil {
-		log.Fatal("Error commit the transaction due to :")
 		return nil, err
 	}
 	return addedMember, nil
 }
 
-func (ts *TeamService) AddMember(ctx context.Context, teamID uuid.UUID, addedMember models.AddMemberReq) (*models.TeamMembers, error) {
+// AddMember inserts the membership and its active season roster row in tx
+func (ts *TeamService) AddMember(ctx context.Context, tx *sql.Tx, teamID uuid.UUID, addedMember models.AddMemberReq) (*models.TeamMembers, error) {
 	joinedAt := addedMember.Joinedat
 	if joinedAt.IsZero() {
 		joinedAt = time.Now().UTC()
@@ -484,7 +586,15 @@ func (ts *TeamService) AddMember(ctx context.Context, teamID uuid.UUID, addedMem
 
 	query := `INSERT INTO team_members(team_id,role,joinedat,user_id) VALUES($1,$2,$3,$4)`
 
-	if _, err := ts.db.ExecContext(ctx, query, teamID, addedMember.Role, joinedAt, addedMember.UserID); err != nil {
+	if _, err := tx.ExecContext(ctx, query, teamID, addedMember.Role, joinedAt, addedMember.UserID); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return nil, ErrAlreadyMember
+		}
+		return nil, err
+	}
+
+	if err := ts.syncSeasonMember(ctx, tx, teamID, addedMember.UserID, joinedAt); err != nil {
 		return nil, err
 	}
 
@@ -548,7 +658,11 @@ func (ts *TeamService) GetTeamMebers(ctx context.Context, teamID uuid.UUID, user
 			Firstname: profile.GetFirstname(),
 			Lastname:  profile.GetLastname(),
 			Email:     profile.GetEmail(),
+			Role:      member.Role,
+			Status:    member.Status,
 			Createdat: member.Joinedat,
+
+			MemberPosition: member.MemberPosition,
 		}
 
 		finalTeamList = append(finalTeamList, mergedList)
@@ -557,50 +671,99 @@ func (ts *TeamService) GetTeamMebers(ctx context.Context, teamID uuid.UUID, user
 	return finalTeamList, nil
 }
 
-func (ts *TeamService) UpdateMemberRole(ctx context.Context, userID uuid.UUID, teamID uuid.UUID, newRole string) (int64, error) {
-	//var member models.TeamMembers
-	query := `UPDATE team_members SET role=$1 ,updated_at=NOW() WHERE userid=$1 AND teamid=$2`
+// repo service -> runs inside the roster transaction
+func (ts *TeamService) UpdateMemberRole(ctx context.Context, tx *sql.Tx, userID uuid.UUID, teamID uuid.UUID, newRole string) (int64, error) {
+	query := `UPDATE team_members SET role=$1 WHERE user_id=$2 AND team_id=$3`
 
-	result, err := ts.db.ExecContext(ctx, query)
+	result, err := tx.ExecContext(ctx, query, newRole, userID, teamID)
 	if err != nil {
 		return 0, err
 	}
 	return result.RowsAffected()
 }
 
-func (ts *TeamService) UpdateTeamMembersRoles(ctx context.Context, userID uuid.UUID, teamID uuid.UUID, req models.UpdateTeamMemberReq) (*models.TeamMembers, error) {
+// lockRosterRoles locks the coach rows of a team and the target member row so that
+// concurrent removals/demotions cannot leave a team without a coach. It returns the target's role
+// and the number of active coaches other than the target, suspended coaches do not count
+func (ts *TeamService) lockRosterRoles(ctx context.Context, tx *sql.Tx, teamID uuid.UUID, userID uuid.UUID) (string, int, error) {
+	var otherCoaches int
+	var targetRole string
 
-	txs, err := ts.db.BeginTx(ctx, nil)
+	query := `SELECT user_id,role,status FROM team_members WHERE team_id=$1 AND (role='coach' OR user_id=$2) FOR UPDATE`
+
+	rows, err := tx.QueryContext(ctx, query, teamID, userID)
+	if err != nil {
+		return "", 0, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var memberID uuid.UUID
+		var role, status string
+		if err := rows.Scan(&memberID, &role, &status); err != nil {
+			return "", 0, err
+		}
+		if memberID == userID {
+			targetRole = role
+			continue
+		}
+		if role == permissions.RoleCoach && status == models.MemberStatusActive {
+			otherCoaches++
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return "", 0, err
+	}
+
+	if targetRole == "" {
+		return "", otherCoaches, ErrNotFound
+	}
+	return targetRole, otherCoaches, nil
+}
+
+// PUT :: coach/manager changes the team role of a member
+func (ts *TeamService) UpdateTeamMembersRoles(ctx context.Context, reqUserID uuid.UUID, targetUserID uuid.UUID, teamID uuid.UUID, req models.UpdateTeamMemberReq) (*models.TeamMembers, error) {
+
+	validRole, err := ts.roleExists(ctx, teamID, req.Role)
 	if err != nil {
 		return nil, err
 	}
+	if !validRole {
+		return nil, ErrBadRequest
+	}
 
-	defer txs.Rollback()
-	//check if team_id exists in team table
-	team, err := ts.IsTeam(ctx, teamID)
+	//permission check against the requester's team role
+	if _, err := ts.requirePermission(ctx, teamID, reqUserID, permissions.RosterManage); err != nil {
+		return nil, err
+	}
+
+	canManageRoles, _, err := ts.HasPermission(ctx, teamID, reqUserID, permissions.RolesManage)
 	if err != nil {
-		log.Fatal("Error checking member existence")
 		return nil, err
 	}
 
-	if !team {
-		log.Fatal("Team does not exists, enter correct team")
-		return nil, ErrNotFound
+	txs, err := ts.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
 	}
 
-	//query team_members to check role of requesting userID
-	role, err := ts.GetRoleForUser(ctx, teamID, userID)
+	defer txs.Rollback()
+
+	currentRole, otherCoaches, err := ts.lockRosterRoles(ctx, txs, teamID, targetUserID)
 	if err != nil {
-		log.Fatal("Error getting user roles")
 		return nil, err
 	}
 
-	isAuthorized := role == "coach" || role == "manager"
-	if !isAuthorized {
+	//handing out or taking away the coach role, or any custom role, needs roles.manage
+	if (permissions.GuardedRole(currentRole) || permissions.GuardedRole(req.Role)) && !canManageRoles {
 		return nil, ErrForbidden
 	}
 
-	rowsAffected, err := ts.UpdateMemberRole(ctx, userID, teamID, req.Role)
+	if currentRole == permissions.RoleCoach && req.Role != permissions.RoleCoach && otherCoaches == 0 {
+		return nil, ErrLastCoach
+	}
+
+	rowsAffected, err := ts.UpdateMemberRole(ctx, txs, targetUserID, teamID, req.Role)
 	if err != nil {
 		return nil, err
 	}
@@ -609,69 +772,147 @@ func (ts *TeamService) UpdateTeamMembersRoles(ctx context.Context, userID uuid.U
 		return nil, ErrNotFound
 	}
 
+	var joinedAt time.Time
+	if err := txs.QueryRowContext(ctx, `SELECT joinedat FROM team_members WHERE team_id=$1 AND user_id=$2`, teamID, targetUserID).Scan(&joinedAt); err != nil {
+		return nil, err
+	}
+
+	if err := ts.syncSeasonMember(ctx, txs, teamID, targetUserID, joinedAt); err != nil {
+		return nil, err
+	}
+
 	//commit transaction
 	if err := txs.Commit(); err != nil {
-		log.Fatalf("error committing the transaction")
 		return nil, err
 	}
 
-	return &models.TeamMembers{
-		TeamID:   teamID,
-		UserID:   userID,
-		Role:     req.Role,
-		Joinedat: time.Now(),
-	}, nil
+	ts.publishRosterChange(ctx, events.RosterRoleChanged, teamID, targetUserID, currentRole, req.Role, reqUserID)
+
+	return models.NewTeamMembers(teamID, targetUserID, req.Role, joinedAt), nil
 }
 
-func (ts *TeamService) RemoveTeamMember(ctx context.Context, UserID uuid.UUID, teamID uuid.UUID) (int64, error) {
+// repo service -> runs inside the roster transaction
+func (ts *TeamService) RemoveTeamMember(ctx context.Context, tx *sql.Tx, userID uuid.UUID, teamID uuid.UUID) (int64, error) {
 
-	query := `DELETE FROM team_members WHERE team_id=$1 AND user_id=2`
+	query := `DELETE FROM team_members WHERE team_id=$1 AND user_id=$2`
 
-	result, err := ts.db.ExecContext(ctx, query)
+	result, err := tx.ExecContext(ctx, query, teamID, userID)
 	if err != nil {
 		return 0, err
 	}
 	return result.RowsAffected()
 }
 
+// DELETE :: coach/manager removes a member, or a member leaves the team
 func (ts *TeamService) RemoveMember(ctx context.Context, reqUserID, userIDToRemove, teamID uuid.UUID) (*models.TeamMembers, error) {
 
-	if reqUserID == userIDToRemove {
-		log.Println("user attempted to remove themselves from the team")
-		//fmt.Errorf("cannot remove yourself from a team")
+	isLeaving := reqUserID == userIDToRemove
+
+	if err := ts.requireWritableTeam(ctx, teamID); err != nil {
+		return nil, err
+	}
+
+	canManageRoster, _, err := ts.HasPermission(ctx, teamID, reqUserID, permissions.RosterManage)
+	if err != nil {
+		return nil, err
+	}
+
+	isAuthorized := isLeaving || canManageRoster
+	if !isAuthorized {
+		return nil, ErrForbidden
+	}
+
+	canManageRoles, _, err := ts.HasPermission(ctx, teamID, reqUserID, permissions.RolesManage)
+	if err != nil {
+		return nil, err
 	}
 
 	txs, err := ts.db.BeginTx(ctx, nil)
 	if err != nil {
-		log.Fatal("failed to begin transaction")
 		return nil, err
 	}
 
 	defer txs.Rollback()
 
-	role, err := ts.GetRoleForUser(ctx, teamID, reqUserID)
+	currentRole, otherCoaches, err := ts.lockRosterRoles(ctx, txs, teamID, userIDToRemove)
 	if err != nil {
 		return nil, err
 	}
 
-	isAuthorized := role == "coach" || role == "manager"
-	if !isAuthorized {
+	//removing a coach or a member with a custom role needs roles.manage
+	if !isLeaving && permissions.GuardedRole(currentRole) && !canManageRoles {
 		return nil, ErrForbidden
 	}
 
-	rowsAffected, err := ts.RemoveTeamMember(ctx, userIDToRemove, teamID)
+	if currentRole == permissions.RoleCoach && otherCoaches == 0 {
+		return nil, ErrLastCoach
+	}
+
+	rowsAffected, err := ts.RemoveTeamMember(ctx, txs, userIDToRemove, teamID)
 	if err != nil {
-		log.Fatalf("error")
+		return nil, err
 	}
 
 	if rowsAffected == 0 {
 		return nil, ErrNotFound
 	}
 
+	if err := ts.closeSeasonMember(ctx, txs, teamID, userIDToRemove, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+
 	if err := txs.Commit(); err != nil {
-		log.Fatalf("error commitinng transaction")
 		return nil, err
 	}
 
-	return &models.TeamMembers{}, nil
+	changeType := events.RosterMemberRemoved
+	if isLeaving {
+		changeType = events.RosterMemberLeft
+	}
+	ts.publishRosterChange(ctx, changeType, teamID, userIDToRemove, currentRole, "", reqUserID)
+
+	return &models.TeamMembers{
+		TeamID: teamID,
+		UserID: userIDToRemove,
+		Role:   currentRole,
+	}, nil
+}
+
+// publishRosterChange is best effort, the roster change is already committed
+func (ts *TeamService) publishRosterChange(ctx context.Context, changeType string, teamID, userID uuid.UUID, previousRole, role string, changedBy uuid.UUID) {
+	event := events.TeamRosterChanged{
+		ChangeType:   changeType,
+		TeamID:       teamID,
+		UserID:       userID,
+		PreviousRole: previousRole,
+		Role:         role,
+		ChangedBy:    changedBy,
+	}
+	if err := ts.prod.PublishTeamUpdate(ctx, event); err != nil {
+		log.Printf("kafka error publishing %s: %s", events.TypeTeamRosterChanged, err)
+	}
+}
+
+// fetchProfiles is a best effort user-service lookup keyed by user id string,
+// callers fall back to bare ids when a profile is missing
+func (ts *TeamService) fetchProfiles(ctx context.Context, userIDs []uuid.UUID) map[string]*user_proto.UserProfile {
+	profiles := map[string]*user_proto.UserProfile{}
+	if len(userIDs) == 0 {
+		return profiles
+	}
+
+	ids := make([]string, 0, len(userIDs))
+	for _, userID := range userIDs {
+		ids = append(ids, userID.String())
+	}
+
+	profileRes, err := ts.userClient.GetUserProfiles(ctx, &user_proto.GetUserRequest{Userid: ids})
+	if err != nil {
+		log.Printf("could not fetch user profiles: %v", err)
+		return profiles
+	}
+	if profileRes.Profiles != nil {
+		profiles = profileRes.Profiles
+	}
+	return profiles
 }
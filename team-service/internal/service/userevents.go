@@ -0,0 +1,300 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github/wycliff-ochieng/internal/models"
+	"github/wycliff-ochieng/internal/permissions"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wycliff-ochieng/common_packages/events"
+)
+
+// userRosterChange is a roster event to publish once the user event transaction committed
+type userRosterChange struct {
+	changeType   string
+	teamID       uuid.UUID
+	previousRole string
+	role         string
+	userID       uuid.UUID
+}
+
+type coachMissing struct {
+	teamID         uuid.UUID
+	organizationID uuid.UUID
+}
+
+// userMembership is one team of the user, locked for the length of the transaction
+type userMembership struct {
+	teamID         uuid.UUID
+	organizationID uuid.UUID
+	role           string
+	status         string
+}
+
+// markUserEventProcessed records the event key, false means it was applied before
+func (ts *TeamService) markUserEventProcessed(ctx context.Context, tx *sql.Tx, eventKey string, eventType string, userID uuid.UUID) (bool, error) {
+	query := `INSERT INTO processed_user_events(event_key,event_type,user_id) VALUES($1,$2,$3) ON CONFLICT (event_key) DO NOTHING`
+	result, err := tx.ExecContext(ctx, query, eventKey, eventType, userID)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (ts *TeamService) lockUserMemberships(ctx context.Context, tx *sql.Tx, userID uuid.UUID) ([]userMembership, error) {
+	query := `SELECT tm.team_id,t.organization_id,tm.role,tm.status FROM team_members tm JOIN teams t ON t.id = tm.team_id
+	WHERE tm.user_id=$1 ORDER BY tm.team_id FOR UPDATE OF tm`
+	rows, err := tx.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	memberships := make([]userMembership, 0)
+	for rows.Next() {
+		var m userMembership
+		if err := rows.Scan(&m.teamID, &m.organizationID, &m.role, &m.status); err != nil {
+			return nil, err
+		}
+		memberships = append(memberships, m)
+	}
+	return memberships, rows.Err()
+}
+
+// hasOtherActiveCoach locks the team's coach rows so two deletions cannot both skip the reassignment
+func (ts *TeamService) hasOtherActiveCoach(ctx context.Context, tx *sql.Tx, teamID uuid.UUID, userID uuid.UUID) (bool, error) {
+	query := `SELECT user_id FROM team_members WHERE team_id=$1 AND role=$2 AND status=$3 AND user_id<>$4 FOR UPDATE`
+	rows, err := tx.QueryContext(ctx, query, teamID, permissions.RoleCoach, models.MemberStatusActive, userID)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	found := rows.Next()
+	return found, rows.Err()
+}
+
+// promoteSuccessor makes the longest serving active manager the coach, uuid.Nil when there is none
+func (ts *TeamService) promoteSuccessor(ctx context.Context, tx *sql.Tx, teamID uuid.UUID, at time.Time) (uuid.UUID, error) {
+	var successor uuid.UUID
+	query := `SELECT user_id FROM team_members WHERE team_id=$1 AND role=$2 AND status=$3
+	ORDER BY joinedat NULLS LAST, user_id LIMIT 1 FOR UPDATE`
+	err := tx.QueryRowContext(ctx, query, teamID, permissions.RoleManager, models.MemberStatusActive).Scan(&successor)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return uuid.Nil, nil
+		}
+		return uuid.Nil, err
+	}
+
+	if _, err := ts.UpdateMemberRole(ctx, tx, successor, teamID, permissions.RoleCoach); err != nil {
+		return uuid.Nil, err
+	}
+	if err := ts.syncSeasonMember(ctx, tx, teamID, successor, at); err != nil {
+		return uuid.Nil, err
+	}
+	return successor, nil
+}
+
+// ApplyUserDeleted removes a deleted account from every roster. When it was the last active coach
+// the longest serving manager takes over, otherwise the team is left flagged as needing a coach
+func (ts *TeamService) ApplyUserDeleted(ctx context.Context, eventKey string, eventType string, userID uuid.UUID) error {
+
+	txs, err := ts.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer txs.Rollback()
+
+	fresh, err := ts.markUserEventProcessed(ctx, txs, eventKey, eventType, userID)
+	if err != nil {
+		return err
+	}
+	if !fresh {
+		log.Printf("user event %s already applied, skipping", eventKey)
+		return nil
+	}
+
+	memberships, err := ts.lockUserMemberships(ctx, txs, userID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	var changes []userRosterChange
+	var missing []coachMissing
+
+	for _, m := range memberships {
+		if m.role == permissions.RoleCoach && m.status == models.MemberStatusActive {
+			otherCoach, err := ts.hasOtherActiveCoach(ctx, txs, m.teamID, userID)
+			if err != nil {
+				return err
+			}
+			if !otherCoach {
+				successor, err := ts.promoteSuccessor(ctx, txs, m.teamID, now)
+				if err != nil {
+					return err
+				}
+				if successor != uuid.Nil {
+					changes = append(changes, userRosterChange{events.RosterCoachReassigned, m.teamID, permissions.RoleManager, permissions.RoleCoach, successor})
+				} else {
+					missing = append(missing, coachMissing{m.teamID, m.organizationID})
+				}
+			}
+		}
+
+		if _, err := ts.RemoveTeamMember(ctx, txs, userID, m.teamID); err != nil {
+			return err
+		}
+		if err := ts.closeSeasonMember(ctx, txs, m.teamID, userID, now); err != nil {
+			return err
+		}
+		changes = append(changes, userRosterChange{events.RosterAccountDeleted, m.teamID, m.role, "", userID})
+	}
+
+	//nothing left for the account to act on
+	cleanup := []string{
+		`UPDATE team_join_requests SET status='CANCELLED', decided_at=NOW() WHERE user_id=$1 AND status='PENDING'`,
+		`UPDATE team_invites SET status='REVOKED', updatedat=NOW() WHERE invitee_user_id=$1 AND status='PENDING'`,
+		`DELETE FROM organization_admins WHERE user_id=$1`,
+	}
+	for _, query := range cleanup {
+		if _, err := txs.ExecContext(ctx, query, userID); err != nil {
+			return err
+		}
+	}
+
+	if err := txs.Commit(); err != nil {
+		return err
+	}
+
+	ts.publishUserEventChanges(ctx, changes, missing, userID, events.CoachMissingAccountDeleted)
+	return nil
+}
+
+// ApplyUserSuspended marks the user INACTIVE on every team (or ACTIVE again when suspended is false),
+// the memberships and roles are kept so a reactivation restores them as they were
+func (ts *TeamService) ApplyUserSuspended(ctx context.Context, eventKey string, eventType string, userID uuid.UUID, suspended bool) error {
+
+	txs, err := ts.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer txs.Rollback()
+
+	fresh, err := ts.markUserEventProcessed(ctx, txs, eventKey, eventType, userID)
+	if err != nil {
+		return err
+	}
+	if !fresh {
+		log.Printf("user event %s already applied, skipping", eventKey)
+		return nil
+	}
+
+	memberships, err := ts.lockUserMemberships(ctx, txs, userID)
+	if err != nil {
+		return err
+	}
+
+	status := models.MemberStatusActive
+	changeType := events.RosterMemberReactivated
+	var inactiveSince *time.Time
+	if suspended {
+		now := time.Now().UTC()
+		status = models.MemberStatusInactive
+		changeType = events.RosterMemberSuspended
+		inactiveSince = &now
+	}
+
+	var changes []userRosterChange
+	var missing []coachMissing
+
+	for _, m := range memberships {
+		if m.status == status {
+			continue
+		}
+
+		if suspended && m.role == permissions.RoleCoach {
+			otherCoach, err := ts.hasOtherActiveCoach(ctx, txs, m.teamID, userID)
+			if err != nil {
+				return err
+			}
+			//suspensions can be lifted, so the coach is not replaced, only flagged
+			if !otherCoach {
+				missing = append(missing, coachMissing{m.teamID, m.organizationID})
+			}
+		}
+
+		query := `UPDATE team_members SET status=$1, inactive_since=$2 WHERE team_id=$3 AND user_id=$4`
+		if _, err := txs.ExecContext(ctx, query, status, inactiveSince, m.teamID, userID); err != nil {
+			return err
+		}
+		changes = append(changes, userRosterChange{changeType, m.teamID, m.role, m.role, userID})
+	}
+
+	if err := txs.Commit(); err != nil {
+		return err
+	}
+
+	ts.publishUserEventChanges(ctx, changes, missing, userID, events.CoachMissingSuspended)
+	return nil
+}
+
+// ApplyUserProfileUpdated links pending email invites to the user once their profile carries that email,
+// names and emails are otherwise read from user-service on demand
+func (ts *TeamService) ApplyUserProfileUpdated(ctx context.Context, eventKey string, eventType string, userID uuid.UUID, email string) error {
+
+	txs, err := ts.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer txs.Rollback()
+
+	fresh, err := ts.markUserEventProcessed(ctx, txs, eventKey, eventType, userID)
+	if err != nil {
+		return err
+	}
+	if !fresh {
+		log.Printf("user event %s already applied, skipping", eventKey)
+		return nil
+	}
+
+	if email != "" {
+		query := `UPDATE team_invites SET invitee_user_id=$1, updatedat=NOW()
+		WHERE status='PENDING' AND invitee_user_id IS NULL AND LOWER(invitee_email)=LOWER($2)`
+		if _, err := txs.ExecContext(ctx, query, userID, email); err != nil {
+			return err
+		}
+	}
+
+	return txs.Commit()
+}
+
+func (ts *TeamService) publishUserEventChanges(ctx context.Context, changes []userRosterChange, missing []coachMissing, userID uuid.UUID, reason string) {
+	for _, c := range changes {
+		ts.publishRosterChange(ctx, c.changeType, c.teamID, c.userID, c.previousRole, c.role, uuid.Nil)
+	}
+
+	for _, m := range missing {
+		event := events.TeamCoachMissing{
+			TeamID:         m.teamID,
+			OrganizationID: m.organizationID,
+			UserID:         userID,
+			Reason:         reason,
+		}
+		if err := ts.prod.PublishTeamUpdate(ctx, event); err != nil {
+			log.Printf("kafka error publishing %s: %s", events.TypeTeamCoachMissing, err)
+		}
+	}
+}
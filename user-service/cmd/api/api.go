@@ -99,6 +99,11 @@ func (s *APIServer) Run() {
 
 	updateUser := router.Methods("PUT").Subrouter()
 	updateUser.HandleFunc("/update", uh.UpdateUserProfile)
+	updateUser.HandleFunc("/admin/users/{user_id}/suspend", uh.SuspendUser)
+	updateUser.HandleFunc("/admin/users/{user_id}/reactivate", uh.ReactivateUser)
+
+	deleteUser := router.Methods("DELETE").Subrouter()
+	deleteUser.HandleFunc("/profile/delete", uh.DeleteUserProfile)
 
 	//gRPC server configuration
 	gRPCAddress := "50051"
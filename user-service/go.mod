@@ -16,6 +16,8 @@ require (
 
 require github.com/wycliff-ochieng/sports-common-package v0.1.0
 
+require github.com/wycliff-ochieng/common_packages v0.0.0-00010101000000-000000000000
+
 require (
 	github.com/felixge/httpsnoop v1.0.4 // indirect
 	github.com/gorilla/handlers v1.5.2 // indirect
@@ -29,3 +31,5 @@ require (
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20250707201910-8d1bb00bc6a7 // indirect
 	google.golang.org/protobuf v1.36.9 // indirect
 )
+
+replace github.com/wycliff-ochieng/common_packages => ../common_packages
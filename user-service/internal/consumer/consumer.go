@@ -2,22 +2,14 @@ package consumer
 
 import (
 	"context"
-	"encoding/json"
 	"log"
 	"time"
 
 	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
-	"github.com/google/uuid"
+	"github.com/wycliff-ochieng/common_packages/events"
 	"github.com/wycliff-ochieng/internal/service"
 )
 
-type UserEventCreated struct {
-	UserID    uuid.UUID `json:"userId"`
-	FirstName string    `json:"firstname"`
-	LastName  string    `json:"lastname"`
-	Email     string    `json:"email"`
-}
-
 type UserEventConsumer struct {
 	l        *log.Logger
 	u        *service.UserService
@@ -67,9 +59,9 @@ func (c *UserEventConsumer) StartEventConsumer(ctx context.Context, topic string
 				c.l.Printf("consumed messge from topic %s [%d] at offset %v", *e.TopicPartition.Topic, e.TopicPartition.Partition, e.TopicPartition.Offset)
 				log.Printf("RAW MESSAGE CONSU?MED: %s", string(e.Value))
 
-				var event UserEventCreated
-
-				if err := json.Unmarshal(e.Value, &event); err != nil {
+				//envelope and payload are validated against the shared contract
+				env, payload, err := events.Unmarshal(e.Value)
+				if err != nil {
 					c.l.Printf("error decoding event data:%v", err)
 
 					c.consumer.CommitMessage(e)
@@ -77,11 +69,18 @@ func (c *UserEventConsumer) StartEventConsumer(ctx context.Context, topic string
 					continue
 				}
 
+				event, ok := payload.(*events.UserCreated)
+				if !ok {
+					c.l.Printf("ignoring %s event %s", env.Type, env.ID)
+					c.consumer.CommitMessage(e)
+					continue
+				}
+
 				log.Printf("MESSSAGE AFTERR UNMARSHALING: %+v", event)
 				//call database /create profile service
 				//database operation context
 				opCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
-				err := c.u.CreateUserProfile(opCtx, event.UserID, event.FirstName, event.LastName, event.Email)
+				err = c.u.CreateUserProfile(opCtx, event.UserID, event.FirstName, event.LastName, event.Email)
 				if err != nil {
 					log.Printf("Error creating userprofile: %v", err)
 				}
@@ -0,0 +1,6 @@
+-- +goose Up
+-- ACTIVE or SUSPENDED, set by platform admins
+ALTER TABLE user_profiles ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'ACTIVE';
+
+-- +goose Down
+ALTER TABLE user_profiles DROP COLUMN IF EXISTS status;
@@ -3,10 +3,14 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
 	"time"
 
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
 	//"github.com/aws/aws-sdk-go-v2/aws/middleware/private/metrics/middleware"
 
 	"github.com/wycliff-ochieng/internal/service"
@@ -78,3 +82,75 @@ func (u *UserHandler) UpdateUserProfile(w http.ResponseWriter, r *http.Request)
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(&profile)
 }
+
+// the platform role allowed to suspend and reactivate users
+const adminRole = "admin"
+
+func (u *UserHandler) DeleteUserProfile(w http.ResponseWriter, r *http.Request) {
+	u.l.Println(">>>deleting user profile")
+
+	userUUID, err := middleware.GetUserUUIDFromContext(r.Context())
+	if err != nil {
+		http.Error(w, "cant get user UUID from context", http.StatusUnauthorized)
+		return
+	}
+	userID, err := uuid.Parse(userUUID)
+	if err != nil {
+		http.Error(w, "invalid user UUID in token", http.StatusUnauthorized)
+		return
+	}
+
+	if err := u.p.DeleteUserProfile(r.Context(), userID); err != nil {
+		u.l.Printf("error deleting profile: %v", err)
+		if errors.Is(err, service.ErrNotFound) {
+			http.Error(w, "profile not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Error deleting user profile", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (u *UserHandler) SuspendUser(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	if r.ContentLength > 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "ERROR:unmarshaling data", http.StatusBadRequest)
+			return
+		}
+	}
+	u.setSuspended(w, r, true, req.Reason)
+}
+
+func (u *UserHandler) ReactivateUser(w http.ResponseWriter, r *http.Request) {
+	u.setSuspended(w, r, false, "")
+}
+
+func (u *UserHandler) setSuspended(w http.ResponseWriter, r *http.Request, suspended bool, reason string) {
+	if !middleware.HasRole(r.Context(), adminRole) {
+		http.Error(w, "only admins can suspend or reactivate users", http.StatusForbidden)
+		return
+	}
+
+	userID, err := uuid.Parse(mux.Vars(r)["user_id"])
+	if err != nil {
+		http.Error(w, "invalid user_id", http.StatusBadRequest)
+		return
+	}
+
+	if err := u.p.SetUserSuspended(r.Context(), userID, suspended, reason); err != nil {
+		u.l.Printf("error changing suspension of %s: %v", userID, err)
+		if errors.Is(err, service.ErrNotFound) {
+			http.Error(w, "profile not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Error updating user status", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -6,6 +6,12 @@ import (
 	"github.com/google/uuid"
 )
 
+// profile statuses, a suspended user stays on their teams but loses their team permissions
+const (
+	ProfileStatusActive    = "ACTIVE"
+	ProfileStatusSuspended = "SUSPENDED"
+)
+
 type Profile struct {
 	UserID    uuid.UUID `json:"userid"`
 	Firstname string    `json:"fullname"`
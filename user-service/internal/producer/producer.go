@@ -2,16 +2,19 @@ package internal
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
 	"os"
 
 	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/wycliff-ochieng/common_packages/events"
 )
 
+// ProducerName is stamped on the envelope of every event this service publishes
+const ProducerName = "user-service"
+
 type KafkaProducer interface {
-	PublishUserUpdate(ctx context.Context, userData interface{}) error
+	PublishUserUpdate(ctx context.Context, event events.Payload) error
 }
 
 type UpdateUser struct {
@@ -28,11 +31,12 @@ func NewUpdateUser(p *kafka.Producer, topic string) *UpdateUser {
 	}
 }
 
-func (c *UpdateUser) PublishUserUpdate(ctx context.Context, userData interface{}) error {
+// PublishUserUpdate validates the event and publishes it wrapped in the shared envelope
+func (c *UpdateUser) PublishUserUpdate(ctx context.Context, event events.Payload) error {
 
-	data, err := json.Marshal(userData)
+	data, err := events.Marshal(ProducerName, event)
 	if err != nil {
-		return fmt.Errorf("failed to marshal data: %s", err)
+		return fmt.Errorf("failed to marshal %s: %w", event.EventType(), err)
 	}
 
 	err = c.producer.Produce(&kafka.Message{
@@ -42,9 +46,11 @@ func (c *UpdateUser) PublishUserUpdate(ctx context.Context, userData interface{}
 		},
 		Value: data,
 	}, c.deliverych)
-	log.Println(">>successfully published event to the queue")
-	fmt.Println("Published updateuserprofile event onto the queue")
-	return fmt.Errorf("failed to publish user creation data: %s", err)
+	if err != nil {
+		return fmt.Errorf("failed to publish %s: %w", event.EventType(), err)
+	}
+	log.Printf(">>successfully published %s to the queue", event.EventType())
+	return nil
 }
 
 func InitKafkaProducer() (*kafka.Producer, error) {
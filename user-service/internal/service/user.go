@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/wycliff-ochieng/common_packages/events"
 	"github.com/wycliff-ochieng/internal/database"
 	"github.com/wycliff-ochieng/internal/models"
 	internal "github.com/wycliff-ochieng/internal/producer"
@@ -20,6 +21,8 @@ type Profile interface {
 	GetProfileByID(ctx context.Context, tx *sql.Tx, userID int) (*models.Profile, error)
 	GetUserProfileByUUID(ctx context.Context, userID string) (*models.Profile, error)
 	UpdateUserProfile(ctx context.Context, userID uuid.UUID, firstname, lastname, email string, updatedat time.Time) (*models.Profile, error)
+	DeleteUserProfile(ctx context.Context, userID uuid.UUID) error
+	SetUserSuspended(ctx context.Context, userID uuid.UUID, suspended bool, reason string) error
 }
 
 type UserService struct {
@@ -131,7 +134,16 @@ func (u *UserService) UpdateUserProfile(ctx context.Context, userID uuid.UUID, f
 	//event := u.UpdateUserProfile()
 
 	//Side effects publish event(Implementing kafka producer)
-	err = u.p.PublishUserUpdate(ctx, updateReq.UserID)
+	if email == "" {
+		email = existingProfile.Email
+	}
+	event := events.UserProfileUpdated{
+		UserID:    userID,
+		FirstName: firstname,
+		LastName:  lastname,
+		Email:     email,
+	}
+	err = u.p.PublishUserUpdate(ctx, event)
 	if err != nil {
 		u.l.Printf("error publishing update event to producer:%v", err)
 		return nil, err
@@ -248,3 +260,62 @@ func (u *UserService) GetUserProfilesByUUIDs(ctx context.Context, userUUID []str
 	}
 	return profiles, nil
 }
+
+// DeleteUserProfile deletes the caller's profile and publishes UserDeleted, team-service takes the
+// user off every roster
+func (u *UserService) DeleteUserProfile(ctx context.Context, userID uuid.UUID) error {
+
+	result, err := u.db.ExecContext(ctx, `DELETE FROM user_profiles WHERE userid=$1`, userID)
+	if err != nil {
+		return fmt.Errorf("error deleting profile: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+
+	if err := u.p.PublishUserUpdate(ctx, events.UserDeleted{UserID: userID}); err != nil {
+		u.l.Printf("error publishing %s for %s: %v", events.TypeUserDeleted, userID, err)
+		return err
+	}
+	return nil
+}
+
+// SetUserSuspended suspends or reactivates a user and publishes UserSuspended or UserReactivated.
+// A user already in the requested state is left alone and nothing is published
+func (u *UserService) SetUserSuspended(ctx context.Context, userID uuid.UUID, suspended bool, reason string) error {
+
+	from, to := models.ProfileStatusActive, models.ProfileStatusSuspended
+	if !suspended {
+		from, to = to, from
+	}
+
+	query := `UPDATE user_profiles SET status=$1,updatedat=NOW() WHERE userid=$2 AND status=$3`
+	result, err := u.db.ExecContext(ctx, query, to, userID, from)
+	if err != nil {
+		return fmt.Errorf("error updating profile status: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		var exists bool
+		if err := u.db.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM user_profiles WHERE userid=$1)`, userID).Scan(&exists); err != nil {
+			return err
+		}
+		if !exists {
+			return ErrNotFound
+		}
+		return nil
+	}
+
+	var event events.Payload = events.UserReactivated{UserID: userID}
+	if suspended {
+		event = events.UserSuspended{UserID: userID, Reason: reason}
+	}
+	if err := u.p.PublishUserUpdate(ctx, event); err != nil {
+		u.l.Printf("error publishing %s for %s: %v", event.EventType(), userID, err)
+		return err
+	}
+	return nil
+}
@@ -53,8 +53,8 @@ func UserMiddlware(jwtSecret string) func(next http.Handler) http.Handler {
 			//Extract claims (the populate context)
 			if claims, ok := token.Claims.(*Claims); ok {
 				ctx := context.WithValue(r.Context(), UserUUIDKey, claims.UserUUID)
-				ctx = context.WithValue(r.Context(), RolesdKey, claims.Roles)
-				ctx = context.WithValue(r.Context(), UserIDKey, claims.UserID)
+				ctx = context.WithValue(ctx, RolesdKey, claims.Roles)
+				ctx = context.WithValue(ctx, UserIDKey, claims.UserID)
 				next.ServeHTTP(w, r.WithContext(ctx))
 			} else {
 				http.Error(w, "could not parse token claims", http.StatusFailedDependency)
@@ -78,3 +78,14 @@ func GetUserIdFromcontext(ctx context.Context) (int, error) {
 	}
 	return userID, nil
 }
+
+// HasRole reports whether the token carried the given platform role
+func HasRole(ctx context.Context, role string) bool {
+	roles, _ := ctx.Value(RolesdKey).([]string)
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}